@@ -3,10 +3,21 @@ package main
 import (
 	"blog-service/internal/handlers"
 	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/internal/workers"
+	"blog-service/pkg/botdetect"
 	"blog-service/pkg/database"
+	"blog-service/pkg/engagement"
 	"blog-service/pkg/logger"
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -51,6 +62,78 @@ func main() {
 		log.Fatal("Failed to initialize database:", err)
 	}
 
+	// Run migrations for tables owned by this service
+	if getEnv("DB_AUTO_MIGRATE", "true") == "true" {
+		if err := database.AutoMigrate(
+			&models.Category{},
+			&models.Tag{},
+			&models.Blog{},
+			&models.BlogStatusTransition{},
+			&models.BlogRevision{},
+			&models.BlogSlugRedirect{},
+			&models.BlogSEOAnalysis{},
+			&models.BlogLead{},
+			&models.LeadActivity{},
+			&models.LeadTouchpoint{},
+			&models.LeadMerge{},
+			&models.LeadAssignmentRule{},
+			&models.VisitorEvent{},
+			&models.BlogEngagementDaily{},
+			&models.BlogLike{},
+			&models.BotHit{},
+			&models.BlogDailyMetrics{},
+		); err != nil {
+			log.Fatal("Failed to run database migrations:", err)
+		}
+	}
+
+	// Start background workers
+	var publishScheduler *workers.PublishScheduler
+	if getEnv("ENABLE_PUBLISHING_WORKFLOW", "true") == "true" {
+		interval, err := time.ParseDuration(getEnv("PUBLISH_SCHEDULER_INTERVAL", "1m"))
+		if err != nil {
+			interval = time.Minute
+		}
+		publishScheduler = workers.NewPublishScheduler(interval)
+		publishScheduler.Start()
+	}
+
+	// Pixel and beacon hits are buffered in memory and written in batches
+	engagementBuffer := engagement.NewBuffer(getEnvInt("ENGAGEMENT_BUFFER_MAX_BLOGS", 10000), getEnvInt("ENGAGEMENT_FLUSH_THRESHOLD", 1000))
+	flushInterval, err := time.ParseDuration(getEnv("ENGAGEMENT_FLUSH_INTERVAL", "10s"))
+	if err != nil {
+		flushInterval = 10 * time.Second
+	}
+	engagementFlusher := workers.NewEngagementFlusher(engagementBuffer, flushInterval)
+	engagementFlusher.Start()
+
+	// Crawlers, monitors and scripts are kept out of the engagement counters
+	rateWindow, err := time.ParseDuration(getEnv("BOT_RATE_WINDOW", "1m"))
+	if err != nil {
+		rateWindow = time.Minute
+	}
+	botClassifier, invalidIPs := botdetect.NewClassifier(
+		strings.Split(os.Getenv("BOT_MONITORING_IPS"), ","),
+		getEnvInt("BOT_RATE_LIMIT", 300),
+		rateWindow,
+	)
+	if len(invalidIPs) > 0 {
+		log.Printf("Ignoring invalid BOT_MONITORING_IPS entries: %s", strings.Join(invalidIPs, ", "))
+	}
+	botHits := botdetect.NewHitLog(getEnvInt("BOT_HIT_BUFFER_SIZE", 10000))
+	botHitRetention := time.Duration(getEnvInt("BOT_HIT_RETENTION_DAYS", 90)) * 24 * time.Hour
+	botHitFlusher := workers.NewBotHitFlusher(botHits, 30*time.Second, botHitRetention)
+	botHitFlusher.Start()
+	botFilter := middleware.BotFilter(botClassifier, botHits)
+
+	// Analytics read daily rollups, rebuilt for the days that changed since the last run
+	rollupInterval, err := time.ParseDuration(getEnv("ANALYTICS_ROLLUP_INTERVAL", "5m"))
+	if err != nil {
+		rollupInterval = 5 * time.Minute
+	}
+	metricsRollup := workers.NewMetricsRollup(rollupInterval)
+	metricsRollup.Start()
+
 	// Initialize Gin router
 	if os.Getenv("GIN_MODE") == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -58,6 +141,23 @@ func main() {
 
 	router := gin.New()
 
+	// Client IPs (bot detection, rate limits, lead capture) come from
+	// X-Forwarded-For only when the request arrives through a trusted proxy.
+	// A proxy request without the header would carry the proxy's own address,
+	// so proxies cannot also be monitoring addresses.
+	trustedProxies := []string{}
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", "127.0.0.1,::1"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+	if overlaps := botClassifier.MonitoringOverlaps(trustedProxies); len(overlaps) > 0 {
+		log.Fatalf("TRUSTED_PROXIES entries overlap BOT_MONITORING_IPS: %s", strings.Join(overlaps, ", "))
+	}
+
 	// Add essential middleware
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
@@ -65,6 +165,14 @@ func main() {
 
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler()
+	blogHandler := handlers.NewBlogHandler()
+	categoryHandler := handlers.NewCategoryHandler()
+	tagHandler := handlers.NewTagHandler()
+	leadHandler := handlers.NewLeadHandler()
+	assignmentRuleHandler := handlers.NewAssignmentRuleHandler()
+	trackingHandler := handlers.NewTrackingHandler()
+	engagementHandler := handlers.NewEngagementHandler(engagementBuffer)
+	analyticsHandler := handlers.NewAnalyticsHandler(metricsRollup)
 
 	// ===== HEALTH CHECK ENDPOINTS =====
 	router.GET("/health", healthHandler.SimpleHealthCheck)
@@ -91,6 +199,109 @@ func main() {
 				},
 			})
 		})
+
+		// Blog management endpoints
+		blogs := api.Group("/blogs")
+		{
+			blogs.GET("", middleware.OptionalAuthMiddleware(), blogHandler.ListBlogs)
+			blogs.GET("/:id", middleware.OptionalAuthMiddleware(), blogHandler.GetBlog)
+			blogs.GET("/slug/:slug", middleware.OptionalAuthMiddleware(), blogHandler.GetBlogBySlug)
+			blogs.GET("/search", middleware.OptionalAuthMiddleware(), blogHandler.SearchBlogs)
+			blogs.POST("", middleware.AuthMiddleware(), middleware.RequirePermission("blog:create"), blogHandler.CreateBlog)
+			blogs.PUT("/:id", middleware.AuthMiddleware(), middleware.RequirePermission("blog:update"), blogHandler.UpdateBlog)
+			blogs.DELETE("/:id", middleware.AuthMiddleware(), middleware.RequirePermission("blog:delete"), blogHandler.DeleteBlog)
+
+			// Editorial workflow
+			blogs.GET("/:id/transitions", middleware.AuthMiddleware(), middleware.RequirePermission("blog:read"), blogHandler.ListTransitions)
+			blogs.POST("/:id/transitions", middleware.AuthMiddleware(), blogHandler.TransitionBlog)
+
+			// Content analysis
+			blogs.GET("/:id/seo", middleware.AuthMiddleware(), middleware.RequirePermission("blog:read"), blogHandler.PreviewBlogSEO)
+			blogs.POST("/:id/seo", middleware.AuthMiddleware(), middleware.RequirePermission("blog:update"), blogHandler.AnalyzeBlogSEO)
+			blogs.GET("/:id/seo/history", middleware.AuthMiddleware(), middleware.RequirePermission("blog:read"), blogHandler.SEOHistory)
+
+			// Revision history
+			blogs.GET("/:id/revisions", middleware.AuthMiddleware(), middleware.RequirePermission("blog:read"), blogHandler.ListRevisions)
+			blogs.GET("/:id/revisions/diff", middleware.AuthMiddleware(), middleware.RequirePermission("blog:read"), blogHandler.DiffRevisions)
+			blogs.POST("/:id/revisions/:revision/restore", middleware.AuthMiddleware(), middleware.RequirePermission("blog:update"), blogHandler.RestoreRevision)
+
+			// Lead capture (public, embedded in published posts)
+			blogs.POST("/:id/leads", leadHandler.CaptureLead)
+		}
+
+		// Lead management endpoints
+		leads := api.Group("/leads")
+		{
+			leads.GET("", middleware.AuthMiddleware(), middleware.RequirePermission("lead:read"), leadHandler.ListLeads)
+			leads.GET("/analytics", middleware.AuthMiddleware(), middleware.RequirePermission("lead:read"), leadHandler.GetLeadAnalytics)
+			leads.POST("/attribution/rerun", middleware.AuthMiddleware(), middleware.RequirePermission("lead:attribution"), leadHandler.RerunAttribution)
+			leads.POST("/privacy/export", middleware.AuthMiddleware(), middleware.RequirePermission("lead:privacy"), leadHandler.ExportLeadData)
+			leads.POST("/privacy/erase", middleware.AuthMiddleware(), middleware.RequirePermission("lead:privacy"), leadHandler.EraseLeadData)
+			leads.GET("/:id", middleware.AuthMiddleware(), middleware.RequirePermission("lead:read"), leadHandler.GetLead)
+			leads.POST("/:id/qualify", middleware.AuthMiddleware(), middleware.RequirePermission("lead:update"), leadHandler.QualifyLead)
+			leads.PUT("/:id/status", middleware.AuthMiddleware(), middleware.RequirePermission("lead:update"), leadHandler.UpdateLeadStatus)
+			leads.GET("/:id/transitions", middleware.AuthMiddleware(), middleware.RequirePermission("lead:read"), leadHandler.ListLeadTransitions)
+			leads.POST("/:id/merge", middleware.AuthMiddleware(), middleware.RequirePermission("lead:merge"), leadHandler.MergeLeads)
+		}
+
+		// Signed opt-out link from emails to leads (public; GET shows a confirm page, POST opts out and serves one-click unsubscribe)
+		api.GET("/privacy/opt-out", leadHandler.OptOut)
+		api.POST("/privacy/opt-out", leadHandler.OptOut)
+
+		// Signed consent confirmation link sent to opted-out or quarantined leads (public)
+		api.GET("/privacy/confirm-consent", leadHandler.ConfirmConsent)
+		api.POST("/privacy/confirm-consent", leadHandler.ConfirmConsent)
+
+		// Anonymous visitor tracking (public, first-party cookie)
+		api.POST("/track", botFilter, trackingHandler.TrackEvent)
+
+		// View, scroll, like and share counters (public)
+		api.GET("/pixel.gif", botFilter, engagementHandler.Pixel)
+		api.POST("/beacon", botFilter, engagementHandler.Beacon)
+
+		// Touchpoint ingestion for known leads (lead token, or lead_id with lead:update)
+		touchpoints := api.Group("/touchpoints")
+		{
+			touchpoints.POST("", middleware.OptionalAuthMiddleware(), botFilter, leadHandler.RecordTouchpoint)
+			touchpoints.POST("/batch", middleware.OptionalAuthMiddleware(), botFilter, leadHandler.RecordTouchpointBatch)
+		}
+
+		// Blog performance analytics
+		analytics := api.Group("/analytics")
+		{
+			analytics.GET("/dashboard", middleware.AuthMiddleware(), middleware.RequirePermission("analytics:read"), analyticsHandler.GetDashboard)
+			analytics.GET("/blogs/:id", middleware.AuthMiddleware(), middleware.RequirePermission("analytics:read"), analyticsHandler.GetBlogMetrics)
+			analytics.POST("/rollups/backfill", middleware.AuthMiddleware(), middleware.RequirePermission("analytics:manage"), analyticsHandler.BackfillRollups)
+		}
+
+		// Lead assignment rules
+		assignmentRules := api.Group("/assignment-rules")
+		{
+			assignmentRules.GET("", middleware.AuthMiddleware(), middleware.RequirePermission("lead:assign"), assignmentRuleHandler.ListRules)
+			assignmentRules.GET("/:id", middleware.AuthMiddleware(), middleware.RequirePermission("lead:assign"), assignmentRuleHandler.GetRule)
+			assignmentRules.POST("", middleware.AuthMiddleware(), middleware.RequirePermission("lead:assign"), assignmentRuleHandler.CreateRule)
+			assignmentRules.PUT("/:id", middleware.AuthMiddleware(), middleware.RequirePermission("lead:assign"), assignmentRuleHandler.UpdateRule)
+			assignmentRules.DELETE("/:id", middleware.AuthMiddleware(), middleware.RequirePermission("lead:assign"), assignmentRuleHandler.DeleteRule)
+		}
+
+		// Taxonomy endpoints
+		categories := api.Group("/categories")
+		{
+			categories.GET("", middleware.OptionalAuthMiddleware(), categoryHandler.ListCategories)
+			categories.GET("/:id", middleware.OptionalAuthMiddleware(), categoryHandler.GetCategory)
+			categories.POST("", middleware.AuthMiddleware(), middleware.RequirePermission("category:manage"), categoryHandler.CreateCategory)
+			categories.PUT("/:id", middleware.AuthMiddleware(), middleware.RequirePermission("category:manage"), categoryHandler.UpdateCategory)
+			categories.DELETE("/:id", middleware.AuthMiddleware(), middleware.RequirePermission("category:manage"), categoryHandler.DeleteCategory)
+		}
+
+		tags := api.Group("/tags")
+		{
+			tags.GET("", middleware.OptionalAuthMiddleware(), tagHandler.ListTags)
+			tags.GET("/:id", middleware.OptionalAuthMiddleware(), tagHandler.GetTag)
+			tags.POST("", middleware.AuthMiddleware(), middleware.RequirePermission("tag:manage"), tagHandler.CreateTag)
+			tags.PUT("/:id", middleware.AuthMiddleware(), middleware.RequirePermission("tag:manage"), tagHandler.UpdateTag)
+			tags.DELETE("/:id", middleware.AuthMiddleware(), middleware.RequirePermission("tag:manage"), tagHandler.DeleteTag)
+		}
 	}
 
 	// Swagger documentation
@@ -115,12 +326,87 @@ func main() {
 	log.Printf("    GET  /metrics - System metrics")
 	log.Printf("  API ENDPOINTS:")
 	log.Printf("    GET  /api/v1/test - Test endpoint")
+	log.Printf("    GET  /api/v1/blogs - List blog posts")
+	log.Printf("    GET  /api/v1/blogs/search - Full-text search with facets")
+	log.Printf("    POST /api/v1/blogs - Create blog post (auth)")
+	log.Printf("    GET  /api/v1/blogs/:id - Get blog post")
+	log.Printf("    GET  /api/v1/blogs/slug/:slug - Get blog post by slug (301 for renamed slugs)")
+	log.Printf("    PUT  /api/v1/blogs/:id - Update blog post (auth)")
+	log.Printf("    DELETE /api/v1/blogs/:id - Delete blog post (auth)")
+	log.Printf("    GET  /api/v1/blogs/:id/transitions - Workflow status history (auth)")
+	log.Printf("    POST /api/v1/blogs/:id/transitions - Change workflow status (auth)")
+	log.Printf("    GET  /api/v1/blogs/:id/seo - SEO analysis preview of parsed content (auth)")
+	log.Printf("    POST /api/v1/blogs/:id/seo - Run and store SEO analysis (auth)")
+	log.Printf("    GET  /api/v1/blogs/:id/seo/history - SEO score history (auth)")
+	log.Printf("    GET  /api/v1/blogs/:id/revisions - Revision history (auth)")
+	log.Printf("    GET  /api/v1/blogs/:id/revisions/diff - Diff two revisions (auth)")
+	log.Printf("    POST /api/v1/blogs/:id/revisions/:revision/restore - Restore a revision (auth)")
+	log.Printf("    POST /api/v1/blogs/:id/leads - Capture a lead from a published post")
+	log.Printf("    GET  /api/v1/leads - List leads with filters (auth)")
+	log.Printf("    GET  /api/v1/leads/analytics - Lead funnel, source, quality and trend analytics (auth)")
+	log.Printf("    GET  /api/v1/leads/:id - Lead detail with activities and touchpoints (auth)")
+	log.Printf("    POST /api/v1/leads/:id/qualify - Qualify a lead (auth)")
+	log.Printf("    PUT  /api/v1/leads/:id/status - Update lead status (auth)")
+	log.Printf("    GET  /api/v1/leads/:id/transitions - Lead status history (auth)")
+	log.Printf("    POST /api/v1/leads/:id/merge - Merge a duplicate lead (admin)")
+	log.Printf("    POST /api/v1/leads/privacy/export - Export a data subject's lead data (admin)")
+	log.Printf("    POST /api/v1/leads/privacy/erase - Anonymize a data subject's lead data (admin)")
+	log.Printf("    GET/POST /api/v1/privacy/opt-out - Opt a lead out via signed link")
+	log.Printf("    GET/POST /api/v1/privacy/confirm-consent - Confirm consent via emailed signed link")
+	log.Printf("    POST /api/v1/leads/attribution/rerun - Recalculate revenue attribution (admin)")
+	log.Printf("    POST /api/v1/track - Record an anonymous visitor event")
+	log.Printf("    GET  /api/v1/pixel.gif - Tracking pixel for views, scroll, likes and shares")
+	log.Printf("    POST /api/v1/beacon - sendBeacon endpoint for engagement events")
+	log.Printf("    POST /api/v1/touchpoints - Record a touchpoint for a known lead")
+	log.Printf("    POST /api/v1/touchpoints/batch - Record up to 100 touchpoints")
+	log.Printf("    GET  /api/v1/analytics/dashboard - Blog performance dashboard (auth)")
+	log.Printf("    GET  /api/v1/analytics/blogs/:id - Per-blog metrics, comparison and trend (auth)")
+	log.Printf("    POST /api/v1/analytics/rollups/backfill - Rebuild daily metric rollups for a date range (admin)")
+	log.Printf("    GET  /api/v1/assignment-rules - List lead assignment rules (auth)")
+	log.Printf("    POST/PUT/DELETE /api/v1/assignment-rules - Manage lead assignment rules (auth)")
+	log.Printf("    GET  /api/v1/categories - List categories (format=tree for nested)")
+	log.Printf("    GET  /api/v1/categories/:id - Get category with ancestors and children")
+	log.Printf("    POST/PUT/DELETE /api/v1/categories - Manage categories (auth)")
+	log.Printf("    GET  /api/v1/tags - List tags")
+	log.Printf("    GET  /api/v1/tags/:id - Get tag")
+	log.Printf("    POST/PUT/DELETE /api/v1/tags - Manage tags (auth)")
 	log.Printf("  DOCUMENTATION:")
 	log.Printf("    GET  /swagger/index.html - API Documentation (if enabled)")
 
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	server := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// On SIGINT or SIGTERM stop accepting requests, let in-flight ones finish,
+	// then stop the workers so buffered counters and bot hits are written
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+
+	shutdownTimeout, err := time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", "30s"))
+	if err != nil {
+		shutdownTimeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Server shutdown did not complete: %v", err)
+	}
+
+	if publishScheduler != nil {
+		publishScheduler.Stop()
 	}
+	metricsRollup.Stop()
+	engagementFlusher.Stop()
+	botHitFlusher.Stop()
+	log.Println("Server stopped")
 }
 
 func getEnv(key, defaultValue string) string {
@@ -129,3 +415,10 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
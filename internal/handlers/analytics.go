@@ -0,0 +1,957 @@
+package handlers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/internal/workers"
+	"blog-service/pkg/analytics"
+	"blog-service/pkg/database"
+	"blog-service/pkg/engagement"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultTopPerformers is the number of ranked posts unless ?limit= is given
+	defaultTopPerformers = 10
+	maxTopPerformers     = 50
+
+	// trendingGrowth is the period-over-period view growth, in percent, that marks a topic as trending
+	trendingGrowth = 20.0
+
+	// uncategorizedLabel groups posts without a category
+	uncategorizedLabel = "Uncategorized"
+)
+
+var performanceCalculator = analytics.NewPerformanceCalculator()
+
+// AnalyticsHandler serves blog performance analytics from the daily metric rollups
+type AnalyticsHandler struct {
+	rollup *workers.MetricsRollup
+}
+
+// NewAnalyticsHandler creates a new analytics handler backfilling through rollup
+func NewAnalyticsHandler(rollup *workers.MetricsRollup) *AnalyticsHandler {
+	return &AnalyticsHandler{rollup: rollup}
+}
+
+// blogPerformanceRow holds one post's aggregates over the requested window
+type blogPerformanceRow struct {
+	ID                 uint
+	Title              string
+	Slug               string
+	PublishedAt        *time.Time
+	CategoryID         *uint
+	CategoryName       string
+	AuthorID           uint
+	AuthorName         string
+	CommentsCount      int
+	SEOScore           float64
+	HasFeaturedImage   bool
+	HasFocusKeyword    bool
+	HasMetaDescription bool
+	HasAnalysis        bool // content counts below come from the latest SEO analysis
+	WordCount          int
+	HeadingCount       int
+	ImageCount         int
+	Views              int
+	Likes              int
+	Shares             int
+	Scroll25           int
+	Scroll50           int
+	Scroll75           int
+	Scroll100          int
+	PreviousViews      int
+	Leads              int
+	Revenue            float64
+	Visitors           int
+	TimeSpent          float64
+	TimedEvents        int
+
+	EngagementScore int `gorm:"-"`
+}
+
+// performanceTotals sums post rows for a summary, category, author or content group
+type performanceTotals struct {
+	Posts         int
+	Views         int
+	PreviousViews int
+	Engagements   int
+	Shares        int
+	Scrolled      int // views reaching 25% of the post
+	Leads         int
+	Revenue       float64
+	TimeSpent     float64
+	TimedEvents   int
+	ScoreSum      int
+}
+
+// bucketValue is one point of an aggregated series
+type bucketValue struct {
+	Bucket string
+	Value  float64
+}
+
+// GetDashboard returns blog performance for a date range: summary, top posts,
+// category and author breakdowns, a trend series and engagement insights.
+// Parameters: start_date and end_date (RFC 3339 or YYYY-MM-DD), or period
+// (e.g. 7d, default 30d) ending now; granularity (day, week, month); categories
+// (comma-separated slugs, including subcategories); author_ids; limit.
+func (h *AnalyticsHandler) GetDashboard(c *gin.Context) {
+	req, ok := analyticsRequestFromQuery(c)
+	if !ok {
+		return
+	}
+	limit := min(max(parseIntQuery(c, "limit", defaultTopPerformers), 1), maxTopPerformers)
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	response, err := buildDashboard(db, req, limit)
+	if err != nil {
+		logger.Error("Failed to build analytics dashboard", err, map[string]interface{}{
+			"start_date": req.StartDate,
+			"end_date":   req.EndDate,
+		})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve analytics", "DATABASE_ERROR", "Unable to aggregate blog performance")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Analytics dashboard retrieved successfully", response)
+}
+
+// buildDashboard runs the dashboard aggregates for a validated request
+func buildDashboard(db *gorm.DB, req models.BlogAnalyticsRequest, limit int) (models.BlogPerformanceResponse, error) {
+	var response models.BlogPerformanceResponse
+
+	rows, err := blogPerformanceRows(analyticsBlogScope(db, req), req.StartDate, req.EndDate)
+	if err != nil {
+		return response, err
+	}
+	trend, err := performanceTrend(db, req)
+	if err != nil {
+		return response, err
+	}
+	seasonal, err := seasonalViews(db, req)
+	if err != nil {
+		return response, err
+	}
+	segments, err := audienceSegments(db, req)
+	if err != nil {
+		return response, err
+	}
+
+	return models.BlogPerformanceResponse{
+		Period:             analyticsPeriod(req),
+		Summary:            performanceSummary(rows),
+		TopPerformers:      topPerformers(rows, limit),
+		CategoryMetrics:    categoryPerformance(rows),
+		AuthorMetrics:      authorPerformance(rows),
+		TrendData:          trend,
+		EngagementInsights: engagementInsights(rows, seasonal, segments),
+	}, nil
+}
+
+// analyticsRequestFromQuery parses and validates the analytics window and
+// filters, writing the error response when they are invalid
+func analyticsRequestFromQuery(c *gin.Context) (models.BlogAnalyticsRequest, bool) {
+	var req models.BlogAnalyticsRequest
+	var ok bool
+	if req.StartDate, req.EndDate, ok = analyticsWindowFromQuery(c, "30d"); !ok {
+		return req, false
+	}
+
+	if req.Granularity, ok = analyticsGranularityFromQuery(c, req.StartDate, req.EndDate); !ok {
+		return req, false
+	}
+
+	for _, slug := range strings.Split(c.Query("categories"), ",") {
+		if slug = strings.TrimSpace(slug); slug != "" {
+			req.Categories = append(req.Categories, slug)
+		}
+	}
+	if req.AuthorIDs, ok = parseIDList(c.Query("author_ids")); !ok {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_AUTHOR", "author_ids must be a comma-separated list of user IDs")
+		return req, false
+	}
+
+	return req, true
+}
+
+// parseIDList parses a comma-separated list of positive IDs
+func parseIDList(value string) ([]uint, bool) {
+	var ids []uint
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(item, 10, 64)
+		if err != nil || id == 0 {
+			return nil, false
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, true
+}
+
+// analyticsWindowFromQuery parses start_date and end_date, or a period ending
+// at end_date (default now), writing the error response when they are invalid
+func analyticsWindowFromQuery(c *gin.Context, defaultPeriod string) (time.Time, time.Time, bool) {
+	startDate, ok := parseDateQuery(c, "start_date", false)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	endDate, ok := parseDateQuery(c, "end_date", true)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	end := time.Now().UTC()
+	if endDate != nil {
+		end = endDate.UTC()
+	}
+	var start time.Time
+	if startDate != nil {
+		start = startDate.UTC()
+	} else {
+		length, valid := analytics.ParsePeriod(c.DefaultQuery("period", defaultPeriod))
+		if !valid {
+			respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_PERIOD", "period must be a number of hours or days, e.g. 24h or 30d")
+			return start, end, false
+		}
+		start = end.Add(-length)
+	}
+	if !start.Before(end) {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_DATE_RANGE", "start_date must be before end_date")
+		return start, end, false
+	}
+	return start, end, true
+}
+
+// analyticsGranularityFromQuery parses granularity, defaulting to one that
+// suits the window, and rejects windows with too many buckets
+func analyticsGranularityFromQuery(c *gin.Context, start, end time.Time) (string, bool) {
+	granularity := strings.ToLower(c.Query("granularity"))
+	if granularity == "" {
+		granularity = analytics.DefaultGranularity(start, end)
+	} else if !analytics.IsValidGranularity(granularity) {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_GRANULARITY", "granularity must be day, week or month")
+		return granularity, false
+	}
+	if analytics.BucketCount(start, end, granularity) > analytics.MaxBuckets {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_GRANULARITY",
+			fmt.Sprintf("The range spans more than %d %ss; use a coarser granularity", analytics.MaxBuckets, granularity))
+		return granularity, false
+	}
+	return granularity, true
+}
+
+// analyticsPeriod formats the analytics window as an ISO 8601 date interval
+func analyticsPeriod(req models.BlogAnalyticsRequest) string {
+	return req.StartDate.Format("2006-01-02") + "/" + req.EndDate.Format("2006-01-02")
+}
+
+// analyticsBlogScope selects the posts published by the end of the window that
+// match the category and author filters
+func analyticsBlogScope(db *gorm.DB, req models.BlogAnalyticsRequest) *gorm.DB {
+	query := db.Model(&models.Blog{}).Where("blogs.published_at IS NOT NULL AND blogs.published_at <= ?", req.EndDate)
+	if len(req.Categories) > 0 {
+		query = query.Where("blogs.category_id IN (SELECT sub.id FROM categories sub JOIN categories root ON sub.path LIKE CONCAT(root.path, '%') WHERE root.slug IN ?)", req.Categories)
+	}
+	if len(req.AuthorIDs) > 0 {
+		query = query.Where("blogs.author_id IN ?", req.AuthorIDs)
+	}
+	return query
+}
+
+// analyticsDay formats t for comparison with DATE columns
+func analyticsDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// blogPerformanceColumns selects a post with its aggregates from the joins in blogPerformanceRows
+const blogPerformanceColumns = `blogs.id, blogs.title, blogs.slug, blogs.published_at, blogs.category_id,
+	COALESCE(categories.name, '') AS category_name, blogs.author_id, COALESCE(admin_users.name, '') AS author_name,
+	blogs.comments_count, blogs.seo_score,
+	blogs.featured_image <> '' AS has_featured_image, blogs.focus_keyword <> '' AS has_focus_keyword,
+	blogs.meta_description <> '' AS has_meta_description,
+	seo_analyses.id IS NOT NULL AS has_analysis, COALESCE(seo_analyses.word_count, 0) AS word_count,
+	COALESCE(CAST(JSON_EXTRACT(seo_analyses.analysis, '$.structure_analysis.h2_count') AS UNSIGNED)
+		+ CAST(JSON_EXTRACT(seo_analyses.analysis, '$.structure_analysis.h3_count') AS UNSIGNED), 0) AS heading_count,
+	COALESCE(CAST(JSON_EXTRACT(seo_analyses.analysis, '$.image_analysis.image_count') AS UNSIGNED), 0) AS image_count,
+	COALESCE(metrics.views, 0) AS views, COALESCE(metrics.likes, 0) AS likes, COALESCE(metrics.shares, 0) AS shares,
+	COALESCE(metrics.scroll25, 0) AS scroll25, COALESCE(metrics.scroll50, 0) AS scroll50,
+	COALESCE(metrics.scroll75, 0) AS scroll75, COALESCE(metrics.scroll100, 0) AS scroll100,
+	COALESCE(previous.views, 0) AS previous_views, COALESCE(metrics.leads, 0) AS leads,
+	COALESCE(metrics.revenue, 0) AS revenue, COALESCE(metrics.visitors, 0) AS visitors,
+	COALESCE(metrics.time_spent, 0) AS time_spent, COALESCE(metrics.timed_events, 0) AS timed_events`
+
+// blogPerformanceRows sums the daily metric rollups per post in scope over the
+// days of start..end. Leads and revenue are those of the rollups: leads
+// captured and the touchpoint share of leads converted on each day, without
+// merged duplicates and leads quarantined for missing consent. Visitors are
+// summed over days, so a visitor returning on another day counts again.
+func blogPerformanceRows(scope *gorm.DB, start, end time.Time) ([]blogPerformanceRow, error) {
+	previousStart, previousEnd := analytics.PreviousPeriod(start, end)
+
+	rows := []blogPerformanceRow{}
+	err := scope.
+		Select(blogPerformanceColumns).
+		Joins("LEFT JOIN categories ON categories.id = blogs.category_id").
+		Joins("LEFT JOIN admin_users ON admin_users.id = blogs.author_id").
+		Joins("LEFT JOIN seo_analyses ON seo_analyses.id = (SELECT MAX(latest.id) FROM seo_analyses latest WHERE latest.blog_id = blogs.id)").
+		Joins(`LEFT JOIN (SELECT blog_id, SUM(views) AS views, SUM(likes) AS likes, SUM(shares) AS shares,
+				SUM(scroll_25) AS scroll25, SUM(scroll_50) AS scroll50, SUM(scroll_75) AS scroll75, SUM(scroll_100) AS scroll100,
+				SUM(leads) AS leads, SUM(revenue) AS revenue, SUM(unique_visitors) AS visitors,
+				SUM(time_spent) AS time_spent, SUM(timed_events) AS timed_events
+			FROM blog_daily_metrics WHERE date BETWEEN ? AND ? GROUP BY blog_id) metrics ON metrics.blog_id = blogs.id`,
+			analyticsDay(start), analyticsDay(end)).
+		Joins(`LEFT JOIN (SELECT blog_id, SUM(views) AS views FROM blog_daily_metrics
+			WHERE date BETWEEN ? AND ? GROUP BY blog_id) previous ON previous.blog_id = blogs.id`,
+			analyticsDay(previousStart), analyticsDay(previousEnd)).
+		Order("blogs.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		rows[i].EngagementScore = rows[i].engagementScore()
+	}
+	return rows, nil
+}
+
+// timeOnPage is the average reported time on the post, in seconds
+func (r blogPerformanceRow) timeOnPage() float64 {
+	return averageTime(r.TimeSpent, r.TimedEvents)
+}
+
+// engagementScore rates the post 0-100 with the performance calculator
+func (r blogPerformanceRow) engagementScore() int {
+	avgDepth, _ := engagement.ScrollHeatmap(r.Views, [4]int{r.Scroll25, r.Scroll50, r.Scroll75, r.Scroll100})
+	return int(math.Round(performanceCalculator.CalculateEngagementScore(analytics.EngagementMetrics{
+		PageViews:      r.Views,
+		AvgTimeOnPage:  int(r.timeOnPage()),
+		BounceRate:     engagement.BounceRate(r.Views, r.Scroll25),
+		AvgScrollDepth: avgDepth,
+		SocialShares:   r.Shares,
+		Comments:       r.CommentsCount,
+	})))
+}
+
+// add adds a post row to the totals
+func (t *performanceTotals) add(row blogPerformanceRow) {
+	t.Posts++
+	t.Views += row.Views
+	t.PreviousViews += row.PreviousViews
+	t.Engagements += row.Likes + row.Shares
+	t.Shares += row.Shares
+	t.Scrolled += row.Scroll25
+	t.Leads += row.Leads
+	t.Revenue += row.Revenue
+	t.TimeSpent += row.TimeSpent
+	t.TimedEvents += row.TimedEvents
+	t.ScoreSum += row.EngagementScore
+}
+
+// engagementRate is likes and shares per 100 views
+func (t performanceTotals) engagementRate() float64 {
+	return percentage(t.Engagements, t.Views)
+}
+
+// conversionRate is leads per 100 views
+func (t performanceTotals) conversionRate() float64 {
+	return performanceCalculator.CalculateConversionRate(t.Leads, t.Views)
+}
+
+// roi compares revenue to the production cost of the posts
+func (t performanceTotals) roi() float64 {
+	return performanceCalculator.CalculateROI(t.Revenue, contentCostPerPost()*float64(t.Posts))
+}
+
+// growthRate is the view growth over the previous window of the same length
+func (t performanceTotals) growthRate() float64 {
+	return growthRate(float64(t.Views), float64(t.PreviousViews))
+}
+
+// averageScore is the mean engagement score of the posts
+func (t performanceTotals) averageScore() float64 {
+	if t.Posts == 0 {
+		return 0
+	}
+	return float64(t.ScoreSum) / float64(t.Posts)
+}
+
+// perPost divides value by the number of posts
+func (t performanceTotals) perPost(value float64) float64 {
+	if t.Posts == 0 {
+		return 0
+	}
+	return value / float64(t.Posts)
+}
+
+// contentCostPerPost is the production cost of a post used for ROI
+// (ANALYTICS_COST_PER_POST); without it ROI is reported as 0
+func contentCostPerPost() float64 {
+	cost, err := strconv.ParseFloat(getEnv("ANALYTICS_COST_PER_POST", "0"), 64)
+	if err != nil || cost < 0 {
+		return 0
+	}
+	return cost
+}
+
+// averageTime divides reported time by the number of reports
+func averageTime(seconds float64, reports int) float64 {
+	if reports == 0 {
+		return 0
+	}
+	return seconds / float64(reports)
+}
+
+// percentage returns part as a percentage of whole
+func percentage(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
+
+// growthRate is the percentage change from previous to current; no change
+// when both are zero
+func growthRate(current, previous float64) float64 {
+	if current == 0 && previous == 0 {
+		return 0
+	}
+	return performanceCalculator.CalculateGrowthRate(current, previous)
+}
+
+// round2 rounds to two decimal places
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// performanceSummary totals every post in scope
+func performanceSummary(rows []blogPerformanceRow) models.BlogPerformanceSummary {
+	var totals performanceTotals
+	for _, row := range rows {
+		totals.add(row)
+	}
+	return models.BlogPerformanceSummary{
+		TotalPosts:       totals.Posts,
+		TotalViews:       totals.Views,
+		TotalEngagements: totals.Engagements,
+		TotalLeads:       totals.Leads,
+		TotalRevenue:     round2(totals.Revenue),
+		AvgTimeOnPage:    round2(averageTime(totals.TimeSpent, totals.TimedEvents)),
+		AvgBounceRate:    round2(engagement.BounceRate(totals.Views, totals.Scrolled)),
+		AvgSocialShares:  round2(totals.perPost(float64(totals.Shares))),
+		ConversionRate:   round2(totals.conversionRate()),
+		LeadValuePerPost: round2(totals.perPost(totals.Revenue)),
+		ROI:              round2(totals.roi()),
+		GrowthRate:       round2(totals.growthRate()),
+	}
+}
+
+// topPerformers ranks posts by revenue, then leads, views and engagement score
+func topPerformers(rows []blogPerformanceRow, limit int) []models.BlogPerformanceMetric {
+	ranked := append([]blogPerformanceRow(nil), rows...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Revenue != b.Revenue {
+			return a.Revenue > b.Revenue
+		}
+		if a.Leads != b.Leads {
+			return a.Leads > b.Leads
+		}
+		if a.Views != b.Views {
+			return a.Views > b.Views
+		}
+		return a.EngagementScore > b.EngagementScore
+	})
+
+	metrics := make([]models.BlogPerformanceMetric, 0, min(limit, len(ranked)))
+	for i, row := range ranked[:min(limit, len(ranked))] {
+		metric := models.BlogPerformanceMetric{
+			BlogID:          row.ID,
+			Title:           row.Title,
+			URL:             blogPostURL(row.Slug),
+			Category:        categoryLabel(row),
+			AuthorID:        row.AuthorID,
+			AuthorName:      row.AuthorName,
+			Views:           row.Views,
+			UniqueVisitors:  row.Visitors,
+			TimeOnPage:      round2(row.timeOnPage()),
+			BounceRate:      round2(engagement.BounceRate(row.Views, row.Scroll25)),
+			SocialShares:    row.Shares,
+			Comments:        row.CommentsCount,
+			Leads:           row.Leads,
+			Revenue:         round2(row.Revenue),
+			EngagementScore: row.EngagementScore,
+			SEOScore:        int(math.Round(row.SEOScore)),
+			PerformanceRank: i + 1,
+		}
+		if row.PublishedAt != nil {
+			metric.PublishedAt = *row.PublishedAt
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+// categoryLabel is the post's category name, or Uncategorized
+func categoryLabel(row blogPerformanceRow) string {
+	if row.CategoryID == nil || row.CategoryName == "" {
+		return uncategorizedLabel
+	}
+	return row.CategoryName
+}
+
+// groupTotals sums rows per key, returning the keys in first-seen order
+func groupTotals[K comparable](rows []blogPerformanceRow, key func(blogPerformanceRow) (K, bool)) ([]K, map[K]*performanceTotals) {
+	keys := []K{}
+	groups := map[K]*performanceTotals{}
+	for _, row := range rows {
+		k, ok := key(row)
+		if !ok {
+			continue
+		}
+		if groups[k] == nil {
+			keys = append(keys, k)
+			groups[k] = &performanceTotals{}
+		}
+		groups[k].add(row)
+	}
+	return keys, groups
+}
+
+// maxViews returns the most views of any group
+func maxViews[K comparable](groups map[K]*performanceTotals) int {
+	most := 0
+	for _, totals := range groups {
+		most = max(most, totals.Views)
+	}
+	return most
+}
+
+// categoryPerformance totals posts per direct category, most viewed first.
+// Popularity is views relative to the most viewed category.
+func categoryPerformance(rows []blogPerformanceRow) []models.CategoryPerformance {
+	keys, groups := groupTotals(rows, func(row blogPerformanceRow) (string, bool) {
+		return categoryLabel(row), true
+	})
+	most := maxViews(groups)
+
+	metrics := make([]models.CategoryPerformance, 0, len(keys))
+	for _, category := range keys {
+		totals := groups[category]
+		metrics = append(metrics, models.CategoryPerformance{
+			Category:         category,
+			PostCount:        totals.Posts,
+			TotalViews:       totals.Views,
+			AvgViews:         round2(totals.perPost(float64(totals.Views))),
+			TotalEngagements: totals.Engagements,
+			AvgEngagements:   round2(totals.perPost(float64(totals.Engagements))),
+			TotalLeads:       totals.Leads,
+			ConversionRate:   round2(totals.conversionRate()),
+			Revenue:          round2(totals.Revenue),
+			ROI:              round2(totals.roi()),
+			PopularityScore:  int(math.Round(percentage(totals.Views, most))),
+		})
+	}
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return metrics[i].TotalViews > metrics[j].TotalViews
+	})
+	return metrics
+}
+
+// authorPerformance totals posts per author, most viewed first. The author
+// score is the mean engagement score of their posts.
+func authorPerformance(rows []blogPerformanceRow) []models.AuthorPerformance {
+	names := map[uint]string{}
+	keys, groups := groupTotals(rows, func(row blogPerformanceRow) (uint, bool) {
+		names[row.AuthorID] = row.AuthorName
+		return row.AuthorID, true
+	})
+
+	metrics := make([]models.AuthorPerformance, 0, len(keys))
+	for _, authorID := range keys {
+		totals := groups[authorID]
+		metrics = append(metrics, models.AuthorPerformance{
+			AuthorID:         authorID,
+			AuthorName:       names[authorID],
+			PostCount:        totals.Posts,
+			TotalViews:       totals.Views,
+			AvgViews:         round2(totals.perPost(float64(totals.Views))),
+			TotalEngagements: totals.Engagements,
+			EngagementRate:   round2(totals.engagementRate()),
+			TotalLeads:       totals.Leads,
+			LeadConversion:   round2(totals.conversionRate()),
+			Revenue:          round2(totals.Revenue),
+			AuthorScore:      int(math.Round(totals.averageScore())),
+		})
+	}
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return metrics[i].TotalViews > metrics[j].TotalViews
+	})
+	return metrics
+}
+
+// bucketExpression formats column as the start date of its bucket (YYYY-MM-DD,
+// weeks starting on Monday), matching analytics.BucketStart
+func bucketExpression(column, granularity string) string {
+	switch granularity {
+	case analytics.GranularityWeek:
+		return fmt.Sprintf("DATE_FORMAT(DATE_SUB(DATE(%s), INTERVAL WEEKDAY(%s) DAY), '%%Y-%%m-%%d')", column, column)
+	case analytics.GranularityMonth:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-01')", column)
+	default:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-%%d')", column)
+	}
+}
+
+// performanceTrend returns views, engagements, leads, revenue and posts
+// published per bucket, with a point for every bucket in the window
+func performanceTrend(db *gorm.DB, req models.BlogAnalyticsRequest) ([]models.BlogTrendData, error) {
+	buckets := analytics.Buckets(req.StartDate, req.EndDate, req.Granularity)
+	trend := make([]models.BlogTrendData, len(buckets))
+	index := make(map[string]int, len(buckets))
+	for i, bucket := range buckets {
+		trend[i].Date = bucket
+		index[bucket.Format("2006-01-02")] = i
+	}
+
+	metricsQuery := func() *gorm.DB {
+		return db.Table("blog_daily_metrics").
+			Where("blog_id IN (?) AND date BETWEEN ? AND ?", analyticsBlogScope(db, req).Select("blogs.id"),
+				analyticsDay(req.StartDate), analyticsDay(req.EndDate))
+	}
+	series := []struct {
+		query  *gorm.DB
+		column string
+		value  string
+		apply  func(point *models.BlogTrendData, value float64)
+	}{
+		{metricsQuery(), "date", "SUM(views)", func(p *models.BlogTrendData, v float64) { p.Views = int(v) }},
+		{metricsQuery(), "date", "SUM(likes + shares)", func(p *models.BlogTrendData, v float64) { p.Engagements = int(v) }},
+		{metricsQuery(), "date", "SUM(leads)", func(p *models.BlogTrendData, v float64) { p.Leads = int(v) }},
+		{metricsQuery(), "date", "SUM(revenue)", func(p *models.BlogTrendData, v float64) { p.Revenue = round2(v) }},
+		{
+			analyticsBlogScope(db, req).Where("blogs.published_at >= ?", req.StartDate),
+			"blogs.published_at", "COUNT(*)", func(p *models.BlogTrendData, v float64) { p.PostCount = int(v) },
+		},
+	}
+
+	for _, s := range series {
+		values := []bucketValue{}
+		if err := s.query.
+			Select(bucketExpression(s.column, req.Granularity) + " AS bucket, " + s.value + " AS value").
+			Group("bucket").
+			Scan(&values).Error; err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			if i, ok := index[value.Bucket]; ok {
+				s.apply(&trend[i], value.Value)
+			}
+		}
+	}
+
+	return trend, nil
+}
+
+// seasonalViews returns a seasonality index (100 = average) of views per calendar month in the window
+func seasonalViews(db *gorm.DB, req models.BlogAnalyticsRequest) (map[string]float64, error) {
+	values := []bucketValue{}
+	if err := db.Table("blog_daily_metrics").
+		Select("CAST(MONTH(date) AS CHAR) AS bucket, SUM(views) AS value").
+		Where("blog_id IN (?) AND date BETWEEN ? AND ?", analyticsBlogScope(db, req).Select("blogs.id"),
+			analyticsDay(req.StartDate), analyticsDay(req.EndDate)).
+		Group("bucket").
+		Scan(&values).Error; err != nil {
+		return nil, err
+	}
+
+	months := make([]int, 0, len(values))
+	monthly := make([]float64, 0, len(values))
+	for _, value := range values {
+		month, err := strconv.Atoi(value.Bucket)
+		if err != nil || month < 1 || month > 12 {
+			continue
+		}
+		months = append(months, month)
+		monthly = append(monthly, value.Value)
+	}
+
+	patterns := make(map[string]float64, len(months))
+	for i, index := range performanceCalculator.CalculateSeasonalityIndex(monthly) {
+		patterns[time.Month(months[i-1]).String()] = round2(index)
+	}
+	return patterns, nil
+}
+
+// audienceSegments breaks the window's leads down by traffic source. The
+// engagement rate is the average scroll depth at capture.
+func audienceSegments(db *gorm.DB, req models.BlogAnalyticsRequest) ([]models.AudienceSegment, error) {
+	leads := func() *gorm.DB {
+		return db.Table("blog_leads").
+			Where("blog_id IN (?) AND captured_at BETWEEN ? AND ? AND merged_into_id IS NULL AND quarantined_at IS NULL",
+				analyticsBlogScope(db, req).Select("blogs.id"), req.StartDate, req.EndDate)
+	}
+	const segmentColumn = "COALESCE(NULLIF(traffic_source, ''), 'direct') AS segment"
+
+	var rows []struct {
+		Segment     string
+		Size        int
+		ScrollDepth float64
+		Converted   int
+		Revenue     float64
+	}
+	if err := leads().
+		Select(segmentColumn+", COUNT(*) AS size, AVG(scroll_depth_at_capture) AS scroll_depth, SUM(status = ?) AS converted, SUM(conversion_value) AS revenue", workflow.LeadStatusConverted).
+		Group("segment").
+		Order("size DESC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var topics []struct {
+		Segment string
+		Topic   string
+		Leads   int
+	}
+	if err := leads().
+		Select(segmentColumn + ", blog_category AS topic, COUNT(*) AS leads").
+		Where("blog_category <> ''").
+		Group("segment, topic").
+		Order("leads DESC, topic").
+		Scan(&topics).Error; err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, row := range rows {
+		total += row.Size
+	}
+	segments := make([]models.AudienceSegment, 0, len(rows))
+	for _, row := range rows {
+		segment := models.AudienceSegment{
+			Segment:        row.Segment,
+			Size:           row.Size,
+			Percentage:     round2(percentage(row.Size, total)),
+			EngagementRate: round2(row.ScrollDepth),
+			ConversionRate: round2(performanceCalculator.CalculateConversionRate(row.Converted, row.Size)),
+			TopTopics:      []string{},
+		}
+		if row.Size > 0 {
+			segment.RevenuePerUser = round2(row.Revenue / float64(row.Size))
+		}
+		for _, topic := range topics {
+			if topic.Segment == row.Segment && len(segment.TopTopics) < 3 {
+				segment.TopTopics = append(segment.TopTopics, topic.Topic)
+			}
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// engagementInsights explains what drives engagement across the posts in scope
+func engagementInsights(rows []blogPerformanceRow, seasonal map[string]float64, segments []models.AudienceSegment) models.EngagementInsights {
+	words, headings, images := topQuartileContent(rows)
+
+	patterns := models.EngagementPatterns{
+		BestPublishingTime:   map[string]float64{},
+		BestPublishingHour:   map[int]float64{},
+		SeasonalPatterns:     seasonal,
+		OptimalContentLength: words,
+		OptimalHeadingCount:  headings,
+	}
+	// Average views in the window per post, by when the post was published
+	days, byDay := groupTotals(rows, func(row blogPerformanceRow) (string, bool) {
+		if row.PublishedAt == nil {
+			return "", false
+		}
+		return row.PublishedAt.UTC().Weekday().String(), true
+	})
+	for _, day := range days {
+		patterns.BestPublishingTime[day] = round2(byDay[day].perPost(float64(byDay[day].Views)))
+	}
+	hours, byHour := groupTotals(rows, func(row blogPerformanceRow) (int, bool) {
+		if row.PublishedAt == nil {
+			return 0, false
+		}
+		return row.PublishedAt.UTC().Hour(), true
+	})
+	for _, hour := range hours {
+		patterns.BestPublishingHour[hour] = round2(byHour[hour].perPost(float64(byHour[hour].Views)))
+	}
+
+	preferences := contentPreferences(rows)
+	preferences.OptimalImageCount = images
+
+	return models.EngagementInsights{
+		TopEngagementDrivers: engagementDrivers(rows),
+		EngagementPatterns:   patterns,
+		AudienceSegments:     segments,
+		ContentPreferences:   preferences,
+	}
+}
+
+// engagementDrivers compares the engagement rate of posts with and without a
+// trait. Traits read from content need an SEO analysis of the post; traits
+// where either side has no views are left out.
+func engagementDrivers(rows []blogPerformanceRow) []models.EngagementDriver {
+	traits := []struct {
+		driver        string
+		label         string
+		needsAnalysis bool
+		has           func(blogPerformanceRow) bool
+	}{
+		{"featured_image", "a featured image", false, func(r blogPerformanceRow) bool { return r.HasFeaturedImage }},
+		{"focus_keyword", "a focus keyword", false, func(r blogPerformanceRow) bool { return r.HasFocusKeyword }},
+		{"meta_description", "a meta description", false, func(r blogPerformanceRow) bool { return r.HasMetaDescription }},
+		{"long_form", "1,000 or more words", true, func(r blogPerformanceRow) bool { return r.WordCount >= 1000 }},
+		{"subheadings", "three or more subheadings", true, func(r blogPerformanceRow) bool { return r.HeadingCount >= 3 }},
+		{"images", "images in the content", true, func(r blogPerformanceRow) bool { return r.ImageCount > 0 }},
+	}
+
+	drivers := []models.EngagementDriver{}
+	for _, trait := range traits {
+		var with, without performanceTotals
+		for _, row := range rows {
+			if trait.needsAnalysis && !row.HasAnalysis {
+				continue
+			}
+			if trait.has(row) {
+				with.add(row)
+			} else {
+				without.add(row)
+			}
+		}
+		if with.Views == 0 || without.Views == 0 {
+			continue
+		}
+
+		lift := analytics.Lift(with.engagementRate(), without.engagementRate())
+		direction := "more"
+		if lift < 0 {
+			direction = "less"
+		}
+		drivers = append(drivers, models.EngagementDriver{
+			Driver:      trait.driver,
+			Impact:      analytics.ImpactLevel(lift),
+			Description: fmt.Sprintf("Posts with %s get %.0f%% %s engagement per view", trait.label, math.Abs(lift), direction),
+			Score:       round2(lift),
+		})
+	}
+	sort.SliceStable(drivers, func(i, j int) bool {
+		return drivers[i].Score > drivers[j].Score
+	})
+	return drivers
+}
+
+// topQuartileContent returns the average word, subheading and image counts of
+// the top quarter of analyzed posts by engagement score
+func topQuartileContent(rows []blogPerformanceRow) (words, headings, images int) {
+	analyzed := []blogPerformanceRow{}
+	for _, row := range rows {
+		if row.HasAnalysis && row.Views > 0 {
+			analyzed = append(analyzed, row)
+		}
+	}
+	if len(analyzed) == 0 {
+		return 0, 0, 0
+	}
+	sort.SliceStable(analyzed, func(i, j int) bool {
+		return analyzed[i].EngagementScore > analyzed[j].EngagementScore
+	})
+
+	top := analyzed[:(len(analyzed)+3)/4]
+	for _, row := range top {
+		words += row.WordCount
+		headings += row.HeadingCount
+		images += row.ImageCount
+	}
+	n := float64(len(top))
+	return int(math.Round(float64(words) / n)), int(math.Round(float64(headings) / n)), int(math.Round(float64(images) / n))
+}
+
+// contentPreferences breaks views, engagement and conversions down by content
+// length, format (inferred from the title) and topic (category)
+func contentPreferences(rows []blogPerformanceRow) models.ContentPreferences {
+	preferences := models.ContentPreferences{
+		PreferredLength: []models.ContentLengthPreference{},
+		PreferredFormat: []models.ContentFormatPreference{},
+		PreferredTopics: []models.TopicPreference{},
+	}
+
+	_, lengths := groupTotals(rows, func(row blogPerformanceRow) (string, bool) {
+		return analytics.LengthRange(row.WordCount), row.HasAnalysis
+	})
+	analyzedViews := 0
+	for _, totals := range lengths {
+		analyzedViews += totals.Views
+	}
+	for _, lengthRange := range analytics.LengthRanges() {
+		if totals := lengths[lengthRange]; totals != nil {
+			preferences.PreferredLength = append(preferences.PreferredLength, models.ContentLengthPreference{
+				LengthRange:    lengthRange,
+				Percentage:     round2(percentage(totals.Views, analyzedViews)),
+				EngagementRate: round2(totals.engagementRate()),
+				ConversionRate: round2(totals.conversionRate()),
+			})
+		}
+	}
+
+	formats, byFormat := groupTotals(rows, func(row blogPerformanceRow) (string, bool) {
+		return analytics.ContentFormat(row.Title), true
+	})
+	var totalViews int
+	for _, totals := range byFormat {
+		totalViews += totals.Views
+	}
+	for _, format := range formats {
+		totals := byFormat[format]
+		preferences.PreferredFormat = append(preferences.PreferredFormat, models.ContentFormatPreference{
+			Format:         format,
+			Percentage:     round2(percentage(totals.Views, totalViews)),
+			EngagementRate: round2(totals.engagementRate()),
+			ShareRate:      round2(percentage(totals.Shares, totals.Views)),
+		})
+	}
+	sort.SliceStable(preferences.PreferredFormat, func(i, j int) bool {
+		return preferences.PreferredFormat[i].Percentage > preferences.PreferredFormat[j].Percentage
+	})
+
+	topics, byTopic := groupTotals(rows, func(row blogPerformanceRow) (string, bool) {
+		return categoryLabel(row), true
+	})
+	most := maxViews(byTopic)
+	for _, topic := range topics {
+		totals := byTopic[topic]
+		preferences.PreferredTopics = append(preferences.PreferredTopics, models.TopicPreference{
+			Topic:          topic,
+			Interest:       round2(percentage(totals.Views, most)),
+			Engagement:     round2(totals.engagementRate()),
+			ConversionRate: round2(totals.conversionRate()),
+			Trending:       totals.PreviousViews > 0 && totals.growthRate() >= trendingGrowth,
+		})
+	}
+	sort.SliceStable(preferences.PreferredTopics, func(i, j int) bool {
+		return preferences.PreferredTopics[i].Interest > preferences.PreferredTopics[j].Interest
+	})
+
+	return preferences
+}
@@ -0,0 +1,564 @@
+package handlers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/analytics"
+	"blog-service/pkg/database"
+	"blog-service/pkg/engagement"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var trendAnalyzer = analytics.NewTrendAnalyzer()
+
+// blogVisitorStats holds a post's rollup and lead aggregates over a window
+type blogVisitorStats struct {
+	PageViews      int
+	Sessions       int
+	EngagedVisits  int // visitors reporting at least 30 seconds on the post, per day
+	OrganicViews   int
+	CTAClicks      int
+	Downloads      int
+	Leads          int
+	Worked         int // leads moved past new
+	SalesQualified int
+	Customers      int
+	Revenue        float64
+	LeadScore      float64
+	CustomerLTV    float64
+}
+
+// GetBlogMetrics returns detailed metrics for one post over a window: traffic,
+// scroll depth, shares, a lead conversion funnel, a comparison with the
+// preceding window of the same length and a trend analysis of daily views.
+// Parameters: start_date and end_date, or period (default 7d); compare=false
+// leaves out the comparison.
+func (h *AnalyticsHandler) GetBlogMetrics(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	req := models.BlogMetricsRequest{BlogID: id, IncludeComparisons: c.DefaultQuery("compare", "true") != "false"}
+	if req.StartDate, req.EndDate, ok = analyticsWindowFromQuery(c, "7d"); !ok {
+		return
+	}
+	if analytics.BucketCount(req.StartDate, req.EndDate, analytics.GranularityDay) > analytics.MaxBuckets {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_DATE_RANGE",
+			fmt.Sprintf("The range spans more than %d days", analytics.MaxBuckets))
+		return
+	}
+	req.Period = analyticsPeriod(models.BlogAnalyticsRequest{StartDate: req.StartDate, EndDate: req.EndDate})
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	var blog models.Blog
+	if err := db.Select("id, title, slug, published_at").First(&blog, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "Blog not found", "BLOG_NOT_FOUND", "Blog post does not exist")
+			return
+		}
+		logger.Error("Failed to load blog", err, map[string]interface{}{"blog_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve blog", "DATABASE_ERROR", "Unable to load blog post")
+		return
+	}
+
+	response, err := buildBlogMetrics(db, &blog, req)
+	if err != nil {
+		logger.Error("Failed to build blog metrics", err, map[string]interface{}{"blog_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve analytics", "DATABASE_ERROR", "Unable to aggregate blog metrics")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Blog metrics retrieved successfully", response)
+}
+
+// buildBlogMetrics runs the per-post aggregates for a validated request
+func buildBlogMetrics(db *gorm.DB, blog *models.Blog, req models.BlogMetricsRequest) (models.BlogMetricsResponse, error) {
+	response := models.BlogMetricsResponse{
+		BlogID: blog.ID,
+		Title:  blog.Title,
+		URL:    blogPostURL(blog.Slug),
+		Period: req.Period,
+	}
+	if blog.PublishedAt != nil {
+		response.PublishedAt = *blog.PublishedAt
+	}
+
+	scope := func() *gorm.DB {
+		return db.Model(&models.Blog{}).Where("blogs.id = ?", blog.ID)
+	}
+	current, err := singleBlogPerformance(scope(), req.StartDate, req.EndDate)
+	if err != nil {
+		return response, err
+	}
+	stats, err := blogVisitorStatsFor(db, blog.ID, req.StartDate, req.EndDate)
+	if err != nil {
+		return response, err
+	}
+	heatmapTimes, err := scrollTimes(db, blog.ID, req.StartDate, req.EndDate)
+	if err != nil {
+		return response, err
+	}
+	sources, err := conversionSources(db, blog.ID, req.StartDate, req.EndDate)
+	if err != nil {
+		return response, err
+	}
+	series, err := dailyViews(db, blog.ID, req.StartDate, req.EndDate)
+	if err != nil {
+		return response, err
+	}
+
+	trend := trendAnalyzer.AnalyzeTrends(series)
+	response.Metrics = detailedMetrics(current, stats, heatmapTimes, sources, trend)
+	response.TrendAnalysis = blogTrendAnalysis(current, trend)
+	response.Recommendations = append([]string{}, trend.Insights...)
+	response.OptimizationTips = optimizationTips(current, response.Metrics, response.TrendAnalysis)
+
+	if req.IncludeComparisons {
+		previousStart, previousEnd := analytics.PreviousPeriod(req.StartDate, req.EndDate)
+		previous, err := singleBlogPerformance(scope(), previousStart, previousEnd)
+		if err != nil {
+			return response, err
+		}
+		response.Comparisons = metricsComparison(basicMetrics(current), basicMetrics(previous))
+	}
+
+	return response, nil
+}
+
+// singleBlogPerformance aggregates the single post in scope; a post without
+// activity yields a zero row
+func singleBlogPerformance(scope *gorm.DB, start, end time.Time) (blogPerformanceRow, error) {
+	rows, err := blogPerformanceRows(scope, start, end)
+	if err != nil || len(rows) == 0 {
+		return blogPerformanceRow{}, err
+	}
+	return rows[0], nil
+}
+
+// blogVisitorStatsFor sums the post's daily rollups over the window and counts
+// the leads captured on it. Lead counts follow the captured cohort to its
+// current status, so they come from the leads themselves.
+func blogVisitorStatsFor(db *gorm.DB, blogID uint, start, end time.Time) (blogVisitorStats, error) {
+	var stats blogVisitorStats
+
+	if err := db.Table("blog_daily_metrics").
+		Select(`SUM(page_views) AS page_views, SUM(sessions) AS sessions, SUM(engaged_visitors) AS engaged_visits,
+			SUM(organic_views) AS organic_views, SUM(cta_clicks) AS cta_clicks, SUM(downloads) AS downloads`).
+		Where("blog_id = ? AND date BETWEEN ? AND ?", blogID, analyticsDay(start), analyticsDay(end)).
+		Scan(&stats).Error; err != nil {
+		return stats, err
+	}
+
+	if err := db.Table("blog_leads").
+		Select(`COUNT(*) AS leads, SUM(status <> ?) AS worked,
+			SUM(qualified_at IS NOT NULL OR status = ?) AS sales_qualified, SUM(status = ?) AS customers,
+			SUM(CASE WHEN status = ? THEN conversion_value ELSE 0 END) AS revenue,
+			AVG(lead_score) AS lead_score, AVG(CASE WHEN status = ? THEN customer_ltv END) AS customer_ltv`,
+			workflow.LeadStatusNew, workflow.LeadStatusConverted, workflow.LeadStatusConverted,
+			workflow.LeadStatusConverted, workflow.LeadStatusConverted).
+		Where("blog_id = ? AND captured_at BETWEEN ? AND ? AND merged_into_id IS NULL AND quarantined_at IS NULL", blogID, start, end).
+		Scan(&stats).Error; err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// scrollTimes returns the average time on the post of visitors whose deepest
+// reported scroll reached each milestone. The rollups keep no dwell time per
+// milestone, so this reads the post's visitor events.
+func scrollTimes(db *gorm.DB, blogID uint, start, end time.Time) (map[int]float64, error) {
+	var rows []struct {
+		Position  int
+		TimeSpent float64
+	}
+	if err := db.Table("visitor_events").
+		Select("LEAST(FLOOR(scroll_depth / 25), 4) * 25 AS position, AVG(time_spent) AS time_spent").
+		Where("blog_id = ? AND created_at BETWEEN ? AND ? AND time_spent > 0 AND scroll_depth >= 25", blogID, start, end).
+		Group("position").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	times := make(map[int]float64, len(rows))
+	for _, row := range rows {
+		times[row.Position] = row.TimeSpent
+	}
+	return times, nil
+}
+
+// conversionSources breaks the leads captured on the post down by traffic source
+func conversionSources(db *gorm.DB, blogID uint, start, end time.Time) ([]models.ConversionSource, error) {
+	var rows []struct {
+		Source    string
+		Leads     int
+		Customers int
+		Revenue   float64
+		LeadScore float64
+	}
+	if err := db.Table("blog_leads").
+		Select(`COALESCE(NULLIF(traffic_source, ''), 'direct') AS source, COUNT(*) AS leads, SUM(status = ?) AS customers,
+			SUM(CASE WHEN status = ? THEN conversion_value ELSE 0 END) AS revenue, AVG(lead_score) AS lead_score`,
+			workflow.LeadStatusConverted, workflow.LeadStatusConverted).
+		Where("blog_id = ? AND captured_at BETWEEN ? AND ? AND merged_into_id IS NULL AND quarantined_at IS NULL", blogID, start, end).
+		Group("source").
+		Order("leads DESC, source").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	sources := make([]models.ConversionSource, 0, len(rows))
+	for _, row := range rows {
+		sources = append(sources, models.ConversionSource{
+			Source:         row.Source,
+			Leads:          row.Leads,
+			Customers:      row.Customers,
+			Revenue:        round2(row.Revenue),
+			ConversionRate: round2(performanceCalculator.CalculateConversionRate(row.Customers, row.Leads)),
+			Quality:        analytics.QualityTier(int(math.Round(row.LeadScore))),
+		})
+	}
+	return sources, nil
+}
+
+// dailyViews returns the post's views for every day in the window, zero on days without views
+func dailyViews(db *gorm.DB, blogID uint, start, end time.Time) ([]analytics.TrendDataPoint, error) {
+	values := []bucketValue{}
+	if err := db.Table("blog_daily_metrics").
+		Select(bucketExpression("date", analytics.GranularityDay)+" AS bucket, SUM(views) AS value").
+		Where("blog_id = ? AND date BETWEEN ? AND ?", blogID, analyticsDay(start), analyticsDay(end)).
+		Group("bucket").
+		Scan(&values).Error; err != nil {
+		return nil, err
+	}
+
+	views := make(map[string]float64, len(values))
+	for _, value := range values {
+		views[value.Bucket] = value.Value
+	}
+	days := analytics.Buckets(start, end, analytics.GranularityDay)
+	series := make([]analytics.TrendDataPoint, len(days))
+	for i, day := range days {
+		series[i] = analytics.TrendDataPoint{Date: day, Value: views[day.Format("2006-01-02")]}
+	}
+	return series, nil
+}
+
+// detailedMetrics assembles the post's metrics. Exit rate, email shares,
+// print actions, comment moderation, per-network shares, keyword data and form
+// views are not tracked and stay empty, so the funnel has no CTA to form or
+// form to submission rates.
+func detailedMetrics(row blogPerformanceRow, stats blogVisitorStats, times map[int]float64, sources []models.ConversionSource, trend analytics.TrendAnalysis) models.BlogDetailedMetrics {
+	reached := [4]int{row.Scroll25, row.Scroll50, row.Scroll75, row.Scroll100}
+	avgDepth, shares := engagement.ScrollHeatmap(row.Views, reached)
+	heatmap := make([]models.ScrollHeatmapPoint, len(engagement.ScrollBuckets))
+	for i, position := range engagement.ScrollBuckets {
+		heatmap[i] = models.ScrollHeatmapPoint{
+			Position:   position,
+			Engagement: round2(shares[i] * 100),
+			TimeSpent:  round2(times[position]),
+		}
+	}
+
+	var shareVelocity, viralCoefficient float64
+	if days := len(trend.DataPoints); days > 0 {
+		shareVelocity = float64(row.Shares) / float64(days*24)
+	}
+	if row.Views > 0 {
+		viralCoefficient = float64(row.Shares) / float64(row.Views)
+	}
+
+	funnel := models.BlogConversionFunnel{
+		Visitors:        row.Visitors,
+		Engaged:         stats.EngagedVisits,
+		CTAClicks:       stats.CTAClicks,
+		FormSubmissions: stats.Leads,
+		LeadNurturing:   stats.Worked,
+		SalesQualified:  stats.SalesQualified,
+		Customers:       stats.Customers,
+	}
+	funnel.ConversionRates = models.ConversionRates{
+		VisitorToEngaged:    round2(percentage(funnel.Engaged, funnel.Visitors)),
+		EngagedToCTA:        round2(percentage(funnel.CTAClicks, funnel.Engaged)),
+		SubmissionToNurture: round2(percentage(funnel.LeadNurturing, funnel.FormSubmissions)),
+		NurtureToSQL:        round2(percentage(funnel.SalesQualified, funnel.LeadNurturing)),
+		SQLToCustomer:       round2(percentage(funnel.Customers, funnel.SalesQualified)),
+		OverallConversion:   round2(percentage(funnel.Customers, funnel.Visitors)),
+	}
+
+	seoScore := int(math.Round(row.SEOScore))
+	trendScore := performanceCalculator.CalculateTrendScore(trend.DataPoints)
+
+	return models.BlogDetailedMetrics{
+		Views:          row.Views,
+		UniqueVisitors: row.Visitors,
+		PageViews:      stats.PageViews,
+		Sessions:       stats.Sessions,
+		TimeOnPage:     round2(row.timeOnPage()),
+		BounceRate:     round2(engagement.BounceRate(row.Views, row.Scroll25)),
+		ScrollDepth: models.ScrollDepthMetrics{
+			AvgScrollDepth: round2(avgDepth),
+			ScrollDepth25:  row.Scroll25,
+			ScrollDepth50:  row.Scroll50,
+			ScrollDepth75:  row.Scroll75,
+			ScrollDepth100: row.Scroll100,
+			ScrollHeatmap:  heatmap,
+		},
+		SocialShares: models.SocialShareMetrics{
+			TotalShares:      row.Shares,
+			SharesByPlatform: map[string]int{},
+			ShareVelocity:    round2(shareVelocity),
+			ViralCoefficient: math.Round(viralCoefficient*10000) / 10000,
+			InfluencerShares: []models.InfluencerShare{},
+		},
+		Comments: models.CommentMetrics{
+			TotalComments: row.CommentsCount,
+			TopCommenters: []models.TopCommenter{},
+		},
+		Downloads:        stats.Downloads,
+		ClickThroughRate: round2(percentage(stats.CTAClicks, row.Views)),
+		ConversionMetrics: models.BlogConversionMetrics{
+			Leads:             stats.Leads,
+			QualifiedLeads:    stats.SalesQualified,
+			Customers:         stats.Customers,
+			Revenue:           round2(stats.Revenue),
+			ConversionRate:    round2(performanceCalculator.CalculateConversionRate(stats.Leads, row.Views)),
+			LeadQualityScore:  int(math.Round(stats.LeadScore)),
+			ConversionFunnel:  funnel,
+			ConversionSources: sources,
+			AttributedRevenue: round2(row.Revenue),
+			CustomerLTV:       round2(stats.CustomerLTV),
+		},
+		SEOMetrics: models.BlogSEOMetrics{
+			OrganicTraffic:     stats.OrganicViews,
+			KeywordRankings:    []models.KeywordRanking{},
+			SEOScore:           seoScore,
+			TechnicalSEOIssues: []models.SEOIssue{},
+			Backlinks:          models.BacklinkMetrics{TopReferrers: []models.BacklinkSource{}},
+		},
+		EngagementScore: row.EngagementScore,
+		// Engagement, SEO and recent trend weigh equally
+		PerformanceScore: int(math.Round((float64(row.EngagementScore) + float64(seoScore) + trendScore) / 3)),
+	}
+}
+
+// basicMetrics reduces a post row to the compared metrics
+func basicMetrics(row blogPerformanceRow) models.BlogBasicMetrics {
+	return models.BlogBasicMetrics{
+		Views:        row.Views,
+		Engagements:  row.Likes + row.Shares,
+		Leads:        row.Leads,
+		Revenue:      round2(row.Revenue),
+		TimeOnPage:   round2(row.timeOnPage()),
+		BounceRate:   round2(engagement.BounceRate(row.Views, row.Scroll25)),
+		SocialShares: row.Shares,
+	}
+}
+
+// metricsComparison compares the window with the preceding one
+func metricsComparison(current, previous models.BlogBasicMetrics) *models.BlogMetricsComparison {
+	comparison := &models.BlogMetricsComparison{PreviousPeriod: previous, CurrentPeriod: current}
+	change := func(current, previous float64, absolute, percent *float64) {
+		*absolute = round2(current - previous)
+		*percent = round2(growthRate(current, previous))
+	}
+	changes, percents := &comparison.Changes, &comparison.PercentageChanges
+
+	change(float64(current.Views), float64(previous.Views), &changes.Views, &percents.Views)
+	change(float64(current.Engagements), float64(previous.Engagements), &changes.Engagements, &percents.Engagements)
+	change(float64(current.Leads), float64(previous.Leads), &changes.Leads, &percents.Leads)
+	change(current.Revenue, previous.Revenue, &changes.Revenue, &percents.Revenue)
+	change(current.TimeOnPage, previous.TimeOnPage, &changes.TimeOnPage, &percents.TimeOnPage)
+	change(current.BounceRate, previous.BounceRate, &changes.BounceRate, &percents.BounceRate)
+	change(float64(current.SocialShares), float64(previous.SocialShares), &changes.SocialShares, &percents.SocialShares)
+	return comparison
+}
+
+// blogTrendAnalysis summarizes the trend analysis of daily views and projects
+// the next 30 and 90 days along its regression line, scaling the other
+// metrics by their ratio to views in the window
+func blogTrendAnalysis(row blogPerformanceRow, trend analytics.TrendAnalysis) models.BlogTrendAnalysis {
+	direction, strength := analytics.SummarizeTrend(trend)
+	peaks, lows := analytics.PeakAndLowMonths(trend.SeasonalityAnalysis.MonthlyPattern)
+
+	seasonality := models.SeasonalityInfo{
+		HasSeasonality:   trend.SeasonalityAnalysis.HasSeasonality,
+		PeakMonths:       peaks,
+		LowMonths:        lows,
+		SeasonalityScore: analytics.SeasonalityScore(trend.SeasonalityAnalysis.DayOfWeekPattern),
+	}
+	switch {
+	case len(peaks) > 0 || len(lows) > 0:
+		seasonality.SeasonalPattern = "monthly"
+	case seasonality.HasSeasonality:
+		seasonality.SeasonalPattern = "weekly"
+	default:
+		seasonality.SeasonalPattern = "none"
+	}
+
+	current := basicMetrics(row)
+	project := func(days int) models.BlogBasicMetrics {
+		views := trendAnalyzer.ProjectTotal(trend, days)
+		scale := func(value float64) float64 {
+			if row.Views == 0 {
+				return 0
+			}
+			return value * views / float64(row.Views)
+		}
+		return models.BlogBasicMetrics{
+			Views:        int(math.Round(views)),
+			Engagements:  int(math.Round(scale(float64(current.Engagements)))),
+			Leads:        int(math.Round(scale(float64(current.Leads)))),
+			Revenue:      round2(scale(current.Revenue)),
+			TimeOnPage:   current.TimeOnPage,
+			BounceRate:   current.BounceRate,
+			SocialShares: int(math.Round(scale(float64(current.SocialShares)))),
+		}
+	}
+
+	return models.BlogTrendAnalysis{
+		TrendDirection: direction,
+		TrendStrength:  strength,
+		GrowthRate:     round2(trend.TotalGrowth),
+		Seasonality:    seasonality,
+		PredictedPerformance: models.PredictedPerformance{
+			NextMonth:       project(30),
+			NextQuarter:     project(90),
+			Confidence:      round2(math.Max(trend.LinearRegression.RSquared, 0) * 100),
+			PredictionBasis: fmt.Sprintf("Linear trend of %d daily views", trend.Points),
+		},
+		PerformanceFactors: performanceFactors(row, direction, strength, trend),
+	}
+}
+
+// performanceFactors grades the post's trend, reading depth, bounce rate,
+// conversion rate and sharing
+func performanceFactors(row blogPerformanceRow, direction, strength string, trend analytics.TrendAnalysis) []models.PerformanceFactor {
+	impact := analytics.ImpactNeutral
+	switch direction {
+	case analytics.TrendUp:
+		impact = analytics.ImpactPositive
+	case analytics.TrendDown:
+		impact = analytics.ImpactNegative
+	}
+	trendStrength := map[string]string{
+		analytics.TrendStrong:   analytics.ImpactHigh,
+		analytics.TrendModerate: analytics.ImpactMedium,
+		analytics.TrendWeak:     analytics.ImpactLow,
+	}[strength]
+	factors := []models.PerformanceFactor{{
+		Factor:      "views_trend",
+		Impact:      impact,
+		Strength:    trendStrength,
+		Description: fmt.Sprintf("Daily views are %s with a %s trend", map[string]string{analytics.TrendUp: "rising", analytics.TrendDown: "falling", analytics.TrendStable: "stable"}[direction], strength),
+		Score:       round2(trend.LinearRegression.Slope),
+	}}
+
+	avgDepth, _ := engagement.ScrollHeatmap(row.Views, [4]int{row.Scroll25, row.Scroll50, row.Scroll75, row.Scroll100})
+	graded := []struct {
+		factor      string
+		description string
+		value       float64
+		good, bad   float64
+		needsViews  int
+	}{
+		{"scroll_depth", "Average scroll depth is %.0f%%", avgDepth, 50, 25, 1},
+		{"bounce_rate", "%.0f%% of views leave before a quarter of the post", engagement.BounceRate(row.Views, row.Scroll25), 40, 60, 1},
+		{"conversion_rate", "%.2f leads per 100 views", performanceCalculator.CalculateConversionRate(row.Leads, row.Views), 2, 0.5, 100},
+		{"social_shares", "%.2f shares per 100 views", percentage(row.Shares, row.Views), 1, 0.2, 100},
+	}
+	for _, metric := range graded {
+		if row.Views < metric.needsViews {
+			continue
+		}
+		impact, strength := analytics.GradeMetric(metric.value, metric.good, metric.bad)
+		factors = append(factors, models.PerformanceFactor{
+			Factor:      metric.factor,
+			Impact:      impact,
+			Strength:    strength,
+			Description: fmt.Sprintf(metric.description, metric.value),
+			Score:       round2(metric.value),
+		})
+	}
+	return factors
+}
+
+// optimizationTips suggests fixes for the post's weakest metrics, most urgent first
+func optimizationTips(row blogPerformanceRow, metrics models.BlogDetailedMetrics, trend models.BlogTrendAnalysis) []models.OptimizationTip {
+	tips := []models.OptimizationTip{}
+	if metrics.SEOMetrics.SEOScore < 60 {
+		priority := analytics.ImpactMedium
+		if metrics.SEOMetrics.SEOScore < 40 {
+			priority = analytics.ImpactHigh
+		}
+		tips = append(tips, models.OptimizationTip{
+			Category:    "seo",
+			Priority:    priority,
+			Title:       "Improve on-page SEO",
+			Description: fmt.Sprintf("The SEO score is %d/100.", metrics.SEOMetrics.SEOScore),
+			Impact:      "More organic search traffic",
+			Effort:      "medium",
+			Action:      fmt.Sprintf("Run POST /api/v1/blogs/%d/seo and work through its recommendations", row.ID),
+		})
+	}
+	if row.Views >= 100 && row.Leads == 0 {
+		tips = append(tips, models.OptimizationTip{
+			Category:    "conversion",
+			Priority:    analytics.ImpactHigh,
+			Title:       "Add a lead capture call to action",
+			Description: fmt.Sprintf("%d views produced no leads in this period.", row.Views),
+			Impact:      "Leads from existing traffic",
+			Effort:      "low",
+			Action:      "Embed an inline form or a content download offer in the post",
+		})
+	}
+	if row.Views > 0 && metrics.ScrollDepth.AvgScrollDepth < 40 {
+		tips = append(tips, models.OptimizationTip{
+			Category:    "engagement",
+			Priority:    analytics.ImpactMedium,
+			Title:       "Hook readers earlier",
+			Description: fmt.Sprintf("Readers scroll %.0f%% of the post on average and %.0f%% leave before a quarter.", metrics.ScrollDepth.AvgScrollDepth, metrics.BounceRate),
+			Impact:      "Deeper reading and more time on page",
+			Effort:      "medium",
+			Action:      "Tighten the introduction, add subheadings and move the key takeaway up",
+		})
+	}
+	if trend.TrendDirection == analytics.TrendDown {
+		tips = append(tips, models.OptimizationTip{
+			Category:    "seo",
+			Priority:    analytics.ImpactMedium,
+			Title:       "Refresh the post",
+			Description: "Daily views are falling.",
+			Impact:      "Recovered search rankings and traffic",
+			Effort:      "medium",
+			Action:      "Update outdated sections, examples and links, then republish",
+		})
+	}
+	if row.Views >= 100 && percentage(row.Shares, row.Views) < 0.2 {
+		tips = append(tips, models.OptimizationTip{
+			Category:    "social",
+			Priority:    analytics.ImpactLow,
+			Title:       "Encourage sharing",
+			Description: fmt.Sprintf("%d shares from %d views.", row.Shares, row.Views),
+			Impact:      "Referral traffic from social networks",
+			Effort:      "low",
+			Action:      "Add share buttons near the key takeaways and a quotable summary",
+		})
+	}
+	return tips
+}
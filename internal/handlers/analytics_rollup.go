@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/analytics"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BackfillRollups rebuilds the daily blog metric rollups for a range of up to
+// 400 days, e.g. after importing history or fixing data outside the service. Rebuilding replaces the days' rows, so a range can be
+// backfilled again safely.
+func (h *AnalyticsHandler) BackfillRollups(c *gin.Context) {
+	var req models.RollupBackfillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_DATE", "start_date must be YYYY-MM-DD")
+		return
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_DATE", "end_date must be YYYY-MM-DD")
+		return
+	}
+	if end.Before(start) {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_DATE_RANGE", "end_date must not be before start_date")
+		return
+	}
+	if analytics.BucketCount(start, end, analytics.GranularityDay) > analytics.MaxBuckets {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_DATE_RANGE",
+			fmt.Sprintf("The range spans more than %d days", analytics.MaxBuckets))
+		return
+	}
+
+	user, ok := middleware.GetCurrentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required", "UNAUTHENTICATED", "User context not found")
+		return
+	}
+
+	if database.GetDB() == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	days, rows, err := h.rollup.Backfill(start, end)
+	if err != nil {
+		logger.Error("Failed to backfill metric rollups", err, map[string]interface{}{
+			"start_date": req.StartDate,
+			"end_date":   req.EndDate,
+			"days_done":  days,
+		})
+		respondError(c, http.StatusInternalServerError, "Failed to backfill rollups", "DATABASE_ERROR", "Unable to rebuild metric rollups")
+		return
+	}
+
+	logger.LogBusinessEvent("metric_rollups_backfilled", "analytics", nil, map[string]interface{}{
+		"start_date":   req.StartDate,
+		"end_date":     req.EndDate,
+		"days":         days,
+		"requested_by": user.ID,
+	})
+
+	respondSuccess(c, http.StatusOK, "Metric rollups rebuilt successfully", models.RollupBackfillResponse{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Days:      days,
+		BlogRows:  rows,
+	})
+}
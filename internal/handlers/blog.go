@@ -0,0 +1,537 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/slug"
+	"blog-service/pkg/workflow"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BlogHandler handles blog post management endpoints
+type BlogHandler struct{}
+
+// NewBlogHandler creates a new blog handler instance
+func NewBlogHandler() *BlogHandler {
+	return &BlogHandler{}
+}
+
+// ListBlogs returns a paginated, filtered list of blog posts.
+// Unauthenticated callers only see published posts.
+func (h *BlogHandler) ListBlogs(c *gin.Context) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	filters, ok := blogFiltersFromQuery(c)
+	if !ok {
+		return
+	}
+
+	query := applyBlogFilters(db.Model(&models.Blog{}), filters)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error("Failed to count blogs", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve blogs", "DATABASE_ERROR", "Unable to count blog posts")
+		return
+	}
+
+	blogs := []models.Blog{}
+	if err := query.Order("created_at DESC").
+		Scopes(database.Paginate(filters.Page, filters.Limit)).
+		Preload("Tags").
+		Find(&blogs).Error; err != nil {
+		logger.Error("Failed to list blogs", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve blogs", "DATABASE_ERROR", "Unable to list blog posts")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Blogs retrieved successfully", models.PaginatedBlogsResponse{
+		Blogs:      blogs,
+		Total:      total,
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		TotalPages: totalPages(total, filters.Limit),
+	})
+}
+
+// GetBlog returns a single blog post by ID
+func (h *BlogHandler) GetBlog(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	blog, found := h.findBlog(c, id)
+	if !found {
+		return
+	}
+
+	if !canViewBlog(c, blog) {
+		respondError(c, http.StatusNotFound, "Blog not found", "BLOG_NOT_FOUND", "Blog post does not exist")
+		return
+	}
+
+	renderBlogContent(blog)
+	respondSuccess(c, http.StatusOK, "Blog retrieved successfully", blog)
+}
+
+// CreateBlog creates a new blog post authored by the current user
+func (h *BlogHandler) CreateBlog(c *gin.Context) {
+	var req models.CreateBlogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	user, ok := middleware.GetCurrentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required", "UNAUTHENTICATED", "User context not found")
+		return
+	}
+
+	if req.Status != "" && req.Status != workflow.StatusDraft {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_STATUS", "New blog posts start as draft; use the transitions endpoint to change status")
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	generateSlug := req.Slug == ""
+	if !generateSlug {
+		req.Slug = slug.Make(req.Slug)
+		taken, err := slugTaken(db, req.Slug, 0)
+		if err != nil {
+			logger.Error("Failed to check slug", err, map[string]interface{}{"slug": req.Slug})
+			respondError(c, http.StatusInternalServerError, "Failed to create blog", "DATABASE_ERROR", "Unable to check slug")
+			return
+		}
+		if taken {
+			respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another blog post already uses this slug")
+			return
+		}
+	}
+
+	if req.CategoryID != nil && *req.CategoryID == 0 {
+		req.CategoryID = nil
+	}
+	if !h.validateCategory(c, db, req.CategoryID) {
+		return
+	}
+
+	tags, found, err := loadTags(db, req.TagIDs)
+	if err != nil {
+		logger.Error("Failed to load tags", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to create blog", "DATABASE_ERROR", "Unable to load tags")
+		return
+	}
+	if !found {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_TAGS", "One or more tags do not exist")
+		return
+	}
+
+	blog := models.Blog{
+		Title:           req.Title,
+		Slug:            req.Slug,
+		Content:         req.Content,
+		Excerpt:         req.Excerpt,
+		Status:          workflow.StatusDraft,
+		AuthorID:        user.ID,
+		CategoryID:      req.CategoryID,
+		FeaturedImage:   req.FeaturedImage,
+		MetaTitle:       req.MetaTitle,
+		MetaDescription: req.MetaDescription,
+		FocusKeyword:    req.FocusKeyword,
+		LeadSource:      req.LeadSource,
+		UTMSource:       req.UTMSource,
+		UTMMedium:       req.UTMMedium,
+		UTMCampaign:     req.UTMCampaign,
+		Tags:            tags,
+	}
+	if blog.LeadSource == "" {
+		blog.LeadSource = "organic"
+	}
+
+	// A generated slug can lose a race with a concurrent insert; regenerate and retry
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		if generateSlug {
+			if blog.Slug, err = uniqueSlug(db, req.Title, 0); err != nil {
+				break
+			}
+		}
+
+		err = database.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&blog).Error; err != nil {
+				return err
+			}
+			_, err := recordRevision(tx, &blog, &user.ID, revisionFields, nil)
+			return err
+		})
+		if !generateSlug || attempt == maxSlugAttempts || !errors.Is(err, gorm.ErrDuplicatedKey) {
+			break
+		}
+		blog.ID = 0
+	}
+	logger.LogDatabaseOperation("create", "blogs", blog.ID, time.Since(start), err)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another blog post already uses this slug")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to create blog", "DATABASE_ERROR", "Unable to create blog post")
+		return
+	}
+
+	refreshSEOAnalysis(&blog, models.SEOTriggerCreated, seoTrackedFields, &user.ID)
+
+	logger.LogBusinessEvent("blog_created", "blog", blog.ID, map[string]interface{}{
+		"author_id": user.ID,
+		"status":    blog.Status,
+	})
+
+	respondSuccess(c, http.StatusCreated, "Blog created successfully", blog)
+}
+
+// UpdateBlog applies a partial update to a blog post
+func (h *BlogHandler) UpdateBlog(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	var req models.UpdateBlogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	blog, found := h.findBlog(c, id)
+	if !found {
+		return
+	}
+
+	if req.Status != nil && *req.Status != blog.Status {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "STATUS_CHANGE_NOT_ALLOWED", "Use the transitions endpoint to change blog status")
+		return
+	}
+
+	updates := blogUpdates(req)
+
+	db := database.GetDB()
+	if req.CategoryID != nil {
+		if *req.CategoryID == 0 {
+			updates["category_id"] = nil
+		} else if !h.validateCategory(c, db, req.CategoryID) {
+			return
+		}
+	}
+
+	var tags []models.Tag
+	if req.TagIDs != nil {
+		loaded, found, err := loadTags(db, *req.TagIDs)
+		if err != nil {
+			logger.Error("Failed to load tags", err, map[string]interface{}{"blog_id": blog.ID})
+			respondError(c, http.StatusInternalServerError, "Failed to update blog", "DATABASE_ERROR", "Unable to load tags")
+			return
+		}
+		if !found {
+			respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_TAGS", "One or more tags do not exist")
+			return
+		}
+		tags = loaded
+	}
+
+	// An empty slug asks for a fresh one generated from the (new) title
+	newSlug := blog.Slug
+	if req.Slug != nil {
+		if *req.Slug == "" {
+			title := blog.Title
+			if req.Title != nil {
+				title = *req.Title
+			}
+			generated, err := uniqueSlug(db, title, blog.ID)
+			if err != nil {
+				logger.Error("Failed to generate slug", err, map[string]interface{}{"blog_id": blog.ID})
+				respondError(c, http.StatusInternalServerError, "Failed to update blog", "DATABASE_ERROR", "Unable to generate slug")
+				return
+			}
+			newSlug = generated
+		} else {
+			newSlug = slug.Make(*req.Slug)
+			if newSlug != blog.Slug {
+				taken, err := slugTaken(db, newSlug, blog.ID)
+				if err != nil {
+					logger.Error("Failed to check slug", err, map[string]interface{}{"blog_id": blog.ID, "slug": newSlug})
+					respondError(c, http.StatusInternalServerError, "Failed to update blog", "DATABASE_ERROR", "Unable to check slug")
+					return
+				}
+				if taken {
+					respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another blog post already uses this slug")
+					return
+				}
+			}
+		}
+		updates["slug"] = newSlug
+	}
+	oldSlug := blog.Slug
+
+	if len(updates) == 0 && req.TagIDs == nil {
+		respondSuccess(c, http.StatusOK, "No changes applied", blog)
+		return
+	}
+
+	changed := changedRevisionFields(blog, req)
+	var userID *uint
+	if user, ok := middleware.GetCurrentUser(c); ok {
+		userID = &user.ID
+	}
+
+	start := time.Now()
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if len(changed) > 0 {
+			if err := lockBlogRevisions(tx, blog.ID); err != nil {
+				return err
+			}
+			if err := ensureBaselineRevision(tx, blog); err != nil {
+				return err
+			}
+		}
+		if newSlug != oldSlug {
+			if err := recordSlugRedirect(tx, blog.ID, oldSlug, newSlug); err != nil {
+				return err
+			}
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(blog).Omit(clause.Associations).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if req.TagIDs != nil {
+			if err := tx.Model(blog).Association("Tags").Replace(tags); err != nil {
+				return err
+			}
+		}
+		if err := tx.Preload("Tags").First(blog, blog.ID).Error; err != nil {
+			return err
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		_, err := recordRevision(tx, blog, userID, changed, nil)
+		return err
+	})
+	logger.LogDatabaseOperation("update", "blogs", blog.ID, time.Since(start), err)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another blog post already uses this slug")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to update blog", "DATABASE_ERROR", "Unable to update blog post")
+		return
+	}
+
+	if seoChanges := seoRelevantChanges(changed); len(seoChanges) > 0 {
+		refreshSEOAnalysis(blog, models.SEOTriggerContentChanged, seoChanges, userID)
+	}
+
+	logger.LogBusinessEvent("blog_updated", "blog", blog.ID, map[string]interface{}{
+		"user_id":          userID,
+		"fields":           len(updates),
+		"revision_changes": changed,
+	})
+
+	respondSuccess(c, http.StatusOK, "Blog updated successfully", blog)
+}
+
+// DeleteBlog soft-deletes a blog post
+func (h *BlogHandler) DeleteBlog(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	blog, found := h.findBlog(c, id)
+	if !found {
+		return
+	}
+
+	start := time.Now()
+	err := database.GetDB().Delete(blog).Error
+	logger.LogDatabaseOperation("delete", "blogs", blog.ID, time.Since(start), err)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to delete blog", "DATABASE_ERROR", "Unable to delete blog post")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	logger.LogBusinessEvent("blog_deleted", "blog", blog.ID, map[string]interface{}{
+		"user_id": userID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Blog post deleted successfully",
+	})
+}
+
+// findBlog loads a blog by ID and writes the error response when it cannot
+func (h *BlogHandler) findBlog(c *gin.Context, id uint) (*models.Blog, bool) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return nil, false
+	}
+
+	var blog models.Blog
+	if err := db.Preload("Tags").First(&blog, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "Blog not found", "BLOG_NOT_FOUND", "Blog post does not exist")
+			return nil, false
+		}
+		logger.Error("Failed to load blog", err, map[string]interface{}{"blog_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve blog", "DATABASE_ERROR", "Unable to load blog post")
+		return nil, false
+	}
+
+	return &blog, true
+}
+
+// validateCategory checks that a referenced category exists and writes the error response when it does not
+func (h *BlogHandler) validateCategory(c *gin.Context, db *gorm.DB, categoryID *uint) bool {
+	if categoryID == nil {
+		return true
+	}
+
+	var count int64
+	if err := db.Model(&models.Category{}).Where("id = ?", *categoryID).Count(&count).Error; err != nil {
+		logger.Error("Failed to check category", err, map[string]interface{}{"category_id": *categoryID})
+		respondError(c, http.StatusInternalServerError, "Failed to validate category", "DATABASE_ERROR", "Unable to load category")
+		return false
+	}
+	if count == 0 {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_CATEGORY", "Category does not exist")
+		return false
+	}
+	return true
+}
+
+// blogFiltersFromQuery parses list filters from the query string and writes the
+// error response when they are invalid. Callers without blog:read are limited to published posts.
+func blogFiltersFromQuery(c *gin.Context) (models.BlogFilters, bool) {
+	filters := models.BlogFilters{
+		Status:     c.Query("status"),
+		CategoryID: parseUintQuery(c, "category_id"),
+		Category:   c.Query("category"),
+		AuthorID:   parseUintQuery(c, "author_id"),
+		TagID:      parseUintQuery(c, "tag_id"),
+		Tag:        c.Query("tag"),
+		Search:     c.Query("search"),
+		Page:       parseIntQuery(c, "page", 1),
+		Limit:      parseIntQuery(c, "limit", 20),
+	}
+	filters.Page, filters.Limit = normalizePagination(filters.Page, filters.Limit)
+
+	if filters.Status != "" && !workflow.IsValidStatus(filters.Status) {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_STATUS", "Unknown blog status: "+filters.Status)
+		return filters, false
+	}
+
+	if !middleware.CanAccessResource(c, "blog", "read") {
+		filters.Status = workflow.StatusPublished
+	}
+
+	return filters, true
+}
+
+// applyBlogFilters applies list filters to a blog query
+func applyBlogFilters(query *gorm.DB, filters models.BlogFilters) *gorm.DB {
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.CategoryID != nil {
+		query = query.Where("category_id IN (SELECT sub.id FROM categories sub JOIN categories root ON sub.path LIKE CONCAT(root.path, '%') WHERE root.id = ?)", *filters.CategoryID)
+	}
+	if filters.Category != "" {
+		query = query.Where("category_id IN (SELECT sub.id FROM categories sub JOIN categories root ON sub.path LIKE CONCAT(root.path, '%') WHERE root.slug = ?)", filters.Category)
+	}
+	if filters.AuthorID != nil {
+		query = query.Where("author_id = ?", *filters.AuthorID)
+	}
+	if filters.TagID != nil {
+		query = query.Where("id IN (SELECT blog_id FROM blog_tags WHERE tag_id = ?)", *filters.TagID)
+	}
+	if filters.Tag != "" {
+		query = query.Where("id IN (SELECT blog_tags.blog_id FROM blog_tags JOIN tags ON tags.id = blog_tags.tag_id WHERE tags.slug = ?)", filters.Tag)
+	}
+	if filters.Search != "" {
+		pattern := "%" + filters.Search + "%"
+		query = query.Where("title LIKE ? OR content LIKE ?", pattern, pattern)
+	}
+	return query
+}
+
+// blogUpdates converts an update request into a column map
+func blogUpdates(req models.UpdateBlogRequest) map[string]interface{} {
+	updates := make(map[string]interface{})
+
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Slug != nil {
+		updates["slug"] = *req.Slug
+	}
+	if req.Content != nil {
+		updates["content"] = *req.Content
+	}
+	if req.Excerpt != nil {
+		updates["excerpt"] = *req.Excerpt
+	}
+	if req.CategoryID != nil {
+		updates["category_id"] = *req.CategoryID
+	}
+	if req.FeaturedImage != nil {
+		updates["featured_image"] = *req.FeaturedImage
+	}
+	if req.MetaTitle != nil {
+		updates["meta_title"] = *req.MetaTitle
+	}
+	if req.MetaDescription != nil {
+		updates["meta_description"] = *req.MetaDescription
+	}
+	if req.FocusKeyword != nil {
+		updates["focus_keyword"] = *req.FocusKeyword
+	}
+	if req.LeadSource != nil {
+		updates["lead_source"] = *req.LeadSource
+	}
+	if req.UTMSource != nil {
+		updates["utm_source"] = *req.UTMSource
+	}
+	if req.UTMMedium != nil {
+		updates["utm_medium"] = *req.UTMMedium
+	}
+	if req.UTMCampaign != nil {
+		updates["utm_campaign"] = *req.UTMCampaign
+	}
+
+	return updates
+}
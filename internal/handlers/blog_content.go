@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/content"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/seo"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contentParser is shared by all handlers; it is safe for concurrent use
+var contentParser = content.NewParser()
+
+// PreviewBlogSEO parses a blog post's content and runs the SEO analyzer on it
+// without storing the result
+func (h *BlogHandler) PreviewBlogSEO(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	blog, found := h.findBlog(c, id)
+	if !found {
+		return
+	}
+
+	doc, err := parseBlogContent(blog, c.DefaultQuery("format", content.FormatAuto))
+	if err != nil {
+		logger.Error("Failed to parse blog content", err, map[string]interface{}{"blog_id": blog.ID})
+		respondError(c, http.StatusUnprocessableEntity, "Failed to parse content", "CONTENT_PARSE_ERROR", err.Error())
+		return
+	}
+
+	analysis := seo.NewSEOAnalyzer().AnalyzeContent(blogSEOContentData(blog, doc))
+
+	respondSuccess(c, http.StatusOK, "SEO preview generated successfully", map[string]interface{}{
+		"analysis": analysis,
+		"content": map[string]interface{}{
+			"format":         doc.Format,
+			"word_count":     doc.WordCount,
+			"headings":       doc.Headings,
+			"internal_links": doc.InternalLinks,
+			"external_links": doc.ExternalLinks,
+			"images":         doc.Images,
+		},
+	})
+}
+
+// parseBlogContent renders and extracts a blog post's content
+func parseBlogContent(blog *models.Blog, format string) (*content.Document, error) {
+	return contentParser.Parse(blog.Content, content.Options{
+		Format:  format,
+		SiteURL: blogBaseURL(),
+	})
+}
+
+// renderBlogContent fills ContentHTML for readers. Parse failures are logged and
+// leave ContentHTML empty rather than failing the request.
+func renderBlogContent(blog *models.Blog) {
+	doc, err := parseBlogContent(blog, content.FormatAuto)
+	if err != nil {
+		logger.Warn("Failed to render blog content", map[string]interface{}{"blog_id": blog.ID, "error": err.Error()})
+		return
+	}
+	blog.ContentHTML = doc.HTML
+}
+
+// blogSEOContentData builds the SEO analyzer input for a blog post
+func blogSEOContentData(blog *models.Blog, doc *content.Document) seo.ContentData {
+	title := blog.MetaTitle
+	if title == "" {
+		title = blog.Title
+	}
+	postURL := blogPostURL(blog.Slug)
+
+	return doc.ContentData(seo.ContentData{
+		ID:              blog.ID,
+		Title:           title,
+		URL:             postURL,
+		MetaDescription: blog.MetaDescription,
+		PrimaryKeyword:  blog.FocusKeyword,
+		CanonicalURL:    postURL,
+	})
+}
+
+// blogBaseURL returns the public base URL of blog posts
+func blogBaseURL() string {
+	return strings.TrimRight(getEnv("BLOG_BASE_URL", "https://mejona.com/blog"), "/")
+}
+
+// blogPostURL returns the public URL of a blog post
+func blogPostURL(slug string) string {
+	return blogBaseURL() + "/" + slug
+}
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/textdiff"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// revisionFields lists the blog columns tracked by revision history
+var revisionFields = []string{"title", "content", "excerpt", "meta_title", "meta_description"}
+
+// ListRevisions returns the revision history of a blog post, newest first.
+// Content is omitted; use the diff endpoint to compare revisions.
+func (h *BlogHandler) ListRevisions(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	blog, found := h.findBlog(c, id)
+	if !found {
+		return
+	}
+
+	revisions := []models.BlogRevision{}
+	if err := database.GetDB().
+		Omit("content").
+		Where("blog_id = ?", blog.ID).
+		Order("revision_number DESC").
+		Find(&revisions).Error; err != nil {
+		logger.Error("Failed to list blog revisions", err, map[string]interface{}{"blog_id": blog.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve revisions", "DATABASE_ERROR", "Unable to load revision history")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Revisions retrieved successfully", map[string]interface{}{
+		"blog_id":   blog.ID,
+		"revisions": revisions,
+	})
+}
+
+// DiffRevisions returns a line-level diff between two revisions.
+// Defaults to comparing the latest revision with the one before it.
+func (h *BlogHandler) DiffRevisions(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	blog, found := h.findBlog(c, id)
+	if !found {
+		return
+	}
+
+	db := database.GetDB()
+
+	to := parseIntQuery(c, "to", 0)
+	if to <= 0 {
+		var latest models.BlogRevision
+		if err := db.Select("revision_number").Where("blog_id = ?", blog.ID).Order("revision_number DESC").First(&latest).Error; err != nil {
+			respondError(c, http.StatusNotFound, "Revision not found", "REVISION_NOT_FOUND", "Blog post has no revisions")
+			return
+		}
+		to = latest.RevisionNumber
+	}
+
+	from := parseIntQuery(c, "from", to-1)
+	if from <= 0 || from == to {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_REVISION", "from and to must be two different revision numbers")
+		return
+	}
+
+	fromRevision, ok := h.findRevision(c, blog.ID, from)
+	if !ok {
+		return
+	}
+	toRevision, ok := h.findRevision(c, blog.ID, to)
+	if !ok {
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Revision diff generated successfully", models.BlogRevisionDiffResponse{
+		BlogID:       blog.ID,
+		FromRevision: from,
+		ToRevision:   to,
+		Fields: map[string]models.BlogFieldDiff{
+			"title":            diffField(fromRevision.Title, toRevision.Title),
+			"content":          diffField(fromRevision.Content, toRevision.Content),
+			"excerpt":          diffField(fromRevision.Excerpt, toRevision.Excerpt),
+			"meta_title":       diffField(fromRevision.MetaTitle, toRevision.MetaTitle),
+			"meta_description": diffField(fromRevision.MetaDescription, toRevision.MetaDescription),
+		},
+	})
+}
+
+// RestoreRevision copies an old revision back onto the blog and records it as a new revision
+func (h *BlogHandler) RestoreRevision(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || number <= 0 {
+		respondError(c, http.StatusBadRequest, "Invalid revision number", "INVALID_REVISION", "Revision number must be a positive integer")
+		return
+	}
+
+	user, ok := middleware.GetCurrentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required", "UNAUTHENTICATED", "User context not found")
+		return
+	}
+
+	blog, found := h.findBlog(c, id)
+	if !found {
+		return
+	}
+
+	before := *blog
+
+	var restored *models.BlogRevision
+	err = database.Transaction(func(tx *gorm.DB) error {
+		if err := lockBlogRevisions(tx, blog.ID); err != nil {
+			return err
+		}
+
+		var source models.BlogRevision
+		if err := tx.Where("blog_id = ? AND revision_number = ?", blog.ID, number).First(&source).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(blog).Omit(clause.Associations).Updates(map[string]interface{}{
+			"title":            source.Title,
+			"content":          source.Content,
+			"excerpt":          source.Excerpt,
+			"meta_title":       source.MetaTitle,
+			"meta_description": source.MetaDescription,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(blog, blog.ID).Error; err != nil {
+			return err
+		}
+
+		revision, err := recordRevision(tx, blog, &user.ID, revisionFields, &source.RevisionNumber)
+		if err != nil {
+			return err
+		}
+		restored = revision
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "Revision not found", "REVISION_NOT_FOUND", "Revision does not exist for this blog post")
+			return
+		}
+		logger.Error("Failed to restore blog revision", err, map[string]interface{}{"blog_id": blog.ID, "revision": number})
+		respondError(c, http.StatusInternalServerError, "Failed to restore revision", "DATABASE_ERROR", "Unable to restore revision")
+		return
+	}
+
+	seoChanges := []string{}
+	if blog.Title != before.Title {
+		seoChanges = append(seoChanges, "title")
+	}
+	if blog.Content != before.Content {
+		seoChanges = append(seoChanges, "content")
+	}
+	if blog.MetaDescription != before.MetaDescription {
+		seoChanges = append(seoChanges, "meta_description")
+	}
+	if len(seoChanges) > 0 {
+		refreshSEOAnalysis(blog, models.SEOTriggerContentChanged, seoChanges, &user.ID)
+	}
+
+	logger.LogBusinessEvent("blog_revision_restored", "blog", blog.ID, map[string]interface{}{
+		"user_id":       user.ID,
+		"restored_from": number,
+		"new_revision":  restored.RevisionNumber,
+	})
+
+	respondSuccess(c, http.StatusOK, "Revision restored successfully", map[string]interface{}{
+		"blog":     blog,
+		"revision": restored,
+	})
+}
+
+// findRevision loads a revision by number and writes the error response when it cannot
+func (h *BlogHandler) findRevision(c *gin.Context, blogID uint, number int) (*models.BlogRevision, bool) {
+	var revision models.BlogRevision
+	if err := database.GetDB().Where("blog_id = ? AND revision_number = ?", blogID, number).First(&revision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "Revision not found", "REVISION_NOT_FOUND", "Revision "+strconv.Itoa(number)+" does not exist for this blog post")
+			return nil, false
+		}
+		logger.Error("Failed to load blog revision", err, map[string]interface{}{"blog_id": blogID, "revision": number})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve revision", "DATABASE_ERROR", "Unable to load revision")
+		return nil, false
+	}
+	return &revision, true
+}
+
+// lockBlogRevisions locks the blog row so concurrent saves number their
+// revisions one after the other. It must be the transaction's first read, so
+// later reads see the revisions committed by the save that held the lock.
+func lockBlogRevisions(tx *gorm.DB, blogID uint) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Blog{}, blogID).Error
+}
+
+// recordRevision snapshots the blog's tracked fields as the next revision.
+// The transaction must hold lockBlogRevisions.
+func recordRevision(tx *gorm.DB, blog *models.Blog, authorID *uint, changed []string, restoredFrom *int) (*models.BlogRevision, error) {
+	var latest int
+	if err := tx.Model(&models.BlogRevision{}).
+		Where("blog_id = ?", blog.ID).
+		Select("COALESCE(MAX(revision_number), 0)").
+		Scan(&latest).Error; err != nil {
+		return nil, err
+	}
+
+	changedFields := make(models.JSONArray, 0, len(changed))
+	for _, field := range changed {
+		changedFields = append(changedFields, field)
+	}
+
+	revision := models.BlogRevision{
+		BlogID:          blog.ID,
+		RevisionNumber:  latest + 1,
+		Title:           blog.Title,
+		Content:         blog.Content,
+		Excerpt:         blog.Excerpt,
+		MetaTitle:       blog.MetaTitle,
+		MetaDescription: blog.MetaDescription,
+		ChangedFields:   changedFields,
+		AuthorID:        authorID,
+		RestoredFrom:    restoredFrom,
+	}
+	if err := tx.Create(&revision).Error; err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+// ensureBaselineRevision snapshots a blog that predates revision history,
+// so the text being overwritten is not lost
+func ensureBaselineRevision(tx *gorm.DB, blog *models.Blog) error {
+	var count int64
+	if err := tx.Model(&models.BlogRevision{}).Where("blog_id = ?", blog.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	authorID := blog.AuthorID
+	baseline := models.BlogRevision{
+		BlogID:          blog.ID,
+		RevisionNumber:  1,
+		Title:           blog.Title,
+		Content:         blog.Content,
+		Excerpt:         blog.Excerpt,
+		MetaTitle:       blog.MetaTitle,
+		MetaDescription: blog.MetaDescription,
+		ChangedFields:   models.JSONArray{},
+		AuthorID:        &authorID,
+		CreatedAt:       blog.UpdatedAt,
+	}
+	return tx.Create(&baseline).Error
+}
+
+// changedRevisionFields returns the tracked fields an update request actually changes
+func changedRevisionFields(blog *models.Blog, req models.UpdateBlogRequest) []string {
+	changed := []string{}
+	if req.Title != nil && *req.Title != blog.Title {
+		changed = append(changed, "title")
+	}
+	if req.Content != nil && *req.Content != blog.Content {
+		changed = append(changed, "content")
+	}
+	if req.Excerpt != nil && *req.Excerpt != blog.Excerpt {
+		changed = append(changed, "excerpt")
+	}
+	if req.MetaTitle != nil && *req.MetaTitle != blog.MetaTitle {
+		changed = append(changed, "meta_title")
+	}
+	if req.MetaDescription != nil && *req.MetaDescription != blog.MetaDescription {
+		changed = append(changed, "meta_description")
+	}
+	return changed
+}
+
+// diffField builds the diff summary for one field
+func diffField(oldValue, newValue string) models.BlogFieldDiff {
+	lines := textdiff.Diff(oldValue, newValue)
+	added, removed := textdiff.Stats(lines)
+	return models.BlogFieldDiff{
+		Changed: oldValue != newValue,
+		Added:   added,
+		Removed: removed,
+		Lines:   lines,
+	}
+}
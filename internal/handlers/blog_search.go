@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/search"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Full-text match expressions against the idx_blogs_fulltext index
+const (
+	matchNaturalLanguage = "MATCH(title, excerpt, content) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	matchBoolean         = "MATCH(title, excerpt, content) AGAINST (? IN BOOLEAN MODE)"
+)
+
+// blogSearchHit holds a matching blog ID with its relevance score
+type blogSearchHit struct {
+	ID        uint
+	Relevance float64
+}
+
+// facetRow holds a grouped facet count
+type facetRow struct {
+	Value *string
+	Count int64
+}
+
+// SearchBlogs runs a relevance-ranked full-text search over title, excerpt and
+// content. Accepts the blog list filters; unauthenticated callers only search
+// published posts. mode=boolean enables MySQL boolean operators (+word -word "phrase").
+func (h *BlogHandler) SearchBlogs(c *gin.Context) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "MISSING_QUERY", "Query parameter q is required")
+		return
+	}
+
+	mode := c.DefaultQuery("mode", "natural")
+	match := matchNaturalLanguage
+	switch mode {
+	case "natural":
+	case "boolean":
+		match = matchBoolean
+	default:
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_MODE", "mode must be natural or boolean")
+		return
+	}
+
+	filters, ok := blogFiltersFromQuery(c)
+	if !ok {
+		return
+	}
+	filters.Search = "" // q replaces the LIKE search of the list endpoint
+
+	matched := func() *gorm.DB {
+		return applyBlogFilters(db.Model(&models.Blog{}), filters).Where(match, q)
+	}
+
+	var total int64
+	if err := matched().Count(&total).Error; err != nil {
+		if mode == "boolean" && database.IsSyntaxError(err) {
+			respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_QUERY", "q is not a valid boolean search: check operators, quotes and parentheses")
+			return
+		}
+		logger.Error("Failed to count search results", err, map[string]interface{}{"query": q})
+		respondError(c, http.StatusInternalServerError, "Search failed", "DATABASE_ERROR", "Unable to run search")
+		return
+	}
+
+	hits := []blogSearchHit{}
+	if err := matched().
+		Select("id, "+match+" AS relevance", q).
+		Order("relevance DESC, published_at DESC").
+		Scopes(database.Paginate(filters.Page, filters.Limit)).
+		Scan(&hits).Error; err != nil {
+		logger.Error("Failed to run search", err, map[string]interface{}{"query": q})
+		respondError(c, http.StatusInternalServerError, "Search failed", "DATABASE_ERROR", "Unable to run search")
+		return
+	}
+
+	results, err := h.searchResults(db, hits, search.Terms(q))
+	if err != nil {
+		logger.Error("Failed to load search results", err, map[string]interface{}{"query": q})
+		respondError(c, http.StatusInternalServerError, "Search failed", "DATABASE_ERROR", "Unable to load search results")
+		return
+	}
+
+	facets, err := h.searchFacets(db, matched)
+	if err != nil {
+		logger.Error("Failed to compute search facets", err, map[string]interface{}{"query": q})
+		respondError(c, http.StatusInternalServerError, "Search failed", "DATABASE_ERROR", "Unable to compute facets")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Search completed successfully", models.BlogSearchResponse{
+		Query:      q,
+		Mode:       mode,
+		Results:    results,
+		Facets:     facets,
+		Total:      total,
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		TotalPages: totalPages(total, filters.Limit),
+	})
+}
+
+// searchResults loads the blogs for ranked hits and builds highlighted results in rank order
+func (h *BlogHandler) searchResults(db *gorm.DB, hits []blogSearchHit, terms []string) ([]models.BlogSearchResult, error) {
+	results := []models.BlogSearchResult{}
+	if len(hits) == 0 {
+		return results, nil
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+
+	blogs := []models.Blog{}
+	if err := db.Preload("Tags").Where("id IN ?", ids).Find(&blogs).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Blog, len(blogs))
+	for i := range blogs {
+		byID[blogs[i].ID] = &blogs[i]
+	}
+
+	for _, hit := range hits {
+		blog, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+
+		source := blog.Content
+		if source == "" {
+			source = blog.Excerpt
+		}
+
+		results = append(results, models.BlogSearchResult{
+			ID:            blog.ID,
+			Title:         blog.Title,
+			Slug:          blog.Slug,
+			Excerpt:       blog.Excerpt,
+			Status:        blog.Status,
+			AuthorID:      blog.AuthorID,
+			CategoryID:    blog.CategoryID,
+			FeaturedImage: blog.FeaturedImage,
+			PublishedAt:   blog.PublishedAt,
+			Tags:          blog.Tags,
+			Relevance:     hit.Relevance,
+			Highlight:     search.Highlight(blog.Title, terms),
+			Snippet:       search.Snippet(search.PlainText(source), terms, search.DefaultSnippetLength),
+		})
+	}
+
+	return results, nil
+}
+
+// searchFacets counts all matches (ignoring pagination) by category, author and status
+func (h *BlogHandler) searchFacets(db *gorm.DB, matched func() *gorm.DB) (models.BlogSearchFacets, error) {
+	facets := models.BlogSearchFacets{
+		Categories: []models.SearchFacet{},
+		Authors:    []models.SearchFacet{},
+		Statuses:   []models.SearchFacet{},
+	}
+
+	groups := []struct {
+		column string
+		target *[]models.SearchFacet
+	}{
+		{"category_id", &facets.Categories},
+		{"author_id", &facets.Authors},
+		{"status", &facets.Statuses},
+	}
+
+	for _, group := range groups {
+		rows := []facetRow{}
+		if err := matched().
+			Select("CAST(" + group.column + " AS CHAR) AS value, COUNT(*) AS count").
+			Group(group.column).
+			Order("count DESC").
+			Scan(&rows).Error; err != nil {
+			return facets, err
+		}
+		for _, row := range rows {
+			facet := models.SearchFacet{Count: row.Count}
+			if row.Value != nil {
+				facet.Value = *row.Value
+			}
+			*group.target = append(*group.target, facet)
+		}
+	}
+
+	// Label category facets with category names; uncategorized posts have an empty value
+	categoryIDs := []uint{}
+	for _, facet := range facets.Categories {
+		if id, err := strconv.ParseUint(facet.Value, 10, 64); err == nil {
+			categoryIDs = append(categoryIDs, uint(id))
+		}
+	}
+	if len(categoryIDs) > 0 {
+		categories := []models.Category{}
+		if err := db.Select("id, name").Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+			return facets, err
+		}
+		names := make(map[string]string, len(categories))
+		for _, category := range categories {
+			names[strconv.FormatUint(uint64(category.ID), 10)] = category.Name
+		}
+		for i := range facets.Categories {
+			facets.Categories[i].Label = names[facets.Categories[i].Value]
+		}
+	}
+
+	return facets, nil
+}
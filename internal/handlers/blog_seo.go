@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/content"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/seo"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// seoTrackedFields lists the blog columns whose changes trigger a new SEO analysis
+var seoTrackedFields = []string{"title", "content", "meta_description"}
+
+// AnalyzeBlogSEO runs the SEO analyzer on a blog post, stores the result in
+// seo_analyses and updates the post's SEO score
+func (h *BlogHandler) AnalyzeBlogSEO(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	blog, found := h.findBlog(c, id)
+	if !found {
+		return
+	}
+
+	var userID *uint
+	if user, ok := middleware.GetCurrentUser(c); ok {
+		userID = &user.ID
+	}
+
+	var record *models.BlogSEOAnalysis
+	err := database.Transaction(func(tx *gorm.DB) error {
+		var err error
+		record, err = recordSEOAnalysis(tx, blog, models.SEOTriggerManual, nil, userID)
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to run SEO analysis", err, map[string]interface{}{"blog_id": blog.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to run SEO analysis", "SEO_ANALYSIS_FAILED", "Unable to analyze and store SEO results")
+		return
+	}
+
+	logger.LogBusinessEvent("blog_seo_analyzed", "blog", blog.ID, map[string]interface{}{
+		"user_id":      userID,
+		"score":        record.OverallScore,
+		"score_change": record.ScoreChange,
+	})
+
+	respondSuccess(c, http.StatusCreated, "SEO analysis completed successfully", record)
+}
+
+// SEOHistory returns a blog post's stored SEO analyses, newest first.
+// Full analyses are omitted unless include_analysis=true.
+func (h *BlogHandler) SEOHistory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	blog, found := h.findBlog(c, id)
+	if !found {
+		return
+	}
+
+	_, limit := normalizePagination(1, parseIntQuery(c, "limit", 50))
+
+	query := database.GetDB().Where("blog_id = ?", blog.ID).Order("created_at DESC, id DESC").Limit(limit)
+	if c.Query("include_analysis") != "true" {
+		query = query.Omit("analysis")
+	}
+
+	analyses := []models.BlogSEOAnalysis{}
+	if err := query.Find(&analyses).Error; err != nil {
+		logger.Error("Failed to load SEO history", err, map[string]interface{}{"blog_id": blog.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve SEO history", "DATABASE_ERROR", "Unable to load SEO analyses")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "SEO history retrieved successfully", models.SEOScoreHistoryResponse{
+		BlogID:       blog.ID,
+		CurrentScore: blog.SEOScore,
+		Analyses:     analyses,
+	})
+}
+
+// recordSEOAnalysis analyzes a blog post, stores the analysis and syncs blogs.seo_score
+func recordSEOAnalysis(tx *gorm.DB, blog *models.Blog, trigger string, changed []string, userID *uint) (*models.BlogSEOAnalysis, error) {
+	doc, err := parseBlogContent(blog, content.FormatAuto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse content: %v", err)
+	}
+
+	analysis := seo.NewSEOAnalyzer().AnalyzeContent(blogSEOContentData(blog, doc))
+
+	var previous models.BlogSEOAnalysis
+	scoreChange := 0
+	err = tx.Select("overall_score").Where("blog_id = ?", blog.ID).Order("created_at DESC, id DESC").First(&previous).Error
+	switch {
+	case err == nil:
+		scoreChange = analysis.OverallScore - previous.OverallScore
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	changedFields := make(models.JSONArray, 0, len(changed))
+	for _, field := range changed {
+		changedFields = append(changedFields, field)
+	}
+
+	stored := models.SEOAnalysisJSON(analysis)
+	record := models.BlogSEOAnalysis{
+		BlogID:        blog.ID,
+		OverallScore:  analysis.OverallScore,
+		ScoreChange:   scoreChange,
+		WordCount:     doc.WordCount,
+		Trigger:       trigger,
+		ChangedFields: changedFields,
+		TriggeredBy:   userID,
+		Analysis:      &stored,
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return nil, err
+	}
+
+	// The score is derived data, so it does not bump updated_at
+	if err := tx.Model(&models.Blog{}).Where("id = ?", blog.ID).UpdateColumn("seo_score", analysis.OverallScore).Error; err != nil {
+		return nil, err
+	}
+	blog.SEOScore = float64(analysis.OverallScore)
+
+	return &record, nil
+}
+
+// refreshSEOAnalysis re-analyzes a blog post after a content change when
+// SEO_ANALYSIS_ENABLED is on. Failures are logged and do not fail the caller.
+func refreshSEOAnalysis(blog *models.Blog, trigger string, changed []string, userID *uint) {
+	if getEnv("SEO_ANALYSIS_ENABLED", "true") != "true" {
+		return
+	}
+
+	err := database.Transaction(func(tx *gorm.DB) error {
+		_, err := recordSEOAnalysis(tx, blog, trigger, changed, userID)
+		return err
+	})
+	if err != nil {
+		logger.Warn("Automatic SEO analysis failed", map[string]interface{}{
+			"blog_id": blog.ID,
+			"trigger": trigger,
+			"error":   err.Error(),
+		})
+	}
+}
+
+// seoRelevantChanges filters changed fields down to those that affect SEO analysis
+func seoRelevantChanges(changed []string) []string {
+	relevant := []string{}
+	for _, field := range changed {
+		for _, tracked := range seoTrackedFields {
+			if field == tracked {
+				relevant = append(relevant, field)
+			}
+		}
+	}
+	return relevant
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/slug"
+	"blog-service/pkg/workflow"
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxSlugAttempts bounds retries when a generated slug loses an insert race
+const maxSlugAttempts = 3
+
+// GetBlogBySlug returns a blog post by its slug. Retired slugs answer with a
+// 301 pointing at the post's current slug, preserving the query string.
+func (h *BlogHandler) GetBlogBySlug(c *gin.Context) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	requested := c.Param("slug")
+
+	var blog models.Blog
+	err := db.Preload("Tags").Where("slug = ?", requested).First(&blog).Error
+	if err == nil {
+		if !canViewBlog(c, &blog) {
+			respondError(c, http.StatusNotFound, "Blog not found", "BLOG_NOT_FOUND", "Blog post does not exist")
+			return
+		}
+		renderBlogContent(&blog)
+		respondSuccess(c, http.StatusOK, "Blog retrieved successfully", blog)
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Error("Failed to load blog by slug", err, map[string]interface{}{"slug": requested})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve blog", "DATABASE_ERROR", "Unable to load blog post")
+		return
+	}
+
+	var redirect models.BlogSlugRedirect
+	if err := db.Where("old_slug = ?", requested).First(&redirect).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error("Failed to load slug redirect", err, map[string]interface{}{"slug": requested})
+			respondError(c, http.StatusInternalServerError, "Failed to retrieve blog", "DATABASE_ERROR", "Unable to load blog post")
+			return
+		}
+		respondError(c, http.StatusNotFound, "Blog not found", "BLOG_NOT_FOUND", "Blog post does not exist")
+		return
+	}
+
+	if err := db.First(&blog, redirect.BlogID).Error; err != nil || !canViewBlog(c, &blog) {
+		respondError(c, http.StatusNotFound, "Blog not found", "BLOG_NOT_FOUND", "Blog post does not exist")
+		return
+	}
+
+	location := path.Join(path.Dir(c.Request.URL.Path), blog.Slug)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+
+	c.Header("Location", location)
+	respondSuccess(c, http.StatusMovedPermanently, "Blog post has moved", map[string]interface{}{
+		"blog_id":  blog.ID,
+		"slug":     blog.Slug,
+		"location": location,
+	})
+}
+
+// canViewBlog reports whether the caller may see the blog; drafts are hidden from the public
+func canViewBlog(c *gin.Context, blog *models.Blog) bool {
+	return blog.Status == workflow.StatusPublished || middleware.CanAccessResource(c, "blog", "read")
+}
+
+// uniqueSlug generates a slug from text, appending -2, -3, ... until it is free.
+// Slugs held by other posts (including soft-deleted ones) and their redirects count as taken.
+func uniqueSlug(db *gorm.DB, text string, excludeID uint) (string, error) {
+	base := slug.Make(text)
+	suffixed := slug.Stem(base) + "-%"
+
+	var used []string
+	if err := db.Unscoped().Model(&models.Blog{}).
+		Where("(slug = ? OR slug LIKE ?) AND id <> ?", base, suffixed, excludeID).
+		Pluck("slug", &used).Error; err != nil {
+		return "", err
+	}
+
+	var redirected []string
+	if err := db.Model(&models.BlogSlugRedirect{}).
+		Where("(old_slug = ? OR old_slug LIKE ?) AND blog_id <> ?", base, suffixed, excludeID).
+		Pluck("old_slug", &redirected).Error; err != nil {
+		return "", err
+	}
+
+	return slug.Unique(base, append(used, redirected...)), nil
+}
+
+// slugTaken checks whether a slug is used by another blog, including soft-deleted
+// ones, or is reserved as a redirect to another blog
+func slugTaken(db *gorm.DB, value string, excludeID uint) (bool, error) {
+	var count int64
+	query := db.Unscoped().Model(&models.Blog{}).Where("slug = ?", value)
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	if err := db.Model(&models.BlogSlugRedirect{}).
+		Where("old_slug = ? AND blog_id <> ?", value, excludeID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// recordSlugRedirect retires oldSlug in favour of newSlug for a blog. A post
+// moving back to one of its own retired slugs reclaims it from the redirect table.
+func recordSlugRedirect(tx *gorm.DB, blogID uint, oldSlug, newSlug string) error {
+	if err := tx.Where("old_slug = ? AND blog_id = ?", newSlug, blogID).Delete(&models.BlogSlugRedirect{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "old_slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"blog_id"}),
+	}).Create(&models.BlogSlugRedirect{OldSlug: oldSlug, BlogID: blogID}).Error
+}
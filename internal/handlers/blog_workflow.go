@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errStatusChanged is returned when a blog's status changed between read and write
+var errStatusChanged = errors.New("blog status changed concurrently")
+
+// TransitionBlog moves a blog post to a new workflow status
+func (h *BlogHandler) TransitionBlog(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	var req models.BlogTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	user, ok := middleware.GetCurrentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required", "UNAUTHENTICATED", "User context not found")
+		return
+	}
+
+	blog, found := h.findBlog(c, id)
+	if !found {
+		return
+	}
+
+	if err := workflow.Authorize(user.Role, blog.Status, req.Status); err != nil {
+		switch {
+		case errors.Is(err, workflow.ErrInvalidStatus):
+			respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_STATUS", err.Error())
+		case errors.Is(err, workflow.ErrPermissionDenied):
+			logger.LogSecurityEvent("insufficient_permissions", &user.ID, c.ClientIP(), map[string]interface{}{
+				"role":        user.Role,
+				"from_status": blog.Status,
+				"to_status":   req.Status,
+				"path":        c.Request.URL.Path,
+			})
+			respondError(c, http.StatusForbidden, "Insufficient permissions", "INSUFFICIENT_PERMISSIONS", err.Error())
+		default:
+			respondError(c, http.StatusConflict, "Status transition not allowed", "INVALID_TRANSITION", err.Error())
+		}
+		return
+	}
+
+	if req.Status == workflow.StatusScheduled {
+		if req.ScheduledAt == nil || !req.ScheduledAt.After(time.Now()) {
+			respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_SCHEDULE", "scheduled_at must be a future timestamp")
+			return
+		}
+	}
+
+	fromStatus := blog.Status
+	err := database.Transaction(func(tx *gorm.DB) error {
+		return applyStatusTransition(tx, blog, req.Status, &user.ID, req.Notes, req.ScheduledAt)
+	})
+	if err != nil {
+		if errors.Is(err, errStatusChanged) {
+			respondError(c, http.StatusConflict, "Status transition not allowed", "STATUS_CHANGED", "Blog status was changed by another request, reload and retry")
+			return
+		}
+		logger.Error("Failed to transition blog status", err, map[string]interface{}{"blog_id": blog.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to update blog status", "DATABASE_ERROR", "Unable to update blog status")
+		return
+	}
+
+	logger.LogBusinessEvent("blog_status_changed", "blog", blog.ID, map[string]interface{}{
+		"from_status": fromStatus,
+		"to_status":   blog.Status,
+		"user_id":     user.ID,
+	})
+
+	respondSuccess(c, http.StatusOK, "Blog status updated successfully", blog)
+}
+
+// ListTransitions returns a blog's status audit trail and the moves available to the caller
+func (h *BlogHandler) ListTransitions(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	blog, found := h.findBlog(c, id)
+	if !found {
+		return
+	}
+
+	history := []models.BlogStatusTransition{}
+	if err := database.GetDB().Where("blog_id = ?", blog.ID).Order("created_at DESC, id DESC").Find(&history).Error; err != nil {
+		logger.Error("Failed to list blog transitions", err, map[string]interface{}{"blog_id": blog.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve status history", "DATABASE_ERROR", "Unable to load status history")
+		return
+	}
+
+	role, _ := middleware.GetUserRole(c)
+
+	respondSuccess(c, http.StatusOK, "Status history retrieved successfully", map[string]interface{}{
+		"blog_id":   blog.ID,
+		"status":    blog.Status,
+		"history":   history,
+		"available": workflow.AvailableTransitions(role, blog.Status),
+	})
+}
+
+// applyStatusTransition updates the blog row and writes the audit record.
+// The update is guarded on the current status so concurrent transitions cannot both win.
+func applyStatusTransition(tx *gorm.DB, blog *models.Blog, to string, userID *uint, notes string, scheduledAt *time.Time) error {
+	from := blog.Status
+	now := time.Now().UTC()
+	if to != workflow.StatusScheduled {
+		scheduledAt = nil
+	}
+
+	updates := map[string]interface{}{
+		"status": to,
+	}
+
+	switch to {
+	case workflow.StatusScheduled:
+		updates["scheduled_at"] = scheduledAt.UTC()
+	case workflow.StatusPublished:
+		updates["scheduled_at"] = nil
+		if blog.PublishedAt == nil {
+			updates["published_at"] = now
+		}
+	default:
+		updates["scheduled_at"] = nil
+	}
+
+	result := tx.Model(&models.Blog{}).
+		Where("id = ? AND status = ?", blog.ID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errStatusChanged
+	}
+
+	transition := models.BlogStatusTransition{
+		BlogID:      blog.ID,
+		FromStatus:  from,
+		ToStatus:    to,
+		UserID:      userID,
+		Notes:       notes,
+		ScheduledAt: scheduledAt,
+	}
+	if err := tx.Create(&transition).Error; err != nil {
+		return err
+	}
+
+	return tx.First(blog, blog.ID).Error
+}
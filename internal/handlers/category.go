@@ -0,0 +1,455 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/slug"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errCategoryCycle is returned when a category would be moved under its own subtree
+var errCategoryCycle = errors.New("category cannot be moved under itself or its descendants")
+
+// CategoryHandler handles blog category endpoints
+type CategoryHandler struct{}
+
+// NewCategoryHandler creates a new category handler instance
+func NewCategoryHandler() *CategoryHandler {
+	return &CategoryHandler{}
+}
+
+// ListCategories returns all categories ordered by hierarchy.
+// Pass format=tree to receive nested children instead of a flat list.
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	categories := []models.Category{}
+	if err := categoryQuery(c, db).Order("categories.path ASC").Find(&categories).Error; err != nil {
+		logger.Error("Failed to list categories", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve categories", "DATABASE_ERROR", "Unable to list categories")
+		return
+	}
+
+	if c.Query("format") == "tree" {
+		respondSuccess(c, http.StatusOK, "Categories retrieved successfully", buildCategoryTree(categories))
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Categories retrieved successfully", categories)
+}
+
+// GetCategory returns a category with its ancestors and direct children
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid category ID", "INVALID_ID", "Category ID must be a positive integer")
+		return
+	}
+
+	category, found := h.findCategory(c, id)
+	if !found {
+		return
+	}
+
+	db := database.GetDB()
+
+	ancestors := []models.Category{}
+	if ids := categoryAncestorIDs(category); len(ids) > 0 {
+		if err := db.Where("id IN ?", ids).Order("depth ASC").Find(&ancestors).Error; err != nil {
+			logger.Error("Failed to load category ancestors", err, map[string]interface{}{"category_id": id})
+			respondError(c, http.StatusInternalServerError, "Failed to retrieve category", "DATABASE_ERROR", "Unable to load category ancestors")
+			return
+		}
+	}
+
+	children := []models.Category{}
+	if err := categoryQuery(c, db).Where("categories.parent_id = ?", category.ID).Order("categories.name ASC").Find(&children).Error; err != nil {
+		logger.Error("Failed to load category children", err, map[string]interface{}{"category_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve category", "DATABASE_ERROR", "Unable to load category children")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Category retrieved successfully", map[string]interface{}{
+		"category":  category,
+		"ancestors": ancestors,
+		"children":  children,
+	})
+}
+
+// CreateCategory creates a new category, optionally under a parent
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req models.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	var parent *models.Category
+	if req.ParentID != nil && *req.ParentID != 0 {
+		var p models.Category
+		if err := db.First(&p, *req.ParentID).Error; err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_PARENT", "Parent category does not exist")
+			return
+		}
+		parent = &p
+	}
+
+	categorySlug, taken, err := resolveTaxonomySlug(db, &models.Category{}, req.Slug, req.Name, 0)
+	if err != nil {
+		logger.Error("Failed to generate category slug", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to create category", "DATABASE_ERROR", "Unable to generate slug")
+		return
+	}
+	if taken {
+		respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another category already uses this slug")
+		return
+	}
+
+	category := models.Category{
+		Name:        req.Name,
+		Slug:        categorySlug,
+		Description: req.Description,
+	}
+	if parent != nil {
+		category.ParentID = &parent.ID
+		category.Depth = parent.Depth + 1
+	}
+
+	start := time.Now()
+	err = database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&category).Error; err != nil {
+			return err
+		}
+		category.Path = categoryPath(parent, category.ID)
+		return tx.Model(&category).Update("path", category.Path).Error
+	})
+	logger.LogDatabaseOperation("create", "categories", category.ID, time.Since(start), err)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another category already uses this slug")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to create category", "DATABASE_ERROR", "Unable to create category")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	logger.LogBusinessEvent("category_created", "category", category.ID, map[string]interface{}{
+		"user_id":   userID,
+		"parent_id": category.ParentID,
+	})
+
+	respondSuccess(c, http.StatusCreated, "Category created successfully", category)
+}
+
+// UpdateCategory renames, re-slugs or moves a category. Moving a category
+// carries its whole subtree along.
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid category ID", "INVALID_ID", "Category ID must be a positive integer")
+		return
+	}
+
+	var req models.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	category, found := h.findCategory(c, id)
+	if !found {
+		return
+	}
+
+	db := database.GetDB()
+	updates := make(map[string]interface{})
+
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Slug != nil {
+		name := category.Name
+		if req.Name != nil {
+			name = *req.Name
+		}
+		categorySlug, taken, err := resolveTaxonomySlug(db, &models.Category{}, *req.Slug, name, category.ID)
+		if err != nil {
+			logger.Error("Failed to generate category slug", err, map[string]interface{}{"category_id": id})
+			respondError(c, http.StatusInternalServerError, "Failed to update category", "DATABASE_ERROR", "Unable to generate slug")
+			return
+		}
+		if taken {
+			respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another category already uses this slug")
+			return
+		}
+		updates["slug"] = categorySlug
+	}
+
+	// Resolve the new parent; 0 moves the category to the top level
+	var newParent *models.Category
+	moving := false
+	if req.ParentID != nil {
+		currentParent := uint(0)
+		if category.ParentID != nil {
+			currentParent = *category.ParentID
+		}
+		if *req.ParentID != currentParent {
+			moving = true
+			if *req.ParentID != 0 {
+				var p models.Category
+				if err := db.First(&p, *req.ParentID).Error; err != nil {
+					respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_PARENT", "Parent category does not exist")
+					return
+				}
+				if strings.HasPrefix(p.Path, category.Path) {
+					respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_PARENT", errCategoryCycle.Error())
+					return
+				}
+				newParent = &p
+			}
+		}
+	}
+
+	if len(updates) == 0 && !moving {
+		respondSuccess(c, http.StatusOK, "No changes applied", category)
+		return
+	}
+
+	start := time.Now()
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if moving {
+			if err := moveCategory(tx, category, newParent); err != nil {
+				return err
+			}
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(category).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return tx.First(category, category.ID).Error
+	})
+	logger.LogDatabaseOperation("update", "categories", category.ID, time.Since(start), err)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another category already uses this slug")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to update category", "DATABASE_ERROR", "Unable to update category")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	logger.LogBusinessEvent("category_updated", "category", category.ID, map[string]interface{}{
+		"user_id": userID,
+		"fields":  len(updates),
+		"moved":   moving,
+	})
+
+	respondSuccess(c, http.StatusOK, "Category updated successfully", category)
+}
+
+// DeleteCategory deletes a leaf category and detaches its blog posts
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid category ID", "INVALID_ID", "Category ID must be a positive integer")
+		return
+	}
+
+	category, found := h.findCategory(c, id)
+	if !found {
+		return
+	}
+
+	db := database.GetDB()
+
+	var children int64
+	if err := db.Model(&models.Category{}).Where("parent_id = ?", category.ID).Count(&children).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to delete category", "DATABASE_ERROR", "Unable to check subcategories")
+		return
+	}
+	if children > 0 {
+		respondError(c, http.StatusConflict, "Category has subcategories", "CATEGORY_HAS_CHILDREN", "Move or delete subcategories first")
+		return
+	}
+
+	var detached int64
+	start := time.Now()
+	err := database.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&models.Blog{}).Where("category_id = ?", category.ID).Update("category_id", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		detached = result.RowsAffected
+		return tx.Delete(category).Error
+	})
+	logger.LogDatabaseOperation("delete", "categories", category.ID, time.Since(start), err)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to delete category", "DATABASE_ERROR", "Unable to delete category")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	logger.LogBusinessEvent("category_deleted", "category", category.ID, map[string]interface{}{
+		"user_id":        userID,
+		"detached_blogs": detached,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Category deleted successfully",
+	})
+}
+
+// findCategory loads a category by ID and writes the error response when it cannot
+func (h *CategoryHandler) findCategory(c *gin.Context, id uint) (*models.Category, bool) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return nil, false
+	}
+
+	var category models.Category
+	if err := db.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "Category not found", "CATEGORY_NOT_FOUND", "Category does not exist")
+			return nil, false
+		}
+		logger.Error("Failed to load category", err, map[string]interface{}{"category_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve category", "DATABASE_ERROR", "Unable to load category")
+		return nil, false
+	}
+
+	return &category, true
+}
+
+// categoryQuery selects categories with their blog counts. Public callers
+// only count published posts.
+func categoryQuery(c *gin.Context, db *gorm.DB) *gorm.DB {
+	countSQL := "(SELECT COUNT(*) FROM blogs WHERE blogs.category_id = categories.id AND blogs.deleted_at IS NULL"
+	if !middleware.CanAccessResource(c, "blog", "read") {
+		countSQL += " AND blogs.status = 'published'"
+	}
+	countSQL += ") AS blog_count"
+
+	return db.Model(&models.Category{}).Select("categories.*, " + countSQL)
+}
+
+// moveCategory re-parents a category and rewrites the paths and depths of its subtree
+func moveCategory(tx *gorm.DB, category *models.Category, parent *models.Category) error {
+	oldPath := category.Path
+	newPath := categoryPath(parent, category.ID)
+
+	depth := 0
+	var parentID *uint
+	if parent != nil {
+		depth = parent.Depth + 1
+		parentID = &parent.ID
+	}
+	depthDelta := depth - category.Depth
+
+	if err := tx.Model(&models.Category{}).Where("id = ?", category.ID).Updates(map[string]interface{}{
+		"parent_id": parentID,
+	}).Error; err != nil {
+		return err
+	}
+
+	// Rewrites the category itself and every descendant sharing its path prefix
+	return tx.Model(&models.Category{}).
+		Where("path LIKE ?", oldPath+"%").
+		Updates(map[string]interface{}{
+			"path":  gorm.Expr("CONCAT(?, SUBSTRING(path, ?))", newPath, len(oldPath)+1),
+			"depth": gorm.Expr("depth + ?", depthDelta),
+		}).Error
+}
+
+// categoryPath builds the materialized path for a category under parent
+func categoryPath(parent *models.Category, id uint) string {
+	if parent == nil {
+		return fmt.Sprintf("/%d/", id)
+	}
+	return fmt.Sprintf("%s%d/", parent.Path, id)
+}
+
+// categoryAncestorIDs returns the IDs of a category's ancestors from its path
+func categoryAncestorIDs(category *models.Category) []uint {
+	ids := []uint{}
+	for _, part := range strings.Split(strings.Trim(category.Path, "/"), "/") {
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil || uint(id) == category.ID {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids
+}
+
+// buildCategoryTree nests a path-ordered category list under its roots
+func buildCategoryTree(categories []models.Category) []*models.CategoryTreeNode {
+	nodes := make(map[uint]*models.CategoryTreeNode, len(categories))
+	roots := []*models.CategoryTreeNode{}
+
+	for _, category := range categories {
+		nodes[category.ID] = &models.CategoryTreeNode{Category: category, Children: []*models.CategoryTreeNode{}}
+	}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID != nil {
+			if parent, ok := nodes[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	return roots
+}
+
+// resolveTaxonomySlug returns the slug for a category or tag. An explicit slug is
+// normalized and reported as taken when another row uses it; an empty one is
+// generated from name with a numeric suffix on collision.
+func resolveTaxonomySlug(db *gorm.DB, model interface{}, requested, name string, excludeID uint) (string, bool, error) {
+	if requested != "" {
+		value := slug.Make(requested)
+		var count int64
+		if err := db.Model(model).Where("slug = ? AND id <> ?", value, excludeID).Count(&count).Error; err != nil {
+			return "", false, err
+		}
+		return value, count > 0, nil
+	}
+
+	base := slug.Make(name)
+	var used []string
+	if err := db.Model(model).
+		Where("(slug = ? OR slug LIKE ?) AND id <> ?", base, slug.Stem(base)+"-%", excludeID).
+		Pluck("slug", &used).Error; err != nil {
+		return "", false, err
+	}
+	return slug.Unique(base, used), false, nil
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/engagement"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/tracking"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+// transparentGIF is a 1x1 transparent GIF served by the tracking pixel
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// EngagementHandler records view, scroll, like and share counters. Hits are
+// deduplicated in memory, likes in blog_likes, and buffered; a worker writes
+// the buffer in batches.
+type EngagementHandler struct {
+	buffer  *engagement.Buffer
+	deduper *engagement.Deduper
+}
+
+// NewEngagementHandler creates a new engagement handler writing to buffer
+func NewEngagementHandler(buffer *engagement.Buffer) *EngagementHandler {
+	window, err := time.ParseDuration(getEnv("ENGAGEMENT_DEDUPE_WINDOW", "24h"))
+	if err != nil || window <= 0 {
+		window = 24 * time.Hour
+	}
+	return &EngagementHandler{
+		buffer:  buffer,
+		deduper: engagement.NewDeduper(window, 0),
+	}
+}
+
+// Pixel records one engagement event from query parameters and always answers
+// with a 1x1 transparent GIF, so a broken embed never shows on the page.
+// Automated traffic gets the GIF but is not counted.
+// Likes and shares are not counted: any page can embed a GET, so they need
+// the beacon. Parameters: blog_id, event (view, scroll), depth, session_id.
+func (h *EngagementHandler) Pixel(c *gin.Context) {
+	blogID, err := strconv.ParseUint(c.Query("blog_id"), 10, 32)
+	event := c.DefaultQuery("event", engagement.EventView)
+	depth, _ := strconv.ParseFloat(c.Query("depth"), 64)
+	id := uint(blogID)
+	pixelEvent := event == engagement.EventView || event == engagement.EventScroll
+	if err == nil && blogID > 0 && pixelEvent && !isAutomated(c, &id) {
+		h.record(c, id, c.Query("session_id"), []models.BeaconEvent{{
+			Type:    event,
+			Depth:   depth,
+			Network: c.Query("network"),
+		}})
+	}
+
+	c.Header("Cache-Control", "no-store, no-cache, must-revalidate, private")
+	c.Data(http.StatusOK, "image/gif", transparentGIF)
+}
+
+// Beacon records engagement events sent with navigator.sendBeacon. Beacons are
+// posted as text/plain, so the body is decoded as JSON whatever its content type.
+func (h *EngagementHandler) Beacon(c *gin.Context) {
+	var req models.BeaconRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	if !isAutomated(c, &req.BlogID) {
+		h.record(c, req.BlogID, req.SessionID, req.Events)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// record counts each event once per visitor and session within the dedupe
+// window and adds the increments to the buffer. Likes count once per visitor
+// ever, recorded in blog_likes.
+// Unknown or unpublished blogs are dropped when the buffer is flushed.
+func (h *EngagementHandler) record(c *gin.Context, blogID uint, sessionID string, events []models.BeaconEvent) {
+	now := time.Now()
+
+	visitor := visitorIDFromCookie(c)
+	if visitor == "" {
+		// Without the cookie, the client address and browser stand in for the visitor
+		sum := sha256.Sum256([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+		visitor = "fp-" + hex.EncodeToString(sum[:12])
+	}
+	if !tracking.IsValidSessionID(sessionID) {
+		sessionID = ""
+	}
+	scope := fmt.Sprintf("%d|%s|%s", blogID, visitor, sessionID)
+
+	var delta engagement.Counts
+	for _, event := range events {
+		switch event.Type {
+		case engagement.EventView:
+			if h.deduper.First("view|"+scope, now) {
+				delta.Views++
+			}
+		case engagement.EventScroll:
+			for i, milestone := range engagement.ScrollBuckets {
+				if event.Depth >= float64(milestone) && h.deduper.First(fmt.Sprintf("scroll|%s|%d", scope, milestone), now) {
+					delta.Scroll[i]++
+				}
+			}
+		case engagement.EventLike:
+			if firstLike(blogID, visitor) {
+				delta.Likes++
+			}
+		case engagement.EventShare:
+			network := strings.ToLower(strings.TrimSpace(event.Network))
+			if h.deduper.First("share|"+scope+"|"+truncateString(network, 50), now) {
+				delta.Shares++
+			}
+		}
+	}
+
+	if !delta.IsZero() {
+		h.buffer.Add(blogID, now, delta)
+	}
+}
+
+// firstLike stores a visitor's like of a blog and reports whether it is their
+// first. Likes of unknown blogs are stored too; their counts are dropped at flush.
+func firstLike(blogID uint, visitor string) bool {
+	db := database.GetDB()
+	if db == nil {
+		return false
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.BlogLike{BlogID: blogID, VisitorID: visitor})
+	if result.Error != nil {
+		logger.Error("Failed to record like", result.Error, map[string]interface{}{"blog_id": blogID})
+		return false
+	}
+	return result.RowsAffected == 1
+}
@@ -0,0 +1,393 @@
+package handlers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/leadmatch"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/privacy"
+	"blog-service/pkg/workflow"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxCaptureAttempts bounds retries of a capture that conflicted with a
+// concurrent capture of the same person or visitor
+const maxCaptureAttempts = 3
+
+// LeadHandler handles lead capture and management
+type LeadHandler struct{}
+
+// NewLeadHandler creates a new lead handler
+func NewLeadHandler() *LeadHandler {
+	return &LeadHandler{}
+}
+
+// CaptureLead records a lead captured on a published blog post. It is public:
+// visitors submit it from forms embedded in the post.
+func (h *LeadHandler) CaptureLead(c *gin.Context) {
+	blogID, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid blog ID", "INVALID_ID", "Blog ID must be a positive integer")
+		return
+	}
+
+	var req models.BlogLeadCaptureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if req.BlogID != 0 && req.BlogID != blogID {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "BLOG_ID_MISMATCH", "blog_id does not match the blog in the URL")
+		return
+	}
+
+	decision := consentPolicy().Evaluate(req.ConsentGiven, req.ConsentType)
+	if decision == privacy.DecisionReject {
+		respondError(c, http.StatusBadRequest, "Consent required", "CONSENT_REQUIRED",
+			"consent_given must be true for consent_type "+strings.ToLower(strings.TrimSpace(req.ConsentType)))
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	// Leads can only come from posts visitors can see
+	var blog models.Blog
+	if err := db.Where("status = ?", workflow.StatusPublished).First(&blog, blogID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "Blog not found", "BLOG_NOT_FOUND", "Blog post does not exist")
+			return
+		}
+		logger.Error("Failed to load blog", err, map[string]interface{}{"blog_id": blogID})
+		respondError(c, http.StatusInternalServerError, "Failed to capture lead", "DATABASE_ERROR", "Unable to load blog post")
+		return
+	}
+
+	// A capture is retried when it lost a deadlock to a concurrent capture of
+	// the same person, whose lead it then finds, or when a concurrent capture
+	// claimed part of the visitor's history
+	visitorID := visitorIDFromCookie(c)
+	var lead, existing *models.BlogLead
+	var confirm bool
+	var err error
+	for attempt := 1; ; attempt++ {
+		// Pages the visitor read before the form count towards the new lead.
+		// Without a required consent the visitor's browsing is not linked to them.
+		history := &visitorHistory{}
+		if decision == privacy.DecisionAccept {
+			if history, err = loadVisitorHistory(db, visitorID); err != nil {
+				logger.Error("Failed to load visitor history", err, map[string]interface{}{"blog_id": blog.ID})
+				respondError(c, http.StatusInternalServerError, "Failed to capture lead", "DATABASE_ERROR", "Unable to load visitor history")
+				return
+			}
+		}
+
+		lead = newBlogLead(&req, &blog, c.ClientIP(), c.Request.UserAgent())
+		if decision == privacy.DecisionQuarantine {
+			lead.QuarantinedAt = &lead.CapturedAt
+		}
+		applyPreCaptureHistory(lead, history)
+		scored := scoreLead(lead, &req)
+		touchpoint := captureTouchpoint(&req, &blog, lead)
+
+		existing, confirm, err = storeCapture(&blog, lead, &scored, &touchpoint, history)
+		if attempt == maxCaptureAttempts || !(database.IsDeadlock(err) || errors.Is(err, errVisitorHistoryClaimed)) {
+			break
+		}
+	}
+	if err != nil {
+		logger.Error("Failed to capture lead", err, map[string]interface{}{"blog_id": blog.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to capture lead", "DATABASE_ERROR", "Unable to store lead")
+		return
+	}
+
+	if existing != nil {
+		logger.LogBusinessEvent("lead_recaptured", "lead", existing.ID, map[string]interface{}{
+			"blog_id":     blog.ID,
+			"source_type": lead.SourceType,
+			"lead_score":  existing.LeadScore,
+			"assigned_to": existing.AssignedTo,
+		})
+		if confirm {
+			go sendConsentConfirmation(*existing)
+		}
+		// Anyone can submit an address, so a repeat capture is only acknowledged
+		respondSuccess(c, http.StatusOK, "Lead already captured, touchpoint recorded", models.LeadRecaptureResponse{Received: true})
+		return
+	}
+
+	logger.LogBusinessEvent("lead_captured", "lead", lead.ID, map[string]interface{}{
+		"blog_id":            blog.ID,
+		"source_type":        lead.SourceType,
+		"lead_score":         lead.LeadScore,
+		"auto_qualification": lead.AutoQualification,
+		"assigned_to":        lead.AssignedTo,
+		"quarantined":        lead.QuarantinedAt != nil,
+	})
+
+	respondSuccess(c, http.StatusCreated, "Lead captured successfully", captureResponse(lead))
+}
+
+// storeCapture stores a capture in one transaction and returns the existing
+// lead it was added to, or nil when it created the lead. A returning reader
+// adds a touchpoint to their existing lead instead of becoming a second lead.
+// confirm reports that a consent confirmation link should be sent to the
+// existing lead.
+func storeCapture(blog *models.Blog, lead *models.BlogLead, scored *models.LeadActivity, touchpoint *models.LeadTouchpoint, history *visitorHistory) (existing *models.BlogLead, confirm bool, err error) {
+	err = database.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if existing, err = findDuplicateLead(tx, lead); err != nil {
+			return err
+		}
+		if existing != nil {
+			// Someone who opted out or is quarantined is only tracked again
+			// after confirming consent from their inbox
+			held := existing.OptedOut || existing.QuarantinedAt != nil
+			if held {
+				history = &visitorHistory{}
+			}
+			if err := stitchVisitorHistory(tx, existing, history); err != nil {
+				return err
+			}
+			if err := mergeCapture(tx, existing, lead, scored, touchpoint); err != nil {
+				return err
+			}
+			if held && lead.ConsentGiven {
+				if confirm, err = requestConsentConfirmation(tx, existing, time.Now()); err != nil {
+					return err
+				}
+			}
+			_, err = assignLead(tx, existing, assignmentTriggerCapture)
+			return err
+		}
+
+		if blog.CategoryID != nil {
+			var category models.Category
+			err := tx.Select("name").First(&category, *blog.CategoryID).Error
+			switch {
+			case err == nil:
+				lead.BlogCategory = truncateString(category.Name, 100)
+			case !errors.Is(err, gorm.ErrRecordNotFound):
+				return err
+			}
+		}
+
+		if err := tx.Create(lead).Error; err != nil {
+			return err
+		}
+
+		scored.LeadID = lead.ID
+		if err := tx.Create(scored).Error; err != nil {
+			return err
+		}
+
+		if err := stitchVisitorHistory(tx, lead, history); err != nil {
+			return err
+		}
+		if err := recordTouchpoint(tx, lead, touchpoint); err != nil {
+			return err
+		}
+
+		// The counter is derived data, so it does not bump updated_at
+		if err := tx.Model(&models.Blog{}).Where("id = ?", blog.ID).
+			UpdateColumn("lead_generation_count", gorm.Expr("lead_generation_count + 1")).Error; err != nil {
+			return err
+		}
+
+		_, err = assignLead(tx, lead, assignmentTriggerCapture)
+		return err
+	})
+	return existing, confirm, err
+}
+
+// captureResponse is the capture response for a newly created lead. Repeat
+// captures never get one, since their submitter may not own the address. The
+// tracking token is only issued to leads that may be tracked: not quarantined.
+func captureResponse(lead *models.BlogLead) models.BlogLeadResponse {
+	response := leadResponse(lead)
+	if !lead.OptedOut && lead.QuarantinedAt == nil {
+		response.LeadToken = issueLeadToken(lead.ID)
+	}
+	response.OptOutURL = leadOptOutURL(lead.ID)
+	return response
+}
+
+// newBlogLead builds a lead from a capture request, denormalizing the blog's
+// title and URL and flattening device, location and engagement data into columns
+func newBlogLead(req *models.BlogLeadCaptureRequest, blog *models.Blog, clientIP, userAgent string) *models.BlogLead {
+	now := time.Now()
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = strings.TrimSpace(strings.TrimSpace(req.FirstName) + " " + strings.TrimSpace(req.LastName))
+	}
+
+	lead := &models.BlogLead{
+		Email:           strings.ToLower(strings.TrimSpace(req.Email)),
+		NormalizedEmail: leadmatch.NormalizeEmail(req.Email),
+		Name:            truncateString(name, 255),
+		FirstName:       strings.TrimSpace(req.FirstName),
+		LastName:        strings.TrimSpace(req.LastName),
+		Company:         strings.TrimSpace(req.Company),
+		JobTitle:        strings.TrimSpace(req.JobTitle),
+		Phone:           strings.TrimSpace(req.Phone),
+		Website:         strings.TrimSpace(req.Website),
+		LinkedInProfile: strings.TrimSpace(req.LinkedInProfile),
+
+		BlogID:    blog.ID,
+		BlogTitle: blog.Title,
+		BlogURL:   truncateString(blogPostURL(blog.Slug), 500),
+
+		SourceType:    req.SourceType,
+		SourceDetails: models.JSONMap(req.SourceDetails),
+		CaptureMethod: req.CaptureMethod,
+
+		Status:     workflow.LeadStatusNew,
+		CapturedAt: now,
+
+		UTMSource:   req.UTMSource,
+		UTMMedium:   req.UTMMedium,
+		UTMCampaign: req.UTMCampaign,
+		UTMTerm:     req.UTMTerm,
+		UTMContent:  req.UTMContent,
+
+		TrafficSource:  req.TrafficSource,
+		ReferrerURL:    req.ReferrerURL,
+		ReferrerDomain: referrerDomain(req.ReferrerURL),
+		LandingPage:    req.LandingPage,
+
+		DeviceType:      truncateString(mapString(req.DeviceInfo, "device_type", "type"), 50),
+		Browser:         truncateString(mapString(req.DeviceInfo, "browser"), 100),
+		OperatingSystem: truncateString(mapString(req.DeviceInfo, "operating_system", "os"), 100),
+		IPAddress:       truncateString(clientIP, 45),
+		Country:         truncateString(mapString(req.LocationInfo, "country"), 100),
+		Region:          truncateString(mapString(req.LocationInfo, "region", "state"), 100),
+		City:            truncateString(mapString(req.LocationInfo, "city"), 100),
+		Timezone:        truncateString(mapString(req.LocationInfo, "timezone"), 100),
+
+		TotalEngagements:        int(mapFloat(req.EngagementData, "total_engagements", "engagements")),
+		PageViewsBeforeCapture:  int(mapFloat(req.EngagementData, "page_views_before_capture", "page_views")),
+		TimeOnSiteBeforeCapture: int(mapFloat(req.EngagementData, "time_on_site_before_capture", "time_on_site")),
+		ScrollDepthAtCapture:    mapFloat(req.EngagementData, "scroll_depth_at_capture", "scroll_depth"),
+		PreviousVisits:          int(mapFloat(req.EngagementData, "previous_visits")),
+
+		CustomFields: models.JSONMap(req.CustomFields),
+
+		ConsentGiven: req.ConsentGiven,
+		ConsentType:  strings.ToLower(strings.TrimSpace(req.ConsentType)),
+	}
+
+	if lead.Browser == "" && lead.OperatingSystem == "" {
+		lead.SourceDetails = withUserAgent(lead.SourceDetails, userAgent)
+	}
+
+	if len(req.Tags) > 0 {
+		lead.Tags = make(models.JSONArray, 0, len(req.Tags))
+		for _, tag := range req.Tags {
+			lead.Tags = append(lead.Tags, tag)
+		}
+	}
+
+	if req.ConsentGiven {
+		lead.ConsentTimestamp = &now
+	}
+
+	return lead
+}
+
+// leadResponse converts a lead into its API response
+func leadResponse(lead *models.BlogLead) models.BlogLeadResponse {
+	updatedAt := lead.UpdatedAt
+	return models.BlogLeadResponse{
+		ID:                  lead.ID,
+		Email:               lead.Email,
+		Name:                lead.Name,
+		Company:             lead.Company,
+		Phone:               lead.Phone,
+		BlogID:              lead.BlogID,
+		BlogTitle:           lead.BlogTitle,
+		SourceType:          lead.SourceType,
+		LeadScore:           lead.LeadScore,
+		Status:              lead.Status,
+		AutoQualification:   lead.AutoQualification,
+		ManualQualification: lead.ManualQualification,
+		QualificationNotes:  lead.QualificationNotes,
+		CapturedAt:          lead.CapturedAt,
+		QualifiedAt:         lead.QualifiedAt,
+		UpdatedAt:           &updatedAt,
+		UTMSource:           lead.UTMSource,
+		UTMMedium:           lead.UTMMedium,
+		UTMCampaign:         lead.UTMCampaign,
+		TrafficSource:       lead.TrafficSource,
+		MergedIntoID:        lead.MergedIntoID,
+		OptedOut:            lead.OptedOut,
+		QuarantinedAt:       lead.QuarantinedAt,
+		ErasedAt:            lead.ErasedAt,
+	}
+}
+
+// withUserAgent keeps the raw user agent in the source details when the
+// client did not send parsed device information
+func withUserAgent(details models.JSONMap, userAgent string) models.JSONMap {
+	if userAgent == "" {
+		return details
+	}
+	if details == nil {
+		details = models.JSONMap{}
+	}
+	if _, exists := details["user_agent"]; !exists {
+		details["user_agent"] = truncateString(userAgent, 500)
+	}
+	return details
+}
+
+// referrerDomain returns the host of a referrer URL without a leading www.
+func referrerDomain(referrer string) string {
+	parsed, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil {
+		return ""
+	}
+	return truncateString(strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www."), 255)
+}
+
+// mapString returns the first non-empty string value among keys
+func mapString(values map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := values[key].(string); ok {
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// mapFloat returns the first non-negative numeric value among keys. JSON
+// numbers decode as float64.
+func mapFloat(values map[string]interface{}, keys ...string) float64 {
+	for _, key := range keys {
+		if value, ok := values[key].(float64); ok && value >= 0 {
+			return value
+		}
+	}
+	return 0
+}
+
+// truncateString shortens s to at most max runes so it fits its column
+func truncateString(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,809 @@
+package handlers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/analytics"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// leadPredictionDays is the number of most recent days of captures the predictions are fitted to
+	leadPredictionDays = 90
+
+	// leadBacktestDays is the number of most recent days held out to backtest the predictions
+	leadBacktestDays = 14
+
+	// roughForecastError is the backtest MAPE, in percent, above which predictions come with a warning
+	roughForecastError = 50.0
+
+	// highDropOff is the drop-off rate, in percent, that makes a funnel stage an improvement area
+	highDropOff = 50.0
+)
+
+// Lead funnel stages, in order. A lead reaching a stage counts as having passed the earlier ones.
+const (
+	leadStageCaptured = iota
+	leadStageQualified
+	leadStageNurtured
+	leadStageConverted
+)
+
+// leadForecastHorizons are the days ahead lead predictions are totalled over
+var leadForecastHorizons = []int{30, 60, 90}
+
+// leadForecaster forecasts daily lead captures
+var leadForecaster = analytics.NewForecaster()
+
+// leadFunnelTransitions names the move out of each stage, matching FunnelConversionRates
+var leadFunnelTransitions = []string{"lead_to_qualified", "qualified_to_nurture", "nurture_to_conversion"}
+
+// leadFunnelAdvice is the improvement area reported for a funnel transition with a high drop-off
+var leadFunnelAdvice = map[string]string{
+	"visitor_to_lead":       "Add or strengthen calls to action on posts with traffic but few leads",
+	"lead_to_qualified":     "Review capture forms and scoring so fewer unqualified leads reach sales",
+	"qualified_to_nurture":  "Enroll qualified leads in nurture sequences instead of leaving them idle",
+	"nurture_to_conversion": "Review nurture content and the sales handoff for leads that stall before converting",
+}
+
+// leadSourceDimensions are the columns lead sources can be broken down by
+var leadSourceDimensions = map[string]string{
+	"source_type":  "blog_leads.source_type",
+	"utm":          "CONCAT(COALESCE(NULLIF(blog_leads.utm_source, ''), '(none)'), ' / ', COALESCE(NULLIF(blog_leads.utm_medium, ''), '(none)'))",
+	"utm_campaign": "COALESCE(NULLIF(blog_leads.utm_campaign, ''), '(none)')",
+}
+
+// leadQualityFactors are the lead attributes correlated with conversion
+var leadQualityFactors = []struct{ column, label string }{
+	{"lead_score", "lead score"},
+	{"scroll_depth_at_capture", "scroll depth at capture"},
+	{"time_on_site_before_capture", "time on site before capture"},
+	{"page_views_before_capture", "page views before capture"},
+	{"previous_visits", "previous visits"},
+}
+
+// leadStageColumn ranks how far each lead got. Qualification and nurturing
+// count when the lead is there now, or reached it according to its status history.
+var leadStageColumn = fmt.Sprintf(`CASE WHEN blog_leads.status = '%[1]s' THEN 3
+	WHEN blog_leads.status = '%[2]s' OR history.reached_nurturing THEN 2
+	WHEN blog_leads.status = '%[3]s' OR blog_leads.qualified_at IS NOT NULL OR history.reached_qualified THEN 1
+	ELSE 0 END`, workflow.LeadStatusConverted, workflow.LeadStatusNurturing, workflow.LeadStatusQualified)
+
+// leadHistoryJoin flags the statuses each lead captured in a window has moved to
+var leadHistoryJoin = fmt.Sprintf(`LEFT JOIN (SELECT lead_id,
+		MAX(JSON_UNQUOTE(JSON_EXTRACT(metadata, '$.to_status')) IN ('%[1]s', '%[2]s', '%[3]s')) AS reached_qualified,
+		MAX(JSON_UNQUOTE(JSON_EXTRACT(metadata, '$.to_status')) IN ('%[2]s', '%[3]s')) AS reached_nurturing
+	FROM lead_activities
+	WHERE activity_type = '%[4]s' AND lead_id IN (SELECT id FROM blog_leads WHERE captured_at BETWEEN ? AND ?)
+	GROUP BY lead_id) history ON history.lead_id = blog_leads.id`,
+	workflow.LeadStatusQualified, workflow.LeadStatusNurturing, workflow.LeadStatusConverted, models.LeadActivityStatusChanged)
+
+// GetLeadAnalytics returns analytics for the leads captured in a window: a
+// summary, the conversion funnel with drop-off reasons, source, blog and
+// score range breakdowns, quality trends and lead predictions.
+// Parameters: start_date and end_date, or period (default 30d); granularity;
+// blog_ids; lead_sources (source types); status; source_dimension
+// (source_type, utm or utm_campaign); limit (blogs in the breakdown).
+func (h *LeadHandler) GetLeadAnalytics(c *gin.Context) {
+	req, ok := leadAnalyticsRequestFromQuery(c)
+	if !ok {
+		return
+	}
+	dimension := c.DefaultQuery("source_dimension", "source_type")
+	if _, valid := leadSourceDimensions[dimension]; !valid {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_DIMENSION", "source_dimension must be source_type, utm or utm_campaign")
+		return
+	}
+	limit := min(max(parseIntQuery(c, "limit", defaultTopPerformers), 1), maxTopPerformers)
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	response, err := buildLeadAnalytics(db, req, dimension, limit)
+	if err != nil {
+		logger.Error("Failed to build lead analytics", err, map[string]interface{}{
+			"start_date": req.StartDate,
+			"end_date":   req.EndDate,
+		})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve lead analytics", "DATABASE_ERROR", "Unable to aggregate leads")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Lead analytics retrieved successfully", response)
+}
+
+// leadAnalyticsRequestFromQuery parses and validates the window and lead
+// filters, writing the error response when they are invalid
+func leadAnalyticsRequestFromQuery(c *gin.Context) (models.BlogLeadAnalyticsRequest, bool) {
+	var req models.BlogLeadAnalyticsRequest
+	var ok bool
+	if req.StartDate, req.EndDate, ok = analyticsWindowFromQuery(c, "30d"); !ok {
+		return req, false
+	}
+	if req.Granularity, ok = analyticsGranularityFromQuery(c, req.StartDate, req.EndDate); !ok {
+		return req, false
+	}
+	if req.BlogIDs, ok = parseIDList(c.Query("blog_ids")); !ok {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_BLOG", "blog_ids must be a comma-separated list of blog IDs")
+		return req, false
+	}
+	for _, source := range strings.Split(c.Query("lead_sources"), ",") {
+		if source = strings.TrimSpace(source); source != "" {
+			req.LeadSources = append(req.LeadSources, source)
+		}
+	}
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		if status = strings.TrimSpace(status); status == "" {
+			continue
+		}
+		if !workflow.IsValidLeadStatus(status) {
+			respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_STATUS", "Unknown lead status: "+status)
+			return req, false
+		}
+		req.Status = append(req.Status, status)
+	}
+	return req, true
+}
+
+// leadCohort selects the leads captured in start..end matching the request's
+// filters, without merged duplicates and leads quarantined for missing consent
+func leadCohort(db *gorm.DB, req models.BlogLeadAnalyticsRequest, start, end time.Time) *gorm.DB {
+	query := db.Table("blog_leads").
+		Where("blog_leads.captured_at BETWEEN ? AND ? AND blog_leads.merged_into_id IS NULL AND blog_leads.quarantined_at IS NULL", start, end)
+	if len(req.BlogIDs) > 0 {
+		query = query.Where("blog_leads.blog_id IN ?", req.BlogIDs)
+	}
+	if len(req.LeadSources) > 0 {
+		query = query.Where("blog_leads.source_type IN ?", req.LeadSources)
+	}
+	if len(req.Status) > 0 {
+		query = query.Where("blog_leads.status IN ?", req.Status)
+	}
+	return query
+}
+
+// leadCohortWithHistory is the request's cohort joined to its status history, for leadStageColumn
+func leadCohortWithHistory(db *gorm.DB, req models.BlogLeadAnalyticsRequest) *gorm.DB {
+	return leadCohort(db, req, req.StartDate, req.EndDate).Joins(leadHistoryJoin, req.StartDate, req.EndDate)
+}
+
+// leadCounts holds counts and values shared by the summary and breakdowns
+type leadCounts struct {
+	Leads     int
+	Qualified int
+	Converted int
+	Revenue   float64
+	LeadScore float64
+}
+
+// leadCountColumns selects leadCounts; the query must join the status history
+var leadCountColumns = fmt.Sprintf(`COUNT(*) AS leads, SUM((%s) >= 1) AS qualified,
+	SUM(blog_leads.status = '%s') AS converted,
+	SUM(CASE WHEN blog_leads.status = '%s' THEN blog_leads.conversion_value ELSE 0 END) AS revenue,
+	AVG(blog_leads.lead_score) AS lead_score`, leadStageColumn, workflow.LeadStatusConverted, workflow.LeadStatusConverted)
+
+// conversionRate is converted leads per 100 leads
+func (l leadCounts) conversionRate() float64 {
+	return performanceCalculator.CalculateConversionRate(l.Converted, l.Leads)
+}
+
+// valuePerLead is revenue divided over all leads
+func (l leadCounts) valuePerLead() float64 {
+	if l.Leads == 0 {
+		return 0
+	}
+	return l.Revenue / float64(l.Leads)
+}
+
+// buildLeadAnalytics runs the lead aggregates for a validated request
+func buildLeadAnalytics(db *gorm.DB, req models.BlogLeadAnalyticsRequest, dimension string, limit int) (models.BlogLeadAnalyticsResponse, error) {
+	response := models.BlogLeadAnalyticsResponse{
+		Period: analyticsPeriod(models.BlogAnalyticsRequest{StartDate: req.StartDate, EndDate: req.EndDate}),
+	}
+
+	summary, err := leadSummary(db, req)
+	if err != nil {
+		return response, err
+	}
+	funnel, err := leadFunnel(db, req)
+	if err != nil {
+		return response, err
+	}
+	sources, err := leadSources(db, req, dimension)
+	if err != nil {
+		return response, err
+	}
+	blogs, err := leadBlogs(db, req, limit)
+	if err != nil {
+		return response, err
+	}
+	quality, err := leadQualityMetrics(db, req, summary)
+	if err != nil {
+		return response, err
+	}
+	trend, err := leadTrend(db, req)
+	if err != nil {
+		return response, err
+	}
+	predictions, err := leadPredictions(db, req)
+	if err != nil {
+		return response, err
+	}
+
+	summary.QualifiedLeads = funnel.LeadsQualified
+	quality.QualityTrends = qualityTrends(trend, quality.QualityTrends.SeasonalPatterns)
+
+	response.Summary = summary
+	response.ConversionFunnel = funnel
+	response.SourceBreakdown = sources
+	response.BlogBreakdown = blogs
+	response.QualityMetrics = quality
+	response.TrendData = trend
+	response.Predictions = predictions
+	return response, nil
+}
+
+// leadSummary totals the cohort and compares its size with the previous window
+func leadSummary(db *gorm.DB, req models.BlogLeadAnalyticsRequest) (models.LeadAnalyticsSummary, error) {
+	var row struct {
+		Leads         int
+		NewLeads      int
+		Converted     int
+		LeadScore     float64
+		TimeToConvert float64
+		Revenue       float64
+	}
+	if err := leadCohort(db, req, req.StartDate, req.EndDate).
+		Select(`COUNT(*) AS leads, SUM(status = ?) AS new_leads, SUM(status = ?) AS converted, AVG(lead_score) AS lead_score,
+			AVG(CASE WHEN status = ? AND converted_at IS NOT NULL THEN TIMESTAMPDIFF(SECOND, captured_at, converted_at) END) / 3600 AS time_to_convert,
+			SUM(CASE WHEN status = ? THEN conversion_value ELSE 0 END) AS revenue`,
+			workflow.LeadStatusNew, workflow.LeadStatusConverted, workflow.LeadStatusConverted, workflow.LeadStatusConverted).
+		Scan(&row).Error; err != nil {
+		return models.LeadAnalyticsSummary{}, err
+	}
+
+	previousStart, previousEnd := analytics.PreviousPeriod(req.StartDate, req.EndDate)
+	var previous int64
+	if err := leadCohort(db, req, previousStart, previousEnd).Count(&previous).Error; err != nil {
+		return models.LeadAnalyticsSummary{}, err
+	}
+
+	counts := leadCounts{Leads: row.Leads, Converted: row.Converted, Revenue: row.Revenue}
+	days := req.EndDate.Sub(req.StartDate).Hours() / 24
+	return models.LeadAnalyticsSummary{
+		TotalLeads:       row.Leads,
+		NewLeads:         row.NewLeads,
+		ConvertedLeads:   row.Converted,
+		ConversionRate:   round2(counts.conversionRate()),
+		AvgLeadScore:     round2(row.LeadScore),
+		AvgTimeToConvert: round2(row.TimeToConvert),
+		TotalRevenue:     round2(row.Revenue),
+		AvgLeadValue:     round2(counts.valuePerLead()),
+		LeadVelocity:     round2(float64(row.Leads) / math.Max(days, 1)),
+		GrowthRate:       round2(growthRate(float64(row.Leads), float64(previous))),
+	}, nil
+}
+
+// leadFunnel counts the cohort through the funnel stages and explains where
+// leads dropped off from each lead's current status and last status change
+func leadFunnel(db *gorm.DB, req models.BlogLeadAnalyticsRequest) (models.LeadConversionFunnel, error) {
+	var rows []struct {
+		Stage      int
+		Status     string
+		FromStatus string
+		Leads      int
+	}
+	if err := leadCohortWithHistory(db, req).
+		Select(leadStageColumn+` AS stage, blog_leads.status,
+			COALESCE(JSON_UNQUOTE(JSON_EXTRACT(last.metadata, '$.from_status')), '') AS from_status, COUNT(*) AS leads`).
+		Joins(`LEFT JOIN lead_activities last ON last.id = (SELECT MAX(changes.id) FROM lead_activities changes
+			WHERE changes.lead_id = blog_leads.id AND changes.activity_type = ?)`, models.LeadActivityStatusChanged).
+		Group("stage, blog_leads.status, from_status").
+		Scan(&rows).Error; err != nil {
+		return models.LeadConversionFunnel{}, err
+	}
+
+	// The daily rollups count a visitor once per day, so distinct visitors of
+	// the window are counted from the events
+	visitors := struct{ Visitors int }{}
+	events := db.Table("visitor_events").
+		Select("COUNT(DISTINCT NULLIF(visitor_id, '')) AS visitors").
+		Where("blog_id IS NOT NULL AND created_at BETWEEN ? AND ?", req.StartDate, req.EndDate)
+	if len(req.BlogIDs) > 0 {
+		events = events.Where("blog_id IN ?", req.BlogIDs)
+	}
+	if err := events.Scan(&visitors).Error; err != nil {
+		return models.LeadConversionFunnel{}, err
+	}
+
+	// reached[s] counts the leads that got to stage s or further
+	var reached [leadStageConverted + 1]int
+	dropped := map[int]map[string]int{}
+	for _, row := range rows {
+		stage := min(max(row.Stage, leadStageCaptured), leadStageConverted)
+		for s := leadStageCaptured; s <= stage; s++ {
+			reached[s] += row.Leads
+		}
+		if stage == leadStageConverted {
+			continue
+		}
+		if dropped[stage] == nil {
+			dropped[stage] = map[string]int{}
+		}
+		dropped[stage][dropOffReason(row.Status, row.FromStatus)] += row.Leads
+	}
+
+	funnel := models.LeadConversionFunnel{
+		BlogVisitors:   visitors.Visitors,
+		LeadsCaptured:  reached[leadStageCaptured],
+		LeadsQualified: reached[leadStageQualified],
+		LeadsNurtured:  reached[leadStageNurtured],
+		LeadsConverted: reached[leadStageConverted],
+	}
+	funnel.ConversionRates = models.FunnelConversionRates{
+		VisitorToLead:       round2(percentage(funnel.LeadsCaptured, funnel.BlogVisitors)),
+		LeadToQualified:     round2(percentage(funnel.LeadsQualified, funnel.LeadsCaptured)),
+		QualifiedToNurture:  round2(percentage(funnel.LeadsNurtured, funnel.LeadsQualified)),
+		NurtureToConversion: round2(percentage(funnel.LeadsConverted, funnel.LeadsNurtured)),
+		OverallConversion:   round2(percentage(funnel.LeadsConverted, funnel.BlogVisitors)),
+	}
+	funnel.DropOffAnalysis = dropOffAnalysis(funnel, reached, dropped)
+	return funnel, nil
+}
+
+// dropOffReason describes why a lead that stopped short of conversion is where it is
+func dropOffReason(status, from string) string {
+	switch {
+	case status == workflow.LeadStatusLost || status == workflow.LeadStatusUnqualified:
+		if from == "" {
+			return "Marked " + status
+		}
+		return fmt.Sprintf("Marked %s from %s", status, from)
+	case from == workflow.LeadStatusConverted:
+		return "Reopened after conversion"
+	case status == workflow.LeadStatusNew:
+		return "Not contacted yet"
+	default:
+		return "Open as " + status
+	}
+}
+
+// dropOffAnalysis finds the funnel transition losing the largest share of
+// leads and the reasons leads stopped at each stage
+func dropOffAnalysis(funnel models.LeadConversionFunnel, reached [leadStageConverted + 1]int, dropped map[int]map[string]int) models.FunnelDropOffAnalysis {
+	analysis := models.FunnelDropOffAnalysis{DropOffReasons: []models.DropOffReason{}, ImprovementAreas: []string{}}
+
+	type transition struct {
+		name    string
+		dropOff float64
+	}
+	transitions := []transition{}
+	if funnel.BlogVisitors > 0 {
+		transitions = append(transitions, transition{"visitor_to_lead", 100 - math.Min(percentage(funnel.LeadsCaptured, funnel.BlogVisitors), 100)})
+	}
+	for stage, name := range leadFunnelTransitions {
+		if reached[stage] > 0 {
+			transitions = append(transitions, transition{name, 100 - percentage(reached[stage+1], reached[stage])})
+		}
+	}
+	sort.SliceStable(transitions, func(i, j int) bool { return transitions[i].dropOff > transitions[j].dropOff })
+	if len(transitions) > 0 && transitions[0].dropOff > 0 {
+		analysis.HighestDropOff = transitions[0].name
+	}
+	for _, t := range transitions {
+		if t.dropOff >= highDropOff {
+			analysis.ImprovementAreas = append(analysis.ImprovementAreas, leadFunnelAdvice[t.name])
+		}
+	}
+
+	for stage, reasons := range dropped {
+		for reason, leads := range reasons {
+			share := percentage(leads, reached[stage])
+			analysis.DropOffReasons = append(analysis.DropOffReasons, models.DropOffReason{
+				Stage:      leadFunnelTransitions[stage],
+				Reason:     reason,
+				Percentage: round2(share),
+				Impact:     analytics.ImpactLevel(share),
+			})
+		}
+	}
+	sort.Slice(analysis.DropOffReasons, func(i, j int) bool {
+		a, b := analysis.DropOffReasons[i], analysis.DropOffReasons[j]
+		if a.Percentage != b.Percentage {
+			return a.Percentage > b.Percentage
+		}
+		if a.Stage != b.Stage {
+			return a.Stage < b.Stage
+		}
+		return a.Reason < b.Reason
+	})
+
+	if uncontacted := percentage(dropped[leadStageCaptured]["Not contacted yet"], reached[leadStageCaptured]); uncontacted >= 25 {
+		analysis.ImprovementAreas = append(analysis.ImprovementAreas,
+			fmt.Sprintf("Follow up on new leads sooner: %.0f%% have not been contacted", uncontacted))
+	}
+	return analysis
+}
+
+// leadSources breaks the cohort down by source type, UTM source and medium, or
+// UTM campaign. Source costs are not tracked, so cost per lead and ROI are 0.
+func leadSources(db *gorm.DB, req models.BlogLeadAnalyticsRequest, dimension string) ([]models.LeadSourceAnalytics, error) {
+	var rows []struct {
+		Source string
+		Counts leadCounts `gorm:"embedded"`
+	}
+	if err := leadCohortWithHistory(db, req).
+		Select(leadSourceDimensions[dimension] + " AS source, " + leadCountColumns).
+		Group("source").
+		Order("leads DESC, source").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	sources := make([]models.LeadSourceAnalytics, 0, len(rows))
+	for _, row := range rows {
+		sources = append(sources, models.LeadSourceAnalytics{
+			Source:         row.Source,
+			LeadCount:      row.Counts.Leads,
+			QualifiedCount: row.Counts.Qualified,
+			ConvertedCount: row.Counts.Converted,
+			Revenue:        round2(row.Counts.Revenue),
+			ConversionRate: round2(row.Counts.conversionRate()),
+			AvgLeadScore:   round2(row.Counts.LeadScore),
+			AvgLeadValue:   round2(row.Counts.valuePerLead()),
+			// Average lead score and qualification rate weigh equally
+			QualityScore: int(math.Round((row.Counts.LeadScore + percentage(row.Counts.Qualified, row.Counts.Leads)) / 2)),
+		})
+	}
+	return sources, nil
+}
+
+// leadBlogs ranks the blogs of the cohort's leads by revenue, conversions and leads
+func leadBlogs(db *gorm.DB, req models.BlogLeadAnalyticsRequest, limit int) ([]models.BlogLeadAnalytics, error) {
+	var rows []struct {
+		BlogID uint
+		Title  string
+		Slug   string
+		Views  int
+		Counts leadCounts `gorm:"embedded"`
+	}
+	if err := leadCohortWithHistory(db, req).
+		Select("blog_leads.blog_id, COALESCE(blogs.title, '') AS title, COALESCE(blogs.slug, '') AS slug, COALESCE(MAX(views.views), 0) AS views, "+leadCountColumns).
+		Joins("LEFT JOIN blogs ON blogs.id = blog_leads.blog_id").
+		Joins(`LEFT JOIN (SELECT blog_id, SUM(views) AS views FROM blog_daily_metrics
+			WHERE date BETWEEN ? AND ? GROUP BY blog_id) views ON views.blog_id = blog_leads.blog_id`,
+			analyticsDay(req.StartDate), analyticsDay(req.EndDate)).
+		Group("blog_leads.blog_id, blogs.title, blogs.slug").
+		Order("revenue DESC, converted DESC, leads DESC, blog_leads.blog_id").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	blogs := make([]models.BlogLeadAnalytics, 0, len(rows))
+	for i, row := range rows {
+		var efficiency float64
+		if row.Views > 0 {
+			efficiency = float64(row.Counts.Leads) / float64(row.Views)
+		}
+		blog := models.BlogLeadAnalytics{
+			BlogID:            row.BlogID,
+			BlogTitle:         row.Title,
+			LeadCount:         row.Counts.Leads,
+			QualifiedCount:    row.Counts.Qualified,
+			ConvertedCount:    row.Counts.Converted,
+			Revenue:           round2(row.Counts.Revenue),
+			ConversionRate:    round2(row.Counts.conversionRate()),
+			AvgLeadScore:      round2(row.Counts.LeadScore),
+			LeadGenEfficiency: math.Round(efficiency*10000) / 10000,
+			PerformanceRank:   i + 1,
+		}
+		if row.Slug != "" {
+			blog.BlogURL = blogPostURL(row.Slug)
+		}
+		blogs = append(blogs, blog)
+	}
+	return blogs, nil
+}
+
+// leadQualityMetrics grades the cohort's lead scores: tiers, conversion by score
+// range, score by capture weekday and the attributes that go with conversion.
+// Quality trends over time are filled in from the trend data.
+func leadQualityMetrics(db *gorm.DB, req models.BlogLeadAnalyticsRequest, summary models.LeadAnalyticsSummary) (models.LeadQualityMetrics, error) {
+	quality := models.LeadQualityMetrics{
+		OverallQualityScore: int(math.Round(summary.AvgLeadScore)),
+		QualityFactors:      []models.QualityFactor{},
+	}
+
+	// Leads are counted per score so the ranges and tiers come from the analytics helpers
+	var scores []struct {
+		LeadScore      int
+		Leads          int
+		Converted      int
+		Revenue        float64
+		ConvertSeconds float64
+		Timed          int
+	}
+	if err := leadCohort(db, req, req.StartDate, req.EndDate).
+		Select(`lead_score, COUNT(*) AS leads, SUM(status = ?) AS converted,
+			SUM(CASE WHEN status = ? THEN conversion_value ELSE 0 END) AS revenue,
+			SUM(CASE WHEN status = ? AND converted_at IS NOT NULL THEN TIMESTAMPDIFF(SECOND, captured_at, converted_at) ELSE 0 END) AS convert_seconds,
+			SUM(status = ? AND converted_at IS NOT NULL) AS timed`,
+			workflow.LeadStatusConverted, workflow.LeadStatusConverted, workflow.LeadStatusConverted, workflow.LeadStatusConverted).
+		Group("lead_score").
+		Scan(&scores).Error; err != nil {
+		return quality, err
+	}
+
+	labels := analytics.ScoreRanges()
+	ranges := make([]struct {
+		leads, converted, timed int
+		revenue, seconds        float64
+	}, len(labels))
+	tiers := map[string]int{}
+	for _, s := range scores {
+		r := &ranges[analytics.ScoreRangeIndex(s.LeadScore)]
+		r.leads += s.Leads
+		r.converted += s.Converted
+		r.timed += s.Timed
+		r.revenue += s.Revenue
+		r.seconds += s.ConvertSeconds
+		tiers[analytics.QualityTier(s.LeadScore)] += s.Leads
+	}
+
+	quality.ScoreRangeAnalysis = make([]models.ScoreRangeAnalysis, len(labels))
+	for i, r := range ranges {
+		analysis := models.ScoreRangeAnalysis{
+			ScoreRange:     labels[i],
+			LeadCount:      r.leads,
+			ConversionRate: round2(performanceCalculator.CalculateConversionRate(r.converted, r.leads)),
+		}
+		if r.converted > 0 {
+			analysis.AvgRevenue = round2(r.revenue / float64(r.converted))
+		}
+		if r.timed > 0 {
+			analysis.TimeToConvert = round2(r.seconds / float64(r.timed) / 3600)
+		}
+		quality.ScoreRangeAnalysis[i] = analysis
+	}
+
+	high, medium, low := tiers[analytics.ImpactHigh], tiers[analytics.ImpactMedium], tiers[analytics.ImpactLow]
+	total := high + medium + low
+	quality.QualityDistribution = models.LeadQualityDistribution{
+		HighQuality:   high,
+		MediumQuality: medium,
+		LowQuality:    low,
+		Percentages: models.QualityPercentages{
+			High:   round2(percentage(high, total)),
+			Medium: round2(percentage(medium, total)),
+			Low:    round2(percentage(low, total)),
+		},
+	}
+
+	// WEEKDAY counts from Monday = 0
+	var weekdays []struct {
+		Weekday   int
+		LeadScore float64
+	}
+	if err := leadCohort(db, req, req.StartDate, req.EndDate).
+		Select("WEEKDAY(captured_at) AS weekday, AVG(lead_score) AS lead_score").
+		Group("weekday").
+		Scan(&weekdays).Error; err != nil {
+		return quality, err
+	}
+	quality.QualityTrends.SeasonalPatterns = make(map[string]float64, len(weekdays))
+	for _, day := range weekdays {
+		quality.QualityTrends.SeasonalPatterns[time.Weekday((day.Weekday+1)%7).String()] = round2(day.LeadScore)
+	}
+
+	for _, factor := range leadQualityFactors {
+		var sums struct {
+			N, SumX, SumY, SumXY, SumX2 float64
+		}
+		if err := leadCohort(db, req, req.StartDate, req.EndDate).
+			Select(fmt.Sprintf(`COUNT(*) AS n, SUM(%[1]s) AS sum_x, SUM(status = ?) AS sum_y,
+				SUM(CASE WHEN status = ? THEN %[1]s ELSE 0 END) AS sum_xy, SUM(%[1]s * %[1]s) AS sum_x2`, factor.column),
+				workflow.LeadStatusConverted, workflow.LeadStatusConverted).
+			Scan(&sums).Error; err != nil {
+			return quality, err
+		}
+		// Conversion is 0 or 1, so its sum of squares is its sum
+		r := analytics.Correlation(sums.N, sums.SumX, sums.SumY, sums.SumXY, sums.SumX2, sums.SumY)
+		if r == 0 {
+			continue
+		}
+		impact, direction := analytics.ImpactPositive, "more"
+		if r < 0 {
+			impact, direction = analytics.ImpactNegative, "less"
+		}
+		quality.QualityFactors = append(quality.QualityFactors, models.QualityFactor{
+			Factor:      factor.column,
+			Impact:      impact,
+			Correlation: math.Round(r*1000) / 1000,
+			Description: fmt.Sprintf("Leads with a higher %s convert %s often (r = %.2f)", factor.label, direction, r),
+		})
+	}
+	sort.SliceStable(quality.QualityFactors, func(i, j int) bool {
+		return math.Abs(quality.QualityFactors[i].Correlation) > math.Abs(quality.QualityFactors[j].Correlation)
+	})
+
+	return quality, nil
+}
+
+// leadTrend follows the cohort per capture bucket: leads captured, how many
+// of them have qualified and converted, their revenue and average score
+func leadTrend(db *gorm.DB, req models.BlogLeadAnalyticsRequest) ([]models.LeadTrendData, error) {
+	buckets := analytics.Buckets(req.StartDate, req.EndDate, req.Granularity)
+	trend := make([]models.LeadTrendData, len(buckets))
+	index := make(map[string]int, len(buckets))
+	for i, bucket := range buckets {
+		trend[i].Date = bucket
+		index[bucket.Format("2006-01-02")] = i
+	}
+
+	var rows []struct {
+		Bucket string
+		Counts leadCounts `gorm:"embedded"`
+	}
+	if err := leadCohortWithHistory(db, req).
+		Select(bucketExpression("blog_leads.captured_at", req.Granularity) + " AS bucket, " + leadCountColumns).
+		Group("bucket").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		i, ok := index[row.Bucket]
+		if !ok {
+			continue
+		}
+		trend[i].LeadsCaptured = row.Counts.Leads
+		trend[i].LeadsQualified = row.Counts.Qualified
+		trend[i].LeadsConverted = row.Counts.Converted
+		trend[i].Revenue = round2(row.Counts.Revenue)
+		trend[i].AvgLeadScore = round2(row.Counts.LeadScore)
+		trend[i].ConversionRate = round2(row.Counts.conversionRate())
+	}
+	return trend, nil
+}
+
+// qualityTrends reads the average lead score over the buckets with leads and
+// reports whether it is improving
+func qualityTrends(trend []models.LeadTrendData, seasonal map[string]float64) models.QualityTrendAnalysis {
+	quality := models.QualityTrendAnalysis{
+		TrendDirection:   "stable",
+		QualityOverTime:  []models.QualityDataPoint{},
+		SeasonalPatterns: seasonal,
+	}
+
+	series := []analytics.TrendDataPoint{}
+	for _, point := range trend {
+		if point.LeadsCaptured == 0 {
+			continue
+		}
+		quality.QualityOverTime = append(quality.QualityOverTime, models.QualityDataPoint{
+			Date:           point.Date,
+			AvgScore:       point.AvgLeadScore,
+			LeadCount:      point.LeadsCaptured,
+			ConversionRate: point.ConversionRate,
+		})
+		series = append(series, analytics.TrendDataPoint{Date: point.Date, Value: point.AvgLeadScore})
+	}
+
+	switch direction, _ := analytics.SummarizeTrend(trendAnalyzer.AnalyzeTrends(series)); direction {
+	case analytics.TrendUp:
+		quality.TrendDirection = "improving"
+	case analytics.TrendDown:
+		quality.TrendDirection = "declining"
+	}
+	return quality
+}
+
+// leadPredictions forecasts daily captures from the last leadPredictionDays
+// complete days of the window, over the next 30, 60 and 90 days. The forecast
+// is backtested on the last leadBacktestDays days when there are at least
+// twice as many. Revenue assumes the window's value per captured lead.
+func leadPredictions(db *gorm.DB, req models.BlogLeadAnalyticsRequest) (models.LeadPredictions, error) {
+	predictions := models.LeadPredictions{
+		TrendPredictions:   []models.LeadTrendPrediction{},
+		RecommendedActions: []string{},
+		Horizons:           []models.LeadForecastHorizon{},
+	}
+
+	// Today's captures are still coming in
+	end := req.EndDate
+	if today := time.Now().UTC().Truncate(24 * time.Hour); !end.Before(today) {
+		end = today.Add(-time.Nanosecond)
+	}
+	start := req.StartDate
+	if earliest := end.AddDate(0, 0, -(leadPredictionDays - 1)); earliest.After(start) {
+		start = earliest
+	}
+	// Captures are forecast whatever the leads' current status, which would
+	// otherwise drop recent days' leads that have not moved on yet
+	captures := req
+	captures.Status = nil
+	series := []analytics.TrendDataPoint{}
+	if start.Before(end) {
+		values := []bucketValue{}
+		if err := leadCohort(db, captures, start, end).
+			Select(bucketExpression("captured_at", analytics.GranularityDay) + " AS bucket, COUNT(*) AS value").
+			Group("bucket").
+			Scan(&values).Error; err != nil {
+			return predictions, err
+		}
+		captured := make(map[string]float64, len(values))
+		for _, value := range values {
+			captured[value.Bucket] = value.Value
+		}
+		for _, day := range analytics.Buckets(start, end, analytics.GranularityDay) {
+			series = append(series, analytics.TrendDataPoint{Date: day, Value: captured[day.Format("2006-01-02")]})
+		}
+	}
+
+	// Predicted captures are priced from the same unfiltered cohort
+	var counts leadCounts
+	if err := leadCohort(db, captures, req.StartDate, req.EndDate).
+		Select("COUNT(*) AS leads, SUM(CASE WHEN status = ? THEN conversion_value ELSE 0 END) AS revenue", workflow.LeadStatusConverted).
+		Scan(&counts).Error; err != nil {
+		return predictions, err
+	}
+	leadValue := counts.valuePerLead()
+
+	forecast := leadForecaster.Forecast(series, leadForecastHorizons[len(leadForecastHorizons)-1])
+	predictions.Method = forecast.Method
+	for _, days := range leadForecastHorizons {
+		leads, lower, upper := forecast.Total(days)
+		predictions.Horizons = append(predictions.Horizons, models.LeadForecastHorizon{
+			Days:             days,
+			PredictedLeads:   int(math.Round(leads)),
+			PredictedRevenue: round2(leads * leadValue),
+			LeadRange:        models.PredictionRange{Lower: round2(lower), Upper: round2(upper)},
+			RevenueRange:     models.PredictionRange{Lower: round2(lower * leadValue), Upper: round2(upper * leadValue)},
+		})
+	}
+	nextMonth, _, _ := forecast.Total(30)
+	predictions.NextMonthLeads = int(math.Round(nextMonth))
+	predictions.NextMonthRevenue = round2(nextMonth * leadValue)
+	for _, point := range forecast.Points {
+		leads := math.Max(point.PredictedValue, 0)
+		predictions.TrendPredictions = append(predictions.TrendPredictions, models.LeadTrendPrediction{
+			Date:             point.Date,
+			PredictedLeads:   int(math.Round(leads)),
+			PredictedRevenue: round2(leads * leadValue),
+			ConfidenceRange: models.PredictionRange{
+				Lower: round2(math.Max(point.LowerBound, 0)),
+				Upper: round2(math.Max(point.UpperBound, 0)),
+			},
+		})
+	}
+
+	if len(series) >= 2*leadBacktestDays {
+		backtest := leadForecaster.Backtest(series, leadBacktestDays)
+		predictions.Backtest = &models.LeadForecastBacktest{
+			HoldoutDays: backtest.HoldoutPoints,
+			MAPE:        round2(backtest.MAPE),
+			MAE:         round2(backtest.MAE),
+			Coverage:    round2(backtest.Coverage),
+		}
+		predictions.PredictionConfidence = round2(math.Max(100-backtest.MAPE, 0))
+		if backtest.MAPE > roughForecastError {
+			predictions.RecommendedActions = append(predictions.RecommendedActions,
+				fmt.Sprintf("Daily lead volume is hard to forecast (%.0f%% backtest error); treat predictions as rough estimates", backtest.MAPE))
+		}
+	}
+	predictions.RecommendedActions = append(predictions.RecommendedActions, trendAnalyzer.AnalyzeTrends(series).Insights...)
+	return predictions, nil
+}
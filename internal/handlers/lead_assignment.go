@@ -0,0 +1,462 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/assignment"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Events that trigger automatic lead assignment
+const (
+	assignmentTriggerCapture       = "capture"
+	assignmentTriggerQualification = "qualification"
+)
+
+// AssignmentRuleHandler handles lead assignment rule endpoints
+type AssignmentRuleHandler struct{}
+
+// NewAssignmentRuleHandler creates a new assignment rule handler instance
+func NewAssignmentRuleHandler() *AssignmentRuleHandler {
+	return &AssignmentRuleHandler{}
+}
+
+// ListRules returns assignment rules in evaluation order
+func (h *AssignmentRuleHandler) ListRules(c *gin.Context) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	query := db.Model(&models.LeadAssignmentRule{})
+	switch c.Query("active") {
+	case "":
+	case "true":
+		query = query.Where("active = ?", true)
+	case "false":
+		query = query.Where("active = ?", false)
+	default:
+		respondError(c, http.StatusBadRequest, "Invalid query parameter", "INVALID_PARAMETER", "active must be true or false")
+		return
+	}
+
+	rules := []models.LeadAssignmentRule{}
+	if err := query.Order("priority ASC, id ASC").Find(&rules).Error; err != nil {
+		logger.Error("Failed to list assignment rules", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve assignment rules", "DATABASE_ERROR", "Unable to list assignment rules")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Assignment rules retrieved successfully", rules)
+}
+
+// GetRule returns a single assignment rule by ID
+func (h *AssignmentRuleHandler) GetRule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid rule ID", "INVALID_ID", "Rule ID must be a positive integer")
+		return
+	}
+
+	rule, found := h.findRule(c, id)
+	if !found {
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Assignment rule retrieved successfully", rule)
+}
+
+// CreateRule creates an assignment rule
+func (h *AssignmentRuleHandler) CreateRule(c *gin.Context) {
+	var req models.LeadAssignmentRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	user, ok := middleware.GetCurrentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required", "UNAUTHENTICATED", "User context not found")
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	if !h.validateRule(c, db, &req) {
+		return
+	}
+
+	rule := models.LeadAssignmentRule{CreatedBy: user.ID}
+	applyRuleRequest(&rule, &req)
+
+	start := time.Now()
+	err := db.Create(&rule).Error
+	logger.LogDatabaseOperation("create", "lead_assignment_rules", rule.ID, time.Since(start), err)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to create assignment rule", "DATABASE_ERROR", "Unable to create assignment rule")
+		return
+	}
+
+	logger.LogBusinessEvent("assignment_rule_created", "lead_assignment_rule", rule.ID, map[string]interface{}{
+		"user_id": user.ID,
+	})
+
+	respondSuccess(c, http.StatusCreated, "Assignment rule created successfully", rule)
+}
+
+// UpdateRule replaces an assignment rule's criteria and target. Changing the
+// pool keeps the round-robin position when the last assigned user is still in it.
+func (h *AssignmentRuleHandler) UpdateRule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid rule ID", "INVALID_ID", "Rule ID must be a positive integer")
+		return
+	}
+
+	var req models.LeadAssignmentRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	rule, found := h.findRule(c, id)
+	if !found {
+		return
+	}
+
+	db := database.GetDB()
+	if !h.validateRule(c, db, &req) {
+		return
+	}
+
+	applyRuleRequest(rule, &req)
+
+	start := time.Now()
+	err := db.Model(rule).Updates(map[string]interface{}{
+		"name":               rule.Name,
+		"priority":           rule.Priority,
+		"active":             rule.Active,
+		"category_id":        rule.CategoryID,
+		"country":            rule.Country,
+		"auto_qualification": rule.AutoQualification,
+		"min_score":          rule.MinScore,
+		"max_score":          rule.MaxScore,
+		"utm_campaign":       rule.UTMCampaign,
+		"assign_to_user_id":  rule.AssignToUserID,
+		"user_pool":          rule.UserPool,
+	}).Error
+	logger.LogDatabaseOperation("update", "lead_assignment_rules", rule.ID, time.Since(start), err)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to update assignment rule", "DATABASE_ERROR", "Unable to update assignment rule")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	logger.LogBusinessEvent("assignment_rule_updated", "lead_assignment_rule", rule.ID, map[string]interface{}{
+		"user_id": userID,
+	})
+
+	respondSuccess(c, http.StatusOK, "Assignment rule updated successfully", rule)
+}
+
+// DeleteRule deletes an assignment rule. Leads it assigned keep their owner.
+func (h *AssignmentRuleHandler) DeleteRule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid rule ID", "INVALID_ID", "Rule ID must be a positive integer")
+		return
+	}
+
+	rule, found := h.findRule(c, id)
+	if !found {
+		return
+	}
+
+	start := time.Now()
+	err := database.GetDB().Delete(rule).Error
+	logger.LogDatabaseOperation("delete", "lead_assignment_rules", rule.ID, time.Since(start), err)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to delete assignment rule", "DATABASE_ERROR", "Unable to delete assignment rule")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	logger.LogBusinessEvent("assignment_rule_deleted", "lead_assignment_rule", rule.ID, map[string]interface{}{
+		"user_id": userID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Assignment rule deleted successfully",
+	})
+}
+
+// findRule loads an assignment rule by ID and writes the error response when it cannot
+func (h *AssignmentRuleHandler) findRule(c *gin.Context, id uint) (*models.LeadAssignmentRule, bool) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return nil, false
+	}
+
+	var rule models.LeadAssignmentRule
+	if err := db.First(&rule, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "Assignment rule not found", "RULE_NOT_FOUND", "Assignment rule does not exist")
+			return nil, false
+		}
+		logger.Error("Failed to load assignment rule", err, map[string]interface{}{"rule_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve assignment rule", "DATABASE_ERROR", "Unable to load assignment rule")
+		return nil, false
+	}
+
+	return &rule, true
+}
+
+// validateRule checks a rule's target and references and writes the error
+// response when they are invalid. Duplicate pool entries are dropped.
+func (h *AssignmentRuleHandler) validateRule(c *gin.Context, db *gorm.DB, req *models.LeadAssignmentRuleRequest) bool {
+	req.UserPool = uniqueIDs(req.UserPool)
+	if (req.AssignToUserID == nil) == (len(req.UserPool) == 0) {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_TARGET", "exactly one of assign_to_user_id and user_pool must be set")
+		return false
+	}
+	if req.MinScore != nil && req.MaxScore != nil && *req.MinScore > *req.MaxScore {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_SCORE_RANGE", "min_score must not be greater than max_score")
+		return false
+	}
+
+	if req.CategoryID != nil {
+		var count int64
+		if err := db.Model(&models.Category{}).Where("id = ?", *req.CategoryID).Count(&count).Error; err != nil {
+			logger.Error("Failed to check category", err, map[string]interface{}{"category_id": *req.CategoryID})
+			respondError(c, http.StatusInternalServerError, "Failed to validate assignment rule", "DATABASE_ERROR", "Unable to load category")
+			return false
+		}
+		if count == 0 {
+			respondError(c, http.StatusBadRequest, "Invalid request data", "CATEGORY_NOT_FOUND", "category_id does not exist")
+			return false
+		}
+	}
+
+	userIDs := req.UserPool
+	if req.AssignToUserID != nil {
+		userIDs = []uint{*req.AssignToUserID}
+	}
+	var count int64
+	if err := db.Model(&models.AdminUser{}).Where("id IN ?", userIDs).Count(&count).Error; err != nil {
+		logger.Error("Failed to check assignment users", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to validate assignment rule", "DATABASE_ERROR", "Unable to load users")
+		return false
+	}
+	if int(count) != len(userIDs) {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_ASSIGNEE", "every assigned user must exist")
+		return false
+	}
+
+	return true
+}
+
+// applyRuleRequest copies a validated request onto a rule
+func applyRuleRequest(rule *models.LeadAssignmentRule, req *models.LeadAssignmentRuleRequest) {
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Priority = req.Priority
+	rule.Active = req.Active == nil || *req.Active
+	rule.CategoryID = req.CategoryID
+	rule.Country = strings.TrimSpace(req.Country)
+	rule.AutoQualification = req.AutoQualification
+	rule.MinScore = req.MinScore
+	rule.MaxScore = req.MaxScore
+	rule.UTMCampaign = strings.TrimSpace(req.UTMCampaign)
+	rule.AssignToUserID = req.AssignToUserID
+
+	rule.UserPool = nil
+	if len(req.UserPool) > 0 {
+		rule.UserPool = make(models.JSONArray, 0, len(req.UserPool))
+		for _, id := range req.UserPool {
+			rule.UserPool = append(rule.UserPool, id)
+		}
+	}
+}
+
+// assignLead routes an unowned lead to the owner picked by the first matching
+// active rule and records an assigned activity. Rules whose targets are all
+// inactive are passed over. Returns the rule used, or nil when the lead was
+// left unassigned. Quarantined leads are not routed until consent is given.
+func assignLead(tx *gorm.DB, lead *models.BlogLead, trigger string) (*models.LeadAssignmentRule, error) {
+	if lead.AssignedTo != nil || lead.QuarantinedAt != nil || getEnv("LEAD_AUTO_ASSIGNMENT", "true") != "true" {
+		return nil, nil
+	}
+
+	rules := []models.LeadAssignmentRule{}
+	if err := tx.Where("active = ?", true).Order("priority ASC, id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	candidate, err := assignmentCandidate(tx, lead, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if !ruleCriteria(rule).Matches(candidate) {
+			continue
+		}
+
+		userID, mode, found, err := pickRuleTarget(tx, rule)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+
+		// An owner set concurrently wins over the rule
+		result := tx.Model(&models.BlogLead{}).
+			Where("id = ? AND assigned_to IS NULL", lead.ID).
+			Update("assigned_to", userID)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil, nil
+		}
+		lead.AssignedTo = &userID
+
+		if mode == "round_robin" {
+			if err := tx.Model(rule).UpdateColumn("last_assigned_user_id", userID).Error; err != nil {
+				return nil, err
+			}
+			rule.LastAssignedUserID = &userID
+		}
+
+		activity := models.LeadActivity{
+			LeadID:       lead.ID,
+			ActivityType: models.LeadActivityAssigned,
+			Title:        fmt.Sprintf("Assigned by rule %q", rule.Name),
+			Metadata: models.JSONMap{
+				"rule_id":     rule.ID,
+				"rule_name":   rule.Name,
+				"trigger":     trigger,
+				"mode":        mode,
+				"assigned_to": userID,
+			},
+		}
+		if err := tx.Create(&activity).Error; err != nil {
+			return nil, err
+		}
+
+		return rule, nil
+	}
+
+	return nil, nil
+}
+
+// assignmentCandidate collects the lead attributes rules match on. The category
+// path is only loaded when a rule filters on category.
+func assignmentCandidate(tx *gorm.DB, lead *models.BlogLead, rules []models.LeadAssignmentRule) (assignment.Lead, error) {
+	candidate := assignment.Lead{
+		Country:           lead.Country,
+		AutoQualification: lead.AutoQualification,
+		Score:             lead.LeadScore,
+		UTMCampaign:       lead.UTMCampaign,
+	}
+
+	for _, rule := range rules {
+		if rule.CategoryID == nil {
+			continue
+		}
+		var paths []string
+		if err := tx.Table("blogs").
+			Joins("JOIN categories ON categories.id = blogs.category_id").
+			Where("blogs.id = ?", lead.BlogID).
+			Pluck("categories.path", &paths).Error; err != nil {
+			return candidate, err
+		}
+		if len(paths) > 0 {
+			candidate.CategoryPath = paths[0]
+		}
+		break
+	}
+
+	return candidate, nil
+}
+
+// ruleCriteria converts a rule's match columns to assignment criteria
+func ruleCriteria(rule *models.LeadAssignmentRule) assignment.Criteria {
+	return assignment.Criteria{
+		CategoryID:        rule.CategoryID,
+		Country:           rule.Country,
+		AutoQualification: rule.AutoQualification,
+		MinScore:          rule.MinScore,
+		MaxScore:          rule.MaxScore,
+		UTMCampaign:       rule.UTMCampaign,
+	}
+}
+
+// pickRuleTarget returns the active user a rule assigns to and the assignment
+// mode. Pool rules are locked so concurrent captures advance the round robin
+// one user at a time.
+func pickRuleTarget(tx *gorm.DB, rule *models.LeadAssignmentRule) (uint, string, bool, error) {
+	if rule.AssignToUserID != nil {
+		var count int64
+		if err := tx.Model(&models.AdminUser{}).Where("id = ? AND active = ?", *rule.AssignToUserID, true).Count(&count).Error; err != nil {
+			return 0, "", false, err
+		}
+		return *rule.AssignToUserID, "user", count > 0, nil
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(rule, rule.ID).Error; err != nil {
+		return 0, "", false, err
+	}
+
+	pool := rule.PoolUserIDs()
+	if len(pool) == 0 {
+		return 0, "", false, nil
+	}
+
+	var activeIDs []uint
+	if err := tx.Model(&models.AdminUser{}).Where("id IN ? AND active = ?", pool, true).Pluck("id", &activeIDs).Error; err != nil {
+		return 0, "", false, err
+	}
+	active := make(map[uint]bool, len(activeIDs))
+	for _, id := range activeIDs {
+		active[id] = true
+	}
+
+	userID, found := assignment.NextInPool(pool, rule.LastAssignedUserID, active)
+	return userID, "round_robin", found, nil
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence's position
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
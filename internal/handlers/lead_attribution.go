@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/analytics"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// attributionBatchSize is the number of converted leads re-attributed per transaction
+const attributionBatchSize = 200
+
+var roiCalculator = analytics.NewROICalculator()
+
+// RerunAttribution recalculates touchpoint attribution for every converted lead
+// with the configured model and rebuilds revenue attribution on the blogs of
+// their touchpoints. Run it after changing ATTRIBUTION_MODEL.
+func (h *LeadHandler) RerunAttribution(c *gin.Context) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	model := attributionModel()
+	start := time.Now()
+
+	// Weights left over from leads that are no longer converted are cleared
+	// together with the revenue of their blogs
+	var cleared int64
+	refreshed := map[uint]bool{}
+	err := database.Transaction(func(tx *gorm.DB) error {
+		var blogIDs []uint
+		if err := tx.Raw("SELECT DISTINCT blog_id FROM lead_touchpoints WHERE blog_id IS NOT NULL AND "+staleAttributionCondition,
+			workflow.LeadStatusConverted).Scan(&blogIDs).Error; err != nil {
+			return err
+		}
+		result := tx.Exec("UPDATE lead_touchpoints SET attribution_weight = 0, conversion_value = 0, updated_at = ? WHERE "+staleAttributionCondition,
+			time.Now(), workflow.LeadStatusConverted)
+		if result.Error != nil {
+			return result.Error
+		}
+		cleared = result.RowsAffected
+		for _, id := range blogIDs {
+			refreshed[id] = true
+		}
+		return refreshBlogRevenue(tx, blogIDs)
+	})
+	if err != nil {
+		logger.Error("Failed to clear attribution", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to rerun attribution", "DATABASE_ERROR", "Unable to clear attribution")
+		return
+	}
+
+	// Each batch refreshes the revenue of its blogs in the same transaction, so
+	// blogs never show revenue from a half-applied rerun
+	leads := 0
+	var batch []models.BlogLead
+	result := db.Where("status = ? AND merged_into_id IS NULL", workflow.LeadStatusConverted).
+		FindInBatches(&batch, attributionBatchSize, func(_ *gorm.DB, _ int) error {
+			return database.Transaction(func(tx *gorm.DB) error {
+				blogIDs := []uint{}
+				for i := range batch {
+					ids, err := attributeLead(tx, &batch[i], model)
+					if err != nil {
+						return err
+					}
+					blogIDs = append(blogIDs, ids...)
+				}
+				blogIDs = uniqueIDs(blogIDs)
+				if err := refreshBlogRevenue(tx, blogIDs); err != nil {
+					return err
+				}
+				for _, id := range blogIDs {
+					refreshed[id] = true
+				}
+				leads += len(batch)
+				return nil
+			})
+		})
+	if result.Error != nil {
+		logger.Error("Failed to rerun attribution", result.Error, map[string]interface{}{"model": model})
+		respondError(c, http.StatusInternalServerError, "Failed to rerun attribution", "DATABASE_ERROR", "Unable to attribute leads")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	logger.LogBusinessEvent("attribution_rerun", "lead_attribution", nil, map[string]interface{}{
+		"user_id":     userID,
+		"model":       model,
+		"leads":       leads,
+		"duration_ms": time.Since(start).Milliseconds(),
+	})
+
+	respondSuccess(c, http.StatusOK, "Attribution recalculated successfully", map[string]interface{}{
+		"model":             model,
+		"leads_attributed":  leads,
+		"touchpoints_reset": cleared,
+		"blogs_updated":     len(refreshed),
+	})
+}
+
+// attributionModel returns the configured attribution model, falling back to
+// linear when ATTRIBUTION_MODEL is not a supported model
+func attributionModel() string {
+	model := getEnv("ATTRIBUTION_MODEL", analytics.AttributionLinear)
+	if !analytics.IsValidAttributionModel(model) {
+		logger.Warn("Unknown attribution model, using linear", map[string]interface{}{"model": model})
+		return analytics.AttributionLinear
+	}
+	return model
+}
+
+// reattributeLead recalculates a lead's touchpoint attribution after its
+// conversion changed and refreshes revenue attribution on the touched blogs
+func reattributeLead(tx *gorm.DB, lead *models.BlogLead) error {
+	blogIDs, err := attributeLead(tx, lead, attributionModel())
+	if err != nil {
+		return err
+	}
+	return refreshBlogRevenue(tx, blogIDs)
+}
+
+// attributeLead spreads a converted lead's conversion value over the
+// touchpoints recorded up to its conversion and stores each touchpoint's weight
+// and value share. Leads that are not converted have their attribution
+// cleared. Returns the blogs among the lead's touchpoints.
+func attributeLead(tx *gorm.DB, lead *models.BlogLead, model string) ([]uint, error) {
+	touchpoints := []models.LeadTouchpoint{}
+	if err := tx.Where("lead_id = ?", lead.ID).Order("created_at ASC, id ASC").Find(&touchpoints).Error; err != nil {
+		return nil, err
+	}
+
+	converted := lead.Status == workflow.LeadStatusConverted && lead.MergedIntoID == nil && lead.ConvertedAt != nil
+	eligible := 0
+	touchedAt := []time.Time{}
+	if converted {
+		for _, tp := range touchpoints {
+			if tp.CreatedAt.After(*lead.ConvertedAt) {
+				break
+			}
+			touchedAt = append(touchedAt, tp.CreatedAt)
+			eligible++
+		}
+	}
+	weights := roiCalculator.AttributionWeights(model, touchedAt, timeOrZero(lead.ConvertedAt))
+
+	var attributed float64
+	blogIDs := []uint{}
+	for i := range touchpoints {
+		tp := &touchpoints[i]
+		var weight, value float64
+		if i < eligible {
+			weight = math.Round(weights[i]*10000) / 10000
+			value = math.Round(weights[i]*lead.ConversionValue*100) / 100
+		}
+
+		if tp.BlogID != nil {
+			blogIDs = append(blogIDs, *tp.BlogID)
+			attributed += value
+		}
+		if weight == tp.AttributionWeight && value == tp.ConversionValue {
+			continue
+		}
+		// Updates bumps updated_at, so the rollup rebuilds the conversion day
+		if err := tx.Model(tp).Updates(map[string]interface{}{
+			"attribution_weight": weight,
+			"conversion_value":   value,
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	attributed = math.Round(attributed*100) / 100
+	if err := tx.Model(&models.BlogLead{}).Where("id = ?", lead.ID).
+		Update("attributed_revenue", attributed).Error; err != nil {
+		return nil, err
+	}
+	lead.AttributedRevenue = attributed
+
+	return uniqueIDs(blogIDs), nil
+}
+
+// staleAttributionCondition matches touchpoints still carrying attribution of
+// leads that are no longer converted or were merged away
+const staleAttributionCondition = `(attribution_weight <> 0 OR conversion_value <> 0)
+	AND lead_id IN (SELECT id FROM blog_leads WHERE status <> ? OR merged_into_id IS NOT NULL)`
+
+// refreshBlogRevenueSQL recomputes blogs.revenue_attribution from touchpoint value shares
+const refreshBlogRevenueSQL = `UPDATE blogs SET revenue_attribution =
+	(SELECT COALESCE(SUM(lead_touchpoints.conversion_value), 0) FROM lead_touchpoints WHERE lead_touchpoints.blog_id = blogs.id)`
+
+// refreshBlogRevenue recomputes revenue attribution for the given blogs
+func refreshBlogRevenue(tx *gorm.DB, blogIDs []uint) error {
+	if len(blogIDs) == 0 {
+		return nil
+	}
+	return tx.Exec(refreshBlogRevenueSQL+" WHERE id IN ?", blogIDs).Error
+}
+
+// timeOrZero dereferences an optional time
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
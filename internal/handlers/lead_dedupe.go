@@ -0,0 +1,482 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/leadmatch"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errLeadMerged aborts a merge when either lead was merged away by a concurrent merge
+var errLeadMerged = errors.New("lead was merged")
+
+// maxFuzzyCandidates bounds the leads compared by fuzzy name and company matching
+const maxFuzzyCandidates = 200
+
+// leadProfileFields are lead columns a duplicate can fill in when they are blank
+var leadProfileFields = []struct {
+	column string
+	value  func(*models.BlogLead) *string
+}{
+	{"name", func(l *models.BlogLead) *string { return &l.Name }},
+	{"first_name", func(l *models.BlogLead) *string { return &l.FirstName }},
+	{"last_name", func(l *models.BlogLead) *string { return &l.LastName }},
+	{"company", func(l *models.BlogLead) *string { return &l.Company }},
+	{"job_title", func(l *models.BlogLead) *string { return &l.JobTitle }},
+	{"phone", func(l *models.BlogLead) *string { return &l.Phone }},
+	{"website", func(l *models.BlogLead) *string { return &l.Website }},
+	{"linkedin_profile", func(l *models.BlogLead) *string { return &l.LinkedInProfile }},
+	{"device_type", func(l *models.BlogLead) *string { return &l.DeviceType }},
+	{"browser", func(l *models.BlogLead) *string { return &l.Browser }},
+	{"operating_system", func(l *models.BlogLead) *string { return &l.OperatingSystem }},
+	{"country", func(l *models.BlogLead) *string { return &l.Country }},
+	{"region", func(l *models.BlogLead) *string { return &l.Region }},
+	{"city", func(l *models.BlogLead) *string { return &l.City }},
+	{"timezone", func(l *models.BlogLead) *string { return &l.Timezone }},
+}
+
+// MergeLeads folds a duplicate lead into the lead in the URL. The duplicate's
+// activities and touchpoints move to the primary lead, tags and custom fields
+// are combined, and the duplicate is kept with merged_into_id set. A lead_merges
+// row stores a snapshot of the duplicate.
+func (h *LeadHandler) MergeLeads(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid lead ID", "INVALID_ID", "Lead ID must be a positive integer")
+		return
+	}
+
+	var req models.LeadMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if req.MergeLeadID == id {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "SAME_LEAD", "A lead cannot be merged into itself")
+		return
+	}
+
+	user, ok := middleware.GetCurrentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required", "UNAUTHENTICATED", "User context not found")
+		return
+	}
+
+	primary, found := h.findLead(c, id)
+	if !found || rejectMergedLead(c, primary) {
+		return
+	}
+	duplicate, found := h.findLead(c, req.MergeLeadID)
+	if !found || rejectMergedLead(c, duplicate) {
+		return
+	}
+
+	var merge models.LeadMerge
+	var changed []string
+	err := database.Transaction(func(tx *gorm.DB) error {
+		// Concurrent merges of the same leads serialize here and see each
+		// other's merged_into_id
+		if err := lockLeads(tx, primary, duplicate); err != nil {
+			return err
+		}
+		if primary.MergedIntoID != nil || duplicate.MergedIntoID != nil {
+			return errLeadMerged
+		}
+
+		snapshot, err := leadSnapshot(duplicate)
+		if err != nil {
+			return err
+		}
+
+		var updates map[string]interface{}
+		updates, changed = combineLeads(primary, duplicate)
+		if len(updates) > 0 {
+			if err := tx.Model(&models.BlogLead{}).Where("id = ?", primary.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, model := range []interface{}{&models.LeadActivity{}, &models.LeadTouchpoint{}} {
+			if err := tx.Model(model).Where("lead_id = ?", duplicate.ID).Update("lead_id", primary.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.BlogLead{}).Where("id = ?", duplicate.ID).Update("merged_into_id", primary.ID).Error; err != nil {
+			return err
+		}
+
+		changedFields := make(models.JSONArray, 0, len(changed))
+		for _, field := range changed {
+			changedFields = append(changedFields, field)
+		}
+		merge = models.LeadMerge{
+			PrimaryLeadID: primary.ID,
+			MergedLeadID:  duplicate.ID,
+			MergedBy:      user.ID,
+			Notes:         req.Notes,
+			MergedLead:    snapshot,
+			ChangedFields: changedFields,
+		}
+		if err := tx.Create(&merge).Error; err != nil {
+			return err
+		}
+
+		activity := models.LeadActivity{
+			LeadID:       primary.ID,
+			ActivityType: models.LeadActivityMerged,
+			Title:        fmt.Sprintf("Merged duplicate lead #%d (%s)", duplicate.ID, duplicate.Email),
+			Description:  req.Notes,
+			UserID:       &user.ID,
+			Metadata: models.JSONMap{
+				"merge_id":       merge.ID,
+				"merged_lead_id": duplicate.ID,
+				"changed_fields": changed,
+			},
+		}
+		if err := tx.Create(&activity).Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(primary, primary.ID).Error; err != nil {
+			return err
+		}
+
+		// Moved touchpoints take part in the primary lead's attribution
+		if primary.Status == workflow.LeadStatusConverted || duplicate.Status == workflow.LeadStatusConverted {
+			return reattributeLead(tx, primary)
+		}
+		return nil
+	})
+	if errors.Is(err, errLeadMerged) {
+		if !rejectMergedLead(c, primary) {
+			rejectMergedLead(c, duplicate)
+		}
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to merge leads", err, map[string]interface{}{"lead_id": primary.ID, "merged_lead_id": duplicate.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to merge leads", "DATABASE_ERROR", "Unable to merge leads")
+		return
+	}
+
+	logger.LogBusinessEvent("leads_merged", "lead", primary.ID, map[string]interface{}{
+		"user_id":        user.ID,
+		"merged_lead_id": duplicate.ID,
+		"merge_id":       merge.ID,
+		"changed_fields": changed,
+	})
+
+	respondSuccess(c, http.StatusOK, "Leads merged successfully", map[string]interface{}{
+		"lead":  leadResponse(primary),
+		"merge": merge,
+	})
+}
+
+// lockLeads locks two leads for update in ID order, so that concurrent merges
+// cannot deadlock, and reloads them
+func lockLeads(tx *gorm.DB, a, b *models.BlogLead) error {
+	var locked []models.BlogLead
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", []uint{a.ID, b.ID}).
+		Order("id ASC").
+		Find(&locked).Error; err != nil {
+		return err
+	}
+	for i := range locked {
+		switch locked[i].ID {
+		case a.ID:
+			*a = locked[i]
+		case b.ID:
+			*b = locked[i]
+		}
+	}
+	return nil
+}
+
+// findDuplicateLead returns the existing lead a new capture belongs to, matching
+// on normalized email and, when LEAD_FUZZY_MATCHING is on, on name plus company
+func findDuplicateLead(tx *gorm.DB, lead *models.BlogLead) (*models.BlogLead, error) {
+	// Locking the address's index range makes concurrent first captures of one
+	// person conflict instead of each creating a lead; MySQL fails all but one
+	// with a deadlock and CaptureLead retries them
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("normalized_email = ?", lead.NormalizedEmail).
+		Find(&[]models.BlogLead{}).Error; err != nil {
+		return nil, err
+	}
+
+	var existing models.BlogLead
+	// Leads stored before normalized_email existed are matched on their email
+	err := tx.Where("merged_into_id IS NULL").
+		Where("normalized_email = ? OR (normalized_email IS NULL AND email = ?)", lead.NormalizedEmail, lead.Email).
+		Order("captured_at ASC, id ASC").
+		First(&existing).Error
+	switch {
+	case err == nil:
+		return &existing, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	if getEnv("LEAD_FUZZY_MATCHING", "false") != "true" {
+		return nil, nil
+	}
+
+	// Narrow candidates by the longest word of the company name, then compare in Go
+	companyWords := strings.Fields(leadmatch.NormalizeCompany(lead.Company))
+	if leadmatch.NormalizeName(lead.Name) == "" || len(companyWords) == 0 {
+		return nil, nil
+	}
+	sort.Slice(companyWords, func(i, j int) bool { return len(companyWords[i]) > len(companyWords[j]) })
+
+	candidates := []models.BlogLead{}
+	if err := tx.Where("merged_into_id IS NULL AND company LIKE ?", "%"+companyWords[0]+"%").
+		Order("captured_at ASC, id ASC").
+		Limit(maxFuzzyCandidates).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range candidates {
+		if leadmatch.SamePerson(lead.Name, lead.Company, candidates[i].Name, candidates[i].Company,
+			leadmatch.DefaultNameThreshold, leadmatch.DefaultCompanyThreshold) {
+			return &candidates[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// mergeCapture records a repeat capture on an existing lead: the touchpoint is
+// appended, blank profile fields, new tags and custom fields and consent are
+// taken from the capture, and the score is raised when the capture scored higher.
+// A capture matched on name and company under another address keeps that
+// address in the alternate_emails custom field, with an email_added activity.
+// Consent only counts for a lead that is not opted out or quarantined: those
+// are released by the confirmation link sent to their address, never by a
+// form anyone can submit.
+func mergeCapture(tx *gorm.DB, existing, capture *models.BlogLead, scored *models.LeadActivity, touchpoint *models.LeadTouchpoint) error {
+	updates := map[string]interface{}{}
+	for _, field := range leadProfileFields {
+		if current, incoming := field.value(existing), *field.value(capture); *current == "" && incoming != "" {
+			updates[field.column] = incoming
+		}
+	}
+
+	if tags, added := unionTags(existing.Tags, capture.Tags); added {
+		updates["tags"] = tags
+	}
+	custom, added := fillCustomFields(existing.CustomFields, capture.CustomFields)
+	newEmail := capture.NormalizedEmail != existing.NormalizedEmail && !strings.EqualFold(capture.Email, existing.Email)
+	if newEmail {
+		custom, newEmail = addAlternateEmail(custom, capture.Email)
+	}
+	if added || newEmail {
+		updates["custom_fields"] = custom
+	}
+
+	if capture.ConsentGiven && !existing.ConsentGiven && !existing.OptedOut && existing.QuarantinedAt == nil {
+		updates["consent_given"] = true
+		updates["consent_type"] = capture.ConsentType
+		updates["consent_timestamp"] = capture.ConsentTimestamp
+	}
+
+	rescored := capture.LeadScore > existing.LeadScore
+	if rescored {
+		updates["lead_score"] = capture.LeadScore
+		updates["auto_qualification"] = capture.AutoQualification
+	}
+
+	if len(updates) > 0 {
+		if err := tx.Model(existing).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+
+	if newEmail {
+		activity := models.LeadActivity{
+			LeadID:       existing.ID,
+			ActivityType: models.LeadActivityEmailAdded,
+			Title:        "Captured with another email address",
+			Metadata:     models.JSONMap{"email": capture.Email, "match": "name_and_company"},
+		}
+		if err := tx.Create(&activity).Error; err != nil {
+			return err
+		}
+	}
+
+	if rescored {
+		scored.LeadID = existing.ID
+		scored.Description = "Rescored on a repeat capture"
+		if err := tx.Create(scored).Error; err != nil {
+			return err
+		}
+	}
+
+	return recordTouchpoint(tx, existing, touchpoint)
+}
+
+// combineLeads computes the primary lead's column updates for merging a
+// duplicate into it, and the names of the columns that change
+func combineLeads(primary, duplicate *models.BlogLead) (map[string]interface{}, []string) {
+	updates := map[string]interface{}{}
+	for _, field := range leadProfileFields {
+		if current, other := *field.value(primary), *field.value(duplicate); current == "" && other != "" {
+			updates[field.column] = other
+		}
+	}
+
+	if tags, added := unionTags(primary.Tags, duplicate.Tags); added {
+		updates["tags"] = tags
+	}
+	if custom, added := fillCustomFields(primary.CustomFields, duplicate.CustomFields); added {
+		updates["custom_fields"] = custom
+	}
+
+	if duplicate.LeadScore > primary.LeadScore {
+		updates["lead_score"] = duplicate.LeadScore
+		updates["auto_qualification"] = duplicate.AutoQualification
+	}
+	if duplicate.TotalEngagements > 0 {
+		updates["total_engagements"] = primary.TotalEngagements + duplicate.TotalEngagements
+	}
+	if duplicate.AttributedRevenue > 0 {
+		updates["attributed_revenue"] = primary.AttributedRevenue + duplicate.AttributedRevenue
+	}
+	if duplicate.ConversionValue > 0 {
+		updates["conversion_value"] = primary.ConversionValue + duplicate.ConversionValue
+	}
+
+	// The merged lead's journey starts at the earliest capture
+	if duplicate.CapturedAt.Before(primary.CapturedAt) {
+		updates["captured_at"] = duplicate.CapturedAt
+	}
+	if len(duplicate.ConversionPath) > 0 {
+		path := append(models.JSONArray{}, primary.ConversionPath...)
+		if duplicate.CapturedAt.Before(primary.CapturedAt) {
+			path = append(append(models.JSONArray{}, duplicate.ConversionPath...), primary.ConversionPath...)
+		} else {
+			path = append(path, duplicate.ConversionPath...)
+		}
+		updates["conversion_path"] = trimConversionPath(path)
+	}
+	if laterTime(duplicate.LastEngagementAt, primary.LastEngagementAt) {
+		updates["last_engagement_at"] = duplicate.LastEngagementAt
+	}
+
+	if duplicate.ConsentGiven && !primary.ConsentGiven {
+		updates["consent_given"] = true
+		updates["consent_type"] = duplicate.ConsentType
+		updates["consent_timestamp"] = duplicate.ConsentTimestamp
+	}
+	// An opt-out on either record applies to the person
+	if duplicate.OptedOut && !primary.OptedOut {
+		updates["opted_out"] = true
+		updates["opt_out_timestamp"] = duplicate.OptOutTimestamp
+	}
+
+	changed := make([]string, 0, len(updates))
+	for column := range updates {
+		changed = append(changed, column)
+	}
+	sort.Strings(changed)
+
+	return updates, changed
+}
+
+// rejectMergedLead writes a conflict response for a lead that was merged away
+func rejectMergedLead(c *gin.Context, lead *models.BlogLead) bool {
+	if lead.MergedIntoID == nil {
+		return false
+	}
+	respondError(c, http.StatusConflict, "Lead was merged", "LEAD_MERGED",
+		fmt.Sprintf("Lead %d was merged into lead %d", lead.ID, *lead.MergedIntoID))
+	return true
+}
+
+// leadSnapshot serializes a lead for the merge audit record
+func leadSnapshot(lead *models.BlogLead) (models.JSONMap, error) {
+	data, err := json.Marshal(lead)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot models.JSONMap
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// unionTags appends tags missing from existing and reports whether any were added
+func unionTags(existing, incoming models.JSONArray) (models.JSONArray, bool) {
+	seen := make(map[string]bool, len(existing))
+	for _, tag := range existing {
+		seen[fmt.Sprint(tag)] = true
+	}
+
+	merged := append(models.JSONArray{}, existing...)
+	added := false
+	for _, tag := range incoming {
+		if key := fmt.Sprint(tag); !seen[key] {
+			seen[key] = true
+			merged = append(merged, tag)
+			added = true
+		}
+	}
+	return merged, added
+}
+
+// fillCustomFields adds incoming keys missing from existing and reports whether any were added
+func fillCustomFields(existing, incoming models.JSONMap) (models.JSONMap, bool) {
+	merged := make(models.JSONMap, len(existing)+len(incoming))
+	for key, value := range existing {
+		merged[key] = value
+	}
+
+	added := false
+	for key, value := range incoming {
+		if _, ok := merged[key]; !ok {
+			merged[key] = value
+			added = true
+		}
+	}
+	return merged, added
+}
+
+// alternateEmailsField is the custom field holding the other addresses a
+// fuzzy-matched lead was captured with
+const alternateEmailsField = "alternate_emails"
+
+// addAlternateEmail adds email to the alternate_emails custom field, reporting
+// false when it is already listed
+func addAlternateEmail(fields models.JSONMap, email string) (models.JSONMap, bool) {
+	emails, _ := fields[alternateEmailsField].([]interface{})
+	for _, listed := range emails {
+		if s, ok := listed.(string); ok && strings.EqualFold(s, email) {
+			return fields, false
+		}
+	}
+	if fields == nil {
+		fields = models.JSONMap{}
+	}
+	fields[alternateEmailsField] = append(append([]interface{}{}, emails...), email)
+	return fields, true
+}
+
+// laterTime reports whether a is set and after b
+func laterTime(a, b *time.Time) bool {
+	return a != nil && (b == nil || a.After(*b))
+}
@@ -0,0 +1,518 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// leadSortColumns whitelists the sort query parameter; values are column names
+var leadSortColumns = map[string]string{
+	"captured_at":  "captured_at",
+	"lead_score":   "lead_score",
+	"status":       "status",
+	"updated_at":   "updated_at",
+	"qualified_at": "qualified_at",
+	"email":        "email",
+	"name":         "name",
+	"company":      "company",
+}
+
+// leadQualifications are the manual qualification labels, matching the
+// labels of analytics.LeadScorer.AutoQualifyLead
+var leadQualifications = []string{"hot", "warm", "cold", "unqualified"}
+
+// ListLeads returns captured leads with filters, sorting and pagination
+func (h *LeadHandler) ListLeads(c *gin.Context) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	filters, ok := leadFiltersFromQuery(c)
+	if !ok {
+		return
+	}
+
+	// Duplicates merged into another lead are kept for audit only
+	query := applyLeadFilters(db.Model(&models.BlogLead{}).Where("merged_into_id IS NULL"), filters)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error("Failed to count leads", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve leads", "DATABASE_ERROR", "Unable to count leads")
+		return
+	}
+
+	var leads []models.BlogLead
+	if err := query.
+		Order(leadSortColumns[filters.Sort] + " " + strings.ToUpper(filters.Order)).
+		Order("id DESC").
+		Scopes(database.Paginate(filters.Page, filters.Limit)).
+		Find(&leads).Error; err != nil {
+		logger.Error("Failed to list leads", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve leads", "DATABASE_ERROR", "Unable to load leads")
+		return
+	}
+
+	responses := make([]models.BlogLeadResponse, 0, len(leads))
+	for i := range leads {
+		responses = append(responses, leadResponse(&leads[i]))
+	}
+
+	respondSuccess(c, http.StatusOK, "Leads retrieved successfully", models.PaginatedBlogLeadsResponse{
+		Leads:      responses,
+		Total:      total,
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		TotalPages: totalPages(total, filters.Limit),
+	})
+}
+
+// GetLead returns a lead with its activities and touchpoints
+func (h *LeadHandler) GetLead(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid lead ID", "INVALID_ID", "Lead ID must be a positive integer")
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	var lead models.BlogLead
+	err := db.
+		Preload("Activities", func(tx *gorm.DB) *gorm.DB { return tx.Order("created_at DESC, id DESC") }).
+		Preload("Touchpoints", func(tx *gorm.DB) *gorm.DB { return tx.Order("created_at ASC, id ASC") }).
+		First(&lead, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "Lead not found", "LEAD_NOT_FOUND", "Lead does not exist")
+			return
+		}
+		logger.Error("Failed to load lead", err, map[string]interface{}{"lead_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve lead", "DATABASE_ERROR", "Unable to load lead")
+		return
+	}
+
+	response := detailedLeadResponse(&lead)
+	if lead.ErasedAt == nil && lead.MergedIntoID == nil {
+		response.OptOutURL = leadOptOutURL(lead.ID)
+	}
+	respondSuccess(c, http.StatusOK, "Lead retrieved successfully", response)
+}
+
+// QualifyLead records a manual qualification (hot, warm, cold or unqualified).
+// Qualified labels move the lead to the qualified status and unqualified to
+// unqualified, through the lead pipeline rules.
+func (h *LeadHandler) QualifyLead(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid lead ID", "INVALID_ID", "Lead ID must be a positive integer")
+		return
+	}
+
+	var req models.LeadQualificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if !containsString(leadQualifications, req.Status) {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_QUALIFICATION", "status must be one of: "+strings.Join(leadQualifications, ", "))
+		return
+	}
+	if req.LeadScore != nil && (*req.LeadScore < 0 || *req.LeadScore > 100) {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_SCORE", "lead_score must be between 0 and 100")
+		return
+	}
+
+	user, ok := middleware.GetCurrentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required", "UNAUTHENTICATED", "User context not found")
+		return
+	}
+	req.QualifiedBy = user.ID
+	req.QualifiedAt = time.Now()
+
+	lead, found := h.findLead(c, id)
+	if !found || rejectMergedLead(c, lead) {
+		return
+	}
+
+	db := database.GetDB()
+	if req.AssignedTo != nil && !h.validateAssignee(c, db, *req.AssignedTo) {
+		return
+	}
+
+	status := workflow.LeadStatusQualified
+	if req.Status == "unqualified" {
+		status = workflow.LeadStatusUnqualified
+	}
+	if status != lead.Status {
+		if err := workflow.AuthorizeLead(user.Role, lead.Status, status); err != nil {
+			respondLeadTransitionError(c, user, lead, status, err)
+			return
+		}
+	}
+
+	updates := map[string]interface{}{
+		"manual_qualification": req.Status,
+		"qualified_by":         req.QualifiedBy,
+		"qualified_at":         req.QualifiedAt,
+	}
+	if req.QualificationNotes != "" {
+		updates["qualification_notes"] = req.QualificationNotes
+	}
+	if req.LeadScore != nil {
+		updates["lead_score"] = *req.LeadScore
+	}
+	if req.Tags != nil {
+		updates["tags"] = stringsToJSONArray(req.Tags)
+	}
+	if req.AssignedTo != nil {
+		updates["assigned_to"] = *req.AssignedTo
+	}
+
+	customFields := mergeCustomFields(lead.CustomFields, req.CustomFields)
+	if req.FollowUpDate != nil {
+		customFields = mergeCustomFields(customFields, map[string]interface{}{"follow_up_date": req.FollowUpDate.Format(time.RFC3339)})
+	}
+	if customFields != nil {
+		updates["custom_fields"] = customFields
+	}
+
+	previous := lead.Status
+	activity := models.LeadActivity{
+		LeadID:       lead.ID,
+		ActivityType: models.LeadActivityQualified,
+		Title:        fmt.Sprintf("Lead qualified as %s", req.Status),
+		Description:  req.QualificationNotes,
+		UserID:       &req.QualifiedBy,
+		Metadata: models.JSONMap{
+			"qualification":   req.Status,
+			"previous_status": previous,
+			"status":          status,
+			"lead_score":      req.LeadScore,
+			"assigned_to":     req.AssignedTo,
+			"follow_up_date":  req.FollowUpDate,
+		},
+	}
+
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if status != previous {
+			if err := applyLeadStatusChange(tx, lead, status, &req.QualifiedBy, req.QualificationNotes, updates); err != nil {
+				return err
+			}
+		} else if err := tx.Model(lead).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&activity).Error; err != nil {
+			return err
+		}
+
+		// Leads qualified without an owner are routed by the assignment rules
+		_, err := assignLead(tx, lead, assignmentTriggerQualification)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errLeadStatusChanged) {
+			respondError(c, http.StatusConflict, "Status transition not allowed", "STATUS_CHANGED", "Lead status was changed by another request, reload and retry")
+			return
+		}
+		logger.Error("Failed to qualify lead", err, map[string]interface{}{"lead_id": lead.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to qualify lead", "DATABASE_ERROR", "Unable to update lead")
+		return
+	}
+
+	logger.LogBusinessEvent("lead_qualified", "lead", lead.ID, map[string]interface{}{
+		"user_id":       req.QualifiedBy,
+		"qualification": req.Status,
+		"status":        status,
+		"assigned_to":   lead.AssignedTo,
+	})
+
+	respondSuccess(c, http.StatusOK, "Lead qualified successfully", leadResponse(lead))
+}
+
+// findLead loads a lead and writes the error response when it cannot
+func (h *LeadHandler) findLead(c *gin.Context, id uint) (*models.BlogLead, bool) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return nil, false
+	}
+
+	var lead models.BlogLead
+	if err := db.First(&lead, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "Lead not found", "LEAD_NOT_FOUND", "Lead does not exist")
+			return nil, false
+		}
+		logger.Error("Failed to load lead", err, map[string]interface{}{"lead_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve lead", "DATABASE_ERROR", "Unable to load lead")
+		return nil, false
+	}
+
+	return &lead, true
+}
+
+// validateAssignee checks that a lead can be assigned to an active admin user
+// and writes the error response when it cannot
+func (h *LeadHandler) validateAssignee(c *gin.Context, db *gorm.DB, userID uint) bool {
+	var count int64
+	if err := db.Model(&models.AdminUser{}).Where("id = ? AND active = ?", userID, true).Count(&count).Error; err != nil {
+		logger.Error("Failed to check assignee", err, map[string]interface{}{"user_id": userID})
+		respondError(c, http.StatusInternalServerError, "Failed to validate assignee", "DATABASE_ERROR", "Unable to load user")
+		return false
+	}
+	if count == 0 {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_ASSIGNEE", "assigned_to must be an active user")
+		return false
+	}
+	return true
+}
+
+// leadFiltersFromQuery parses and validates lead list query parameters.
+// Dates accept RFC 3339 or YYYY-MM-DD; a date-only end_date includes that whole day.
+func leadFiltersFromQuery(c *gin.Context) (models.BlogLeadFilters, bool) {
+	filters := models.BlogLeadFilters{
+		Status:     c.Query("status"),
+		SourceType: c.Query("source_type"),
+		AssignedTo: parseUintQuery(c, "assigned_to"),
+		Sort:       c.DefaultQuery("sort", "captured_at"),
+		Order:      strings.ToLower(c.DefaultQuery("order", "desc")),
+		Page:       parseIntQuery(c, "page", 1),
+		Limit:      parseIntQuery(c, "limit", 20),
+
+		Quarantined: c.Query("quarantined") == "true",
+	}
+	filters.Page, filters.Limit = normalizePagination(filters.Page, filters.Limit)
+
+	if blogID := parseUintQuery(c, "blog_id"); blogID != nil {
+		filters.BlogID = *blogID
+	}
+
+	if filters.Status != "" && !workflow.IsValidLeadStatus(filters.Status) {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_STATUS", "Unknown lead status: "+filters.Status)
+		return filters, false
+	}
+
+	if _, ok := leadSortColumns[filters.Sort]; !ok {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_SORT", "Unsupported sort field: "+filters.Sort)
+		return filters, false
+	}
+	if filters.Order != "asc" && filters.Order != "desc" {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_ORDER", "order must be asc or desc")
+		return filters, false
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target **int
+	}{
+		{"min_score", &filters.MinScore},
+		{"max_score", &filters.MaxScore},
+	} {
+		if c.Query(bound.name) == "" {
+			continue
+		}
+		score := parseIntQuery(c, bound.name, -1)
+		if score < 0 || score > 100 {
+			respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_SCORE", bound.name+" must be between 0 and 100")
+			return filters, false
+		}
+		*bound.target = &score
+	}
+
+	var ok bool
+	if filters.StartDate, ok = parseDateQuery(c, "start_date", false); !ok {
+		return filters, false
+	}
+	if filters.EndDate, ok = parseDateQuery(c, "end_date", true); !ok {
+		return filters, false
+	}
+
+	if tags := c.Query("tags"); tags != "" {
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filters.Tags = append(filters.Tags, tag)
+			}
+		}
+	}
+
+	return filters, true
+}
+
+// applyLeadFilters applies list filters to a lead query
+func applyLeadFilters(query *gorm.DB, filters models.BlogLeadFilters) *gorm.DB {
+	// Leads held for missing consent are only listed on request
+	if filters.Quarantined {
+		query = query.Where("quarantined_at IS NOT NULL")
+	} else {
+		query = query.Where("quarantined_at IS NULL")
+	}
+	if filters.BlogID != 0 {
+		query = query.Where("blog_id = ?", filters.BlogID)
+	}
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.SourceType != "" {
+		query = query.Where("source_type = ?", filters.SourceType)
+	}
+	if filters.AssignedTo != nil {
+		// assigned_to=0 lists unassigned leads
+		if *filters.AssignedTo == 0 {
+			query = query.Where("assigned_to IS NULL")
+		} else {
+			query = query.Where("assigned_to = ?", *filters.AssignedTo)
+		}
+	}
+	if filters.StartDate != nil {
+		query = query.Where("captured_at >= ?", *filters.StartDate)
+	}
+	if filters.EndDate != nil {
+		query = query.Where("captured_at <= ?", *filters.EndDate)
+	}
+	if filters.MinScore != nil {
+		query = query.Where("lead_score >= ?", *filters.MinScore)
+	}
+	if filters.MaxScore != nil {
+		query = query.Where("lead_score <= ?", *filters.MaxScore)
+	}
+	if len(filters.Tags) > 0 {
+		// Leads carrying any of the tags
+		conditions := make([]string, 0, len(filters.Tags))
+		args := make([]interface{}, 0, len(filters.Tags))
+		for _, tag := range filters.Tags {
+			conditions = append(conditions, "JSON_CONTAINS(tags, JSON_QUOTE(?))")
+			args = append(args, tag)
+		}
+		query = query.Where(strings.Join(conditions, " OR "), args...)
+	}
+	return query
+}
+
+// parseDateQuery parses an optional date query parameter and writes the error
+// response when it is malformed. endOfDay moves date-only values to the last
+// instant of that day.
+func parseDateQuery(c *gin.Context, name string, endOfDay bool) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, true
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid query parameters", "INVALID_DATE", name+" must be RFC 3339 or YYYY-MM-DD")
+		return nil, false
+	}
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &parsed, true
+}
+
+// detailedLeadResponse converts a lead with its activities and touchpoints into the detail response
+func detailedLeadResponse(lead *models.BlogLead) models.DetailedBlogLeadResponse {
+	conversionPath := make([]string, 0, len(lead.ConversionPath))
+	for _, step := range lead.ConversionPath {
+		conversionPath = append(conversionPath, fmt.Sprint(step))
+	}
+
+	activities := lead.Activities
+	if activities == nil {
+		activities = []models.LeadActivity{}
+	}
+	touchpoints := lead.Touchpoints
+	if touchpoints == nil {
+		touchpoints = []models.LeadTouchpoint{}
+	}
+
+	return models.DetailedBlogLeadResponse{
+		BlogLeadResponse:  leadResponse(lead),
+		Activities:        activities,
+		Touchpoints:       touchpoints,
+		ConversionPath:    conversionPath,
+		AttributedRevenue: lead.AttributedRevenue,
+		LastEngagementAt:  lead.LastEngagementAt,
+		TotalEngagements:  lead.TotalEngagements,
+		DeviceInfo: map[string]interface{}{
+			"device_type":      lead.DeviceType,
+			"browser":          lead.Browser,
+			"operating_system": lead.OperatingSystem,
+			"ip_address":       lead.IPAddress,
+		},
+		LocationInfo: map[string]interface{}{
+			"country":  lead.Country,
+			"region":   lead.Region,
+			"city":     lead.City,
+			"timezone": lead.Timezone,
+		},
+		ReferrerInfo: map[string]interface{}{
+			"traffic_source":  lead.TrafficSource,
+			"referrer_url":    lead.ReferrerURL,
+			"referrer_domain": lead.ReferrerDomain,
+			"landing_page":    lead.LandingPage,
+			"utm_term":        lead.UTMTerm,
+			"utm_content":     lead.UTMContent,
+		},
+	}
+}
+
+// mergeCustomFields overlays updates onto existing custom fields. It returns
+// nil when there is nothing to store.
+func mergeCustomFields(existing models.JSONMap, updates map[string]interface{}) models.JSONMap {
+	if len(updates) == 0 {
+		if len(existing) == 0 {
+			return nil
+		}
+		return existing
+	}
+
+	merged := make(models.JSONMap, len(existing)+len(updates))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range updates {
+		merged[key] = value
+	}
+	return merged
+}
+
+// stringsToJSONArray converts strings to a JSON array column value
+func stringsToJSONArray(values []string) models.JSONArray {
+	array := make(models.JSONArray, 0, len(values))
+	for _, value := range values {
+		array = append(array, value)
+	}
+	return array
+}
+
+// containsString reports whether values contains target
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,620 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/leadmatch"
+	"blog-service/pkg/leadtoken"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/mailer"
+	"blog-service/pkg/privacy"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// erasedVisitorID replaces the visitor ID of events stitched into an erased lead
+const erasedVisitorID = "erased"
+
+// OptOut records an opt-out from the signed link in emails to a lead. It is
+// public. GET only shows an unsubscribe button, since mail scanners follow
+// links; POST opts out and also serves one-click unsubscribe (RFC 8058).
+// Every lead of the person, matched by normalized email, is opted out.
+func (h *LeadHandler) OptOut(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", "token is required")
+		return
+	}
+	id, err := leadtoken.Verify(leadtoken.PurposeOptOut, token, leadTokenSecret(), time.Now())
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid opt-out link", "INVALID_OPT_OUT_TOKEN", err.Error())
+		return
+	}
+
+	if c.Request.Method == http.MethodGet {
+		c.Header("Cache-Control", "no-store")
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(fmt.Sprintf(optOutConfirmationPage, html.EscapeString(url.QueryEscape(token)))))
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	leads, err := loadSurvivingLeads(db, []uint{id})
+	if err != nil {
+		logger.Error("Failed to load lead for opt-out", err, map[string]interface{}{"lead_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to opt out", "DATABASE_ERROR", "Unable to load lead")
+		return
+	}
+	lead := leads[id]
+	if lead == nil {
+		respondError(c, http.StatusNotFound, "Lead not found", "LEAD_NOT_FOUND", "Lead does not exist")
+		return
+	}
+
+	now := time.Now()
+	var optedOut []uint
+	err = database.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.BlogLead{}).Where("merged_into_id IS NULL AND opted_out = ?", false)
+		if lead.NormalizedEmail != "" {
+			query = query.Where("id = ? OR normalized_email = ?", lead.ID, lead.NormalizedEmail)
+		} else {
+			query = query.Where("id = ?", lead.ID)
+		}
+		if err := query.Pluck("id", &optedOut).Error; err != nil {
+			return err
+		}
+		if len(optedOut) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.BlogLead{}).Where("id IN ?", optedOut).Updates(map[string]interface{}{
+			"opted_out":              true,
+			"opt_out_timestamp":      now,
+			"reconsent_requested_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		activities := make([]models.LeadActivity, 0, len(optedOut))
+		for _, leadID := range optedOut {
+			activities = append(activities, models.LeadActivity{
+				LeadID:       leadID,
+				ActivityType: models.LeadActivityOptedOut,
+				Title:        "Opted out via unsubscribe link",
+				Metadata:     models.JSONMap{"source": "opt_out_link"},
+			})
+		}
+		return tx.Create(&activities).Error
+	})
+	if err != nil {
+		logger.Error("Failed to record opt-out", err, map[string]interface{}{"lead_id": lead.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to opt out", "DATABASE_ERROR", "Unable to update lead")
+		return
+	}
+
+	if len(optedOut) > 0 {
+		logger.LogBusinessEvent("lead_opted_out", "lead", lead.ID, map[string]interface{}{
+			"leads":  optedOut,
+			"source": "opt_out_link",
+		})
+	}
+
+	respondSuccess(c, http.StatusOK, "You have been unsubscribed", map[string]interface{}{
+		"opted_out": true,
+	})
+}
+
+// ConfirmConsent lifts an opt-out or quarantine through the signed link
+// emailed to the lead when a capture with consent matched it. It is public.
+// GET only shows a confirmation button, since mail scanners follow links;
+// POST confirms. Every lead of the person, matched by normalized email, gets
+// consent recorded and tracking resumed.
+func (h *LeadHandler) ConfirmConsent(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", "token is required")
+		return
+	}
+	id, err := leadtoken.Verify(leadtoken.PurposeConsent, token, leadTokenSecret(), time.Now())
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid confirmation link", "INVALID_CONSENT_TOKEN", err.Error())
+		return
+	}
+
+	if c.Request.Method == http.MethodGet {
+		c.Header("Cache-Control", "no-store")
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(fmt.Sprintf(consentConfirmationPage, html.EscapeString(url.QueryEscape(token)))))
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	leads, err := loadSurvivingLeads(db, []uint{id})
+	if err != nil {
+		logger.Error("Failed to load lead for consent confirmation", err, map[string]interface{}{"lead_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to confirm consent", "DATABASE_ERROR", "Unable to load lead")
+		return
+	}
+	lead := leads[id]
+	if lead == nil {
+		respondError(c, http.StatusNotFound, "Lead not found", "LEAD_NOT_FOUND", "Lead does not exist")
+		return
+	}
+
+	now := time.Now()
+	var confirmed []uint
+	err = database.Transaction(func(tx *gorm.DB) error {
+		// A link is used once, and only while its request is pending
+		claimed := tx.Model(&models.BlogLead{}).
+			Where("id = ? AND reconsent_requested_at IS NOT NULL", lead.ID).
+			Update("reconsent_requested_at", nil)
+		if claimed.Error != nil {
+			return claimed.Error
+		}
+		if claimed.RowsAffected == 0 {
+			return errNoConsentPending
+		}
+
+		query := tx.Model(&models.BlogLead{}).Where("merged_into_id IS NULL AND (opted_out = ? OR quarantined_at IS NOT NULL)", true)
+		if lead.NormalizedEmail != "" {
+			query = query.Where("id = ? OR normalized_email = ?", lead.ID, lead.NormalizedEmail)
+		} else {
+			query = query.Where("id = ?", lead.ID)
+		}
+		if err := query.Pluck("id", &confirmed).Error; err != nil {
+			return err
+		}
+		if len(confirmed) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.BlogLead{}).Where("id IN ?", confirmed).Updates(map[string]interface{}{
+			"consent_given":     true,
+			"consent_timestamp": now,
+			"opted_out":         false,
+			"opt_out_timestamp": nil,
+			"quarantined_at":    nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		activities := make([]models.LeadActivity, 0, len(confirmed))
+		for _, leadID := range confirmed {
+			activities = append(activities, models.LeadActivity{
+				LeadID:       leadID,
+				ActivityType: models.LeadActivityReconsented,
+				Title:        "Consent confirmed via emailed link",
+				Metadata:     models.JSONMap{"source": "consent_link"},
+			})
+		}
+		if err := tx.Create(&activities).Error; err != nil {
+			return err
+		}
+
+		// Released leads are routed now, as they would have been at capture
+		released := []models.BlogLead{}
+		if err := tx.Where("id IN ?", confirmed).Order("id ASC").Find(&released).Error; err != nil {
+			return err
+		}
+		for i := range released {
+			if _, err := assignLead(tx, &released[i], assignmentTriggerCapture); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errNoConsentPending) {
+		respondError(c, http.StatusConflict, "Confirmation link already used", "CONSENT_NOT_PENDING", "No consent confirmation is pending for this lead")
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to confirm consent", err, map[string]interface{}{"lead_id": lead.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to confirm consent", "DATABASE_ERROR", "Unable to update lead")
+		return
+	}
+
+	if len(confirmed) > 0 {
+		logger.LogBusinessEvent("lead_reconsented", "lead", lead.ID, map[string]interface{}{
+			"leads":  confirmed,
+			"source": "consent_link",
+		})
+	}
+
+	respondSuccess(c, http.StatusOK, "Your consent has been confirmed", map[string]interface{}{
+		"consent_given": true,
+	})
+}
+
+// ExportLeadData returns everything stored about a data subject: their leads
+// with activities and touchpoints, stitched visitor events and merge records
+func (h *LeadHandler) ExportLeadData(c *gin.Context) {
+	var req models.LeadDataSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	user, ok := middleware.GetCurrentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required", "UNAUTHENTICATED", "User context not found")
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	ids, email, ok := findDataSubjectLeads(c, db, &req)
+	if !ok {
+		return
+	}
+
+	export := models.LeadDataExport{
+		Email:         email,
+		ExportedAt:    time.Now(),
+		Leads:         []models.BlogLead{},
+		VisitorEvents: []models.VisitorEvent{},
+		Merges:        []models.LeadMerge{},
+	}
+	err := db.
+		Preload("Activities", func(tx *gorm.DB) *gorm.DB { return tx.Order("created_at ASC, id ASC") }).
+		Preload("Touchpoints", func(tx *gorm.DB) *gorm.DB { return tx.Order("created_at ASC, id ASC") }).
+		Where("id IN ?", ids).Order("id ASC").Find(&export.Leads).Error
+	if err == nil {
+		err = db.Where("lead_id IN ?", ids).Order("created_at ASC, id ASC").Find(&export.VisitorEvents).Error
+	}
+	if err == nil {
+		err = db.Where("primary_lead_id IN ? OR merged_lead_id IN ?", ids, ids).Order("id ASC").Find(&export.Merges).Error
+	}
+	if err != nil {
+		logger.Error("Failed to export lead data", err, map[string]interface{}{"lead_ids": ids})
+		respondError(c, http.StatusInternalServerError, "Failed to export lead data", "DATABASE_ERROR", "Unable to load lead data")
+		return
+	}
+
+	logger.LogBusinessEvent("lead_data_exported", "lead", nil, map[string]interface{}{
+		"lead_ids":     ids,
+		"requested_by": user.ID,
+	})
+
+	respondSuccess(c, http.StatusOK, "Lead data exported successfully", export)
+}
+
+// EraseLeadData anonymizes a data subject's personal data across their leads,
+// activities, touchpoints, stitched visitor events and merge records. Leads are
+// kept with their status, scores, revenue, blog and campaign fields, so
+// aggregate analytics do not change. Erased leads are opted out.
+func (h *LeadHandler) EraseLeadData(c *gin.Context) {
+	var req models.LeadDataSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if !req.Confirm {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "CONFIRMATION_REQUIRED", "confirm must be true; erasure cannot be undone")
+		return
+	}
+
+	user, ok := middleware.GetCurrentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required", "UNAUTHENTICATED", "User context not found")
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	ids, _, ok := findDataSubjectLeads(c, db, &req)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	err := database.Transaction(func(tx *gorm.DB) error {
+		leads := []models.BlogLead{}
+		if err := tx.Where("id IN ?", ids).Find(&leads).Error; err != nil {
+			return err
+		}
+		for i := range leads {
+			if err := eraseLead(tx, &leads[i], user.ID, req.Reason, now); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.VisitorEvent{}).Where("lead_id IN ?", ids).Updates(map[string]interface{}{
+			"visitor_id": erasedVisitorID,
+			"referrer":   "",
+			"url":        gorm.Expr("SUBSTRING_INDEX(SUBSTRING_INDEX(url, '#', 1), '?', 1)"),
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.LeadMerge{}).Where("primary_lead_id IN ? OR merged_lead_id IN ?", ids, ids).
+			Updates(map[string]interface{}{
+				"merged_lead": models.JSONMap{"erased": true},
+				"notes":       "",
+			}).Error
+	})
+	if err != nil {
+		logger.Error("Failed to erase lead data", err, map[string]interface{}{"lead_ids": ids})
+		respondError(c, http.StatusInternalServerError, "Failed to erase lead data", "DATABASE_ERROR", "Unable to anonymize lead data")
+		return
+	}
+
+	logger.LogBusinessEvent("lead_data_erased", "lead", nil, map[string]interface{}{
+		"lead_ids":     ids,
+		"requested_by": user.ID,
+	})
+
+	respondSuccess(c, http.StatusOK, "Lead data erased successfully", map[string]interface{}{
+		"leads_erased": len(ids),
+		"lead_ids":     ids,
+	})
+}
+
+// findDataSubjectLeads returns the IDs of every lead of the person in a data
+// subject request: leads with the same normalized email and the duplicates
+// merged into them. It writes the error response when it cannot.
+func findDataSubjectLeads(c *gin.Context, db *gorm.DB, req *models.LeadDataSubjectRequest) ([]uint, string, bool) {
+	if (req.Email == "") == (req.LeadID == 0) {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", "send either email or lead_id")
+		return nil, "", false
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	normalized := leadmatch.NormalizeEmail(req.Email)
+	if req.LeadID != 0 {
+		var lead models.BlogLead
+		if err := db.Select("id", "email", "normalized_email").First(&lead, req.LeadID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				respondError(c, http.StatusNotFound, "Lead not found", "LEAD_NOT_FOUND", "Lead does not exist")
+				return nil, "", false
+			}
+			logger.Error("Failed to load lead", err, map[string]interface{}{"lead_id": req.LeadID})
+			respondError(c, http.StatusInternalServerError, "Failed to find lead data", "DATABASE_ERROR", "Unable to load lead")
+			return nil, "", false
+		}
+		email, normalized = lead.Email, lead.NormalizedEmail
+	}
+
+	// Fuzzy-matched captures keep other addresses of the person on the lead
+	query := db.Model(&models.BlogLead{}).Where("email = ?", email).
+		Or("JSON_CONTAINS(JSON_EXTRACT(custom_fields, ?), JSON_QUOTE(?))", "$."+alternateEmailsField, email)
+	if normalized != "" {
+		query = query.Or("normalized_email = ?", normalized)
+	}
+	if req.LeadID != 0 {
+		query = query.Or("id = ?", req.LeadID)
+	}
+	var ids []uint
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		logger.Error("Failed to find data subject leads", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to find lead data", "DATABASE_ERROR", "Unable to load leads")
+		return nil, "", false
+	}
+
+	// Duplicates merged into the person's leads may carry another address
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	pending := ids
+	for hop := 0; hop < maxMergeHops && len(pending) > 0; hop++ {
+		var merged []uint
+		if err := db.Model(&models.BlogLead{}).Where("merged_into_id IN ?", pending).Pluck("id", &merged).Error; err != nil {
+			logger.Error("Failed to find merged leads", err, nil)
+			respondError(c, http.StatusInternalServerError, "Failed to find lead data", "DATABASE_ERROR", "Unable to load leads")
+			return nil, "", false
+		}
+		pending = nil
+		for _, id := range merged {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+				pending = append(pending, id)
+			}
+		}
+	}
+
+	if len(ids) == 0 {
+		respondError(c, http.StatusNotFound, "Lead not found", "LEAD_NOT_FOUND", "No leads match the request")
+		return nil, "", false
+	}
+	return ids, email, true
+}
+
+// eraseLead anonymizes one lead and its activities and touchpoints, and
+// records the erasure on it
+func eraseLead(tx *gorm.DB, lead *models.BlogLead, userID uint, reason string, now time.Time) error {
+	personal := []string{
+		lead.Email, lead.NormalizedEmail, lead.Name, lead.FirstName, lead.LastName,
+		lead.Company, lead.Phone, lead.Website, lead.LinkedInProfile,
+	}
+	if emails, ok := lead.CustomFields[alternateEmailsField].([]interface{}); ok {
+		for _, email := range emails {
+			personal = append(personal, fmt.Sprint(email))
+		}
+	}
+
+	path := make(models.JSONArray, 0, len(lead.ConversionPath))
+	for _, step := range lead.ConversionPath {
+		value := fmt.Sprint(step)
+		if kind, stepURL, found := strings.Cut(value, ":"); found {
+			value = kind + ":" + privacy.StripQuery(stepURL)
+		}
+		path = append(path, value)
+	}
+
+	updates := map[string]interface{}{
+		"email":               privacy.ErasedEmail(lead.ID),
+		"normalized_email":    "",
+		"name":                "",
+		"first_name":          "",
+		"last_name":           "",
+		"company":             "",
+		"job_title":           "",
+		"phone":               "",
+		"website":             "",
+		"linkedin_profile":    "",
+		"ip_address":          "",
+		"region":              "",
+		"city":                "",
+		"source_details":      nil,
+		"custom_fields":       nil,
+		"tags":                nil,
+		"notes":               "",
+		"qualification_notes": "",
+		"referrer_url":        "",
+		"landing_page":        privacy.StripQuery(lead.LandingPage),
+		"conversion_path":     path,
+		"opted_out":           true,
+		"erased_at":           now,
+	}
+	if !lead.OptedOut {
+		updates["opt_out_timestamp"] = now
+	}
+	if err := tx.Model(&models.BlogLead{}).Where("id = ?", lead.ID).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	activities := []models.LeadActivity{}
+	if err := tx.Where("lead_id = ?", lead.ID).Find(&activities).Error; err != nil {
+		return err
+	}
+	for _, activity := range activities {
+		if err := tx.Model(&models.LeadActivity{}).Where("id = ?", activity.ID).Updates(map[string]interface{}{
+			"title":       privacy.Redact(activity.Title, personal),
+			"description": "",
+			"metadata":    models.JSONMap(privacy.RedactMap(activity.Metadata, personal)),
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(&models.LeadTouchpoint{}).Where("lead_id = ?", lead.ID).
+		Update("url", gorm.Expr("SUBSTRING_INDEX(SUBSTRING_INDEX(url, '#', 1), '?', 1)")).Error; err != nil {
+		return err
+	}
+
+	activity := models.LeadActivity{
+		LeadID:       lead.ID,
+		ActivityType: models.LeadActivityErased,
+		Title:        "Personal data erased",
+		Description:  privacy.Redact(reason, personal),
+		UserID:       &userID,
+	}
+	return tx.Create(&activity).Error
+}
+
+// consentPolicy returns the capture consent policy from LEAD_CONSENT_MODE and
+// LEAD_CONSENT_REQUIRED_TYPES
+func consentPolicy() privacy.Policy {
+	return privacy.NewPolicy(
+		getEnv("LEAD_CONSENT_MODE", privacy.ModeQuarantine),
+		strings.Split(getEnv("LEAD_CONSENT_REQUIRED_TYPES", privacy.ConsentGDPR), ","),
+	)
+}
+
+// leadOptOutURL returns the signed opt-out link for a lead, valid for
+// LEAD_OPT_OUT_TTL_DAYS
+func leadOptOutURL(leadID uint) string {
+	days, err := strconv.Atoi(getEnv("LEAD_OPT_OUT_TTL_DAYS", "3650"))
+	if err != nil || days <= 0 {
+		days = 3650
+	}
+	token := leadtoken.Sign(leadtoken.PurposeOptOut, leadID, time.Now().AddDate(0, 0, days), leadTokenSecret())
+	return strings.TrimRight(getEnv("APP_URL", "https://blog-api.mejona.com"), "/") + "/api/v1/privacy/opt-out?token=" + url.QueryEscape(token)
+}
+
+// consentRequestInterval is the least time between consent confirmation
+// emails to one lead, so captures cannot flood an inbox
+const consentRequestInterval = 24 * time.Hour
+
+// errNoConsentPending aborts a consent confirmation whose request was already used
+var errNoConsentPending = errors.New("no consent confirmation pending")
+
+// optOutConfirmationPage is the page behind the unsubscribe link. The button
+// posts the token back; %s is the escaped token.
+const optOutConfirmationPage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Unsubscribe</title></head>
+<body>
+<p>Unsubscribe this email address from all updates?</p>
+<form method="post" action="/api/v1/privacy/opt-out?token=%s"><button type="submit">Unsubscribe</button></form>
+</body></html>`
+
+// consentConfirmationPage is the page behind the emailed link. The button
+// posts the token back; %s is the escaped token.
+const consentConfirmationPage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Confirm your consent</title></head>
+<body>
+<p>Someone submitted a form with your email address and agreed to be contacted. Confirm to resume receiving updates.</p>
+<form method="post" action="/api/v1/privacy/confirm-consent?token=%s"><button type="submit">Confirm</button></form>
+<p>If this was not you, ignore this page; nothing changes.</p>
+</body></html>`
+
+// requestConsentConfirmation marks that a consent confirmation link is to be
+// sent to an opted-out or quarantined lead, and reports false when one was
+// sent within consentRequestInterval
+func requestConsentConfirmation(tx *gorm.DB, lead *models.BlogLead, now time.Time) (bool, error) {
+	if lead.ReconsentRequestedAt != nil && now.Sub(*lead.ReconsentRequestedAt) < consentRequestInterval {
+		return false, nil
+	}
+	if err := tx.Model(&models.BlogLead{}).Where("id = ?", lead.ID).
+		Update("reconsent_requested_at", now).Error; err != nil {
+		return false, err
+	}
+	lead.ReconsentRequestedAt = &now
+	return true, nil
+}
+
+// sendConsentConfirmation emails a lead the signed link confirming consent.
+// It runs after the capture is stored, so failures are only logged.
+func sendConsentConfirmation(lead models.BlogLead) {
+	body := "Someone submitted a form on our blog with this email address and agreed to be contacted.\n\n" +
+		"You previously unsubscribed or did not give consent, so nothing changes unless you confirm here:\n\n" +
+		leadConsentURL(lead.ID) + "\n\n" +
+		"If this was not you, ignore this email."
+	err := mailer.FromEnv().Send(lead.Email, "Confirm your subscription", body)
+	switch {
+	case errors.Is(err, mailer.ErrNotConfigured):
+		logger.Warn("SMTP not configured, consent confirmation not sent", map[string]interface{}{"lead_id": lead.ID})
+	case err != nil:
+		logger.Error("Failed to send consent confirmation", err, map[string]interface{}{"lead_id": lead.ID})
+	default:
+		logger.LogBusinessEvent("lead_consent_confirmation_sent", "lead", lead.ID, nil)
+	}
+}
+
+// leadConsentURL returns the signed consent confirmation link for a lead,
+// valid for LEAD_CONSENT_TTL_HOURS
+func leadConsentURL(leadID uint) string {
+	hours, err := strconv.Atoi(getEnv("LEAD_CONSENT_TTL_HOURS", "72"))
+	if err != nil || hours <= 0 {
+		hours = 72
+	}
+	token := leadtoken.Sign(leadtoken.PurposeConsent, leadID, time.Now().Add(time.Duration(hours)*time.Hour), leadTokenSecret())
+	return strings.TrimRight(getEnv("APP_URL", "https://blog-api.mejona.com"), "/") + "/api/v1/privacy/confirm-consent?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/analytics"
+	"fmt"
+	"strings"
+)
+
+// leadScorer is shared by all handlers; it holds no state
+var leadScorer = analytics.NewLeadScorer()
+
+// scoreLead scores a newly captured lead, sets its score and auto-qualification
+// and returns the "scored" activity explaining the result. The activity's
+// LeadID is filled by the caller once the lead is stored.
+func scoreLead(lead *models.BlogLead, req *models.BlogLeadCaptureRequest) models.LeadActivity {
+	profile := leadProfile(lead, req)
+	breakdown := leadScorer.ScoreBreakdown(profile)
+
+	lead.LeadScore = breakdown.Total
+	lead.AutoQualification = leadScorer.AutoQualifyLead(breakdown.Total, profile)
+
+	return models.LeadActivity{
+		ActivityType: models.LeadActivityScored,
+		Title:        fmt.Sprintf("Lead scored %d (%s)", lead.LeadScore, lead.AutoQualification),
+		Description:  "Scored automatically at capture",
+		Metadata: models.JSONMap{
+			"score":              breakdown.Total,
+			"auto_qualification": lead.AutoQualification,
+			"breakdown":          breakdown,
+			"weights":            analytics.LeadScoreWeights(),
+		},
+	}
+}
+
+// leadProfile builds the scoring profile of a captured lead. Details the lead
+// columns do not keep (company size, CTA clicks, pages visited, ...) are read
+// from the request's custom fields and engagement data.
+func leadProfile(lead *models.BlogLead, req *models.BlogLeadCaptureRequest) analytics.LeadProfile {
+	custom := req.CustomFields
+	engagement := req.EngagementData
+
+	industry := mapString(custom, "industry")
+
+	// The capture itself is a page view, a visit, a post read and a form completion
+	visits := lead.PreviousVisits + 1
+	pageViews := max(lead.PageViewsBeforeCapture, 1)
+	formCompletions := max(int(mapFloat(engagement, "form_completions")), 1)
+
+	downloads := int(mapFloat(engagement, "downloads"))
+	if lead.SourceType == "download" {
+		downloads = max(downloads, 1)
+	}
+
+	return analytics.LeadProfile{
+		Demographics: analytics.Demographics{
+			JobTitle:        lead.JobTitle,
+			Industry:        industry,
+			Location:        strings.TrimSpace(strings.Join([]string{lead.City, lead.Region, lead.Country}, " ")),
+			ExperienceLevel: mapString(custom, "experience_level", "seniority"),
+		},
+		Behavior: analytics.Behavior{
+			PageViews:           pageViews,
+			TotalTimeOnSite:     lead.TimeOnSiteBeforeCapture,
+			VisitCount:          visits,
+			BlogPostsRead:       max(int(mapFloat(engagement, "blog_posts_read")), 1),
+			Downloads:           downloads,
+			VideoWatchTime:      int(mapFloat(engagement, "video_watch_time")),
+			SocialEngagements:   int(mapFloat(engagement, "social_engagements")),
+			ServicePagesVisited: mapBool(engagement, "service_pages_visited"),
+			PricingPagesVisited: mapBool(engagement, "pricing_pages_visited"),
+			ContactPagesVisited: mapBool(engagement, "contact_pages_visited"),
+			SearchQueries:       int(mapFloat(engagement, "search_queries")),
+			LastActivity:        lead.CapturedAt,
+		},
+		Company: analytics.Company{
+			Name:            lead.Company,
+			Size:            mapString(custom, "company_size"),
+			Industry:        industry,
+			Revenue:         mapString(custom, "company_revenue", "annual_revenue"),
+			TechnologyStack: mapStrings(custom, "technology_stack"),
+		},
+		Intent: analytics.Intent{
+			SourceType:      lead.SourceType,
+			ContentTypes:    append([]string{"blog"}, mapStrings(engagement, "content_types")...),
+			CTAInteractions: int(mapFloat(engagement, "cta_interactions", "cta_clicks")),
+			FormCompletions: formCompletions,
+		},
+	}
+}
+
+// mapBool reports whether key holds true
+func mapBool(values map[string]interface{}, key string) bool {
+	value, ok := values[key].(bool)
+	return ok && value
+}
+
+// mapStrings returns the non-empty strings of a JSON array value
+func mapStrings(values map[string]interface{}, key string) []string {
+	items, ok := values[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+			result = append(result, strings.TrimSpace(s))
+		}
+	}
+	return result
+}
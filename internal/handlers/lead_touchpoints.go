@@ -0,0 +1,437 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/auth"
+	"blog-service/pkg/database"
+	"blog-service/pkg/leadtoken"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// captureTouchpoint builds the touchpoint recorded when a lead form is submitted on a blog post
+func captureTouchpoint(req *models.BlogLeadCaptureRequest, blog *models.Blog, lead *models.BlogLead) models.LeadTouchpoint {
+	source := req.UTMSource
+	if source == "" {
+		source = req.TrafficSource
+	}
+	if source == "" {
+		source = lead.ReferrerDomain
+	}
+
+	blogID := blog.ID
+	return models.LeadTouchpoint{
+		TouchpointType: models.TouchpointLeadCapture,
+		BlogID:         &blogID,
+		URL:            lead.BlogURL,
+		Title:          truncateString(blog.Title, 500),
+		Source:         truncateString(source, 100),
+		Medium:         req.UTMMedium,
+		Campaign:       req.UTMCampaign,
+		TimeSpent:      lead.TimeOnSiteBeforeCapture,
+		ScrollDepth:    lead.ScrollDepthAtCapture,
+		Interactions:   int(mapFloat(req.EngagementData, "cta_interactions", "cta_clicks")),
+	}
+}
+
+// recordTouchpoint stores a touchpoint for a lead and updates the lead's
+// engagement count, last engagement time and conversion path. The lead's
+// engagement is re-read under a row lock first, so concurrent touchpoints and
+// captures each append to the current path.
+func recordTouchpoint(tx *gorm.DB, lead *models.BlogLead, touchpoint *models.LeadTouchpoint) error {
+	var current models.BlogLead
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "total_engagements", "last_engagement_at", "conversion_path").
+		First(&current, lead.ID).Error; err != nil {
+		return err
+	}
+	lead.TotalEngagements = current.TotalEngagements
+	lead.LastEngagementAt = current.LastEngagementAt
+	lead.ConversionPath = current.ConversionPath
+
+	touchpoint.LeadID = lead.ID
+	if touchpoint.CreatedAt.IsZero() {
+		touchpoint.CreatedAt = time.Now()
+	}
+	if err := tx.Create(touchpoint).Error; err != nil {
+		return err
+	}
+
+	path := append(models.JSONArray{}, lead.ConversionPath...)
+	path = trimConversionPath(append(path, conversionPathStep(touchpoint)))
+
+	lastEngagement := touchpoint.CreatedAt
+	if lead.LastEngagementAt != nil && lead.LastEngagementAt.After(lastEngagement) {
+		lastEngagement = *lead.LastEngagementAt
+	}
+
+	if err := tx.Model(&models.BlogLead{}).Where("id = ?", lead.ID).Updates(map[string]interface{}{
+		"total_engagements":  gorm.Expr("total_engagements + 1"),
+		"last_engagement_at": lastEngagement,
+		"conversion_path":    path,
+	}).Error; err != nil {
+		return err
+	}
+
+	lead.TotalEngagements++
+	lead.LastEngagementAt = &lastEngagement
+	lead.ConversionPath = path
+	return nil
+}
+
+// conversionPathStep describes a touchpoint in a lead's conversion path
+func conversionPathStep(touchpoint *models.LeadTouchpoint) string {
+	if touchpoint.URL == "" {
+		return touchpoint.TouchpointType
+	}
+	return fmt.Sprintf("%s:%s", touchpoint.TouchpointType, touchpoint.URL)
+}
+
+// trimConversionPath keeps the last maxConversionPathSteps steps of a conversion path
+func trimConversionPath(path models.JSONArray) models.JSONArray {
+	if len(path) <= maxConversionPathSteps {
+		return path
+	}
+	return path[len(path)-maxConversionPathSteps:]
+}
+
+// maxConversionPathSteps bounds the steps stored in a lead's conversion path.
+// Touchpoints themselves are all kept.
+const maxConversionPathSteps = 200
+
+// maxTouchpointClockSkew is how far ahead of the server clock occurred_at may be
+const maxTouchpointClockSkew = 5 * time.Minute
+
+// maxMergeHops bounds how many merges are followed to find a lead's surviving record
+const maxMergeHops = 5
+
+// RecordTouchpoint records a touchpoint for a known lead. Tracking scripts
+// identify the lead with the lead_token returned at capture; authenticated
+// integrations with lead:update may use lead_id.
+func (h *LeadHandler) RecordTouchpoint(c *gin.Context) {
+	var req models.TouchpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	if isAutomated(c, req.BlogID) {
+		respondSuccess(c, http.StatusAccepted, "Automated traffic is not recorded", nil)
+		return
+	}
+
+	touchpoints, ok := h.ingestTouchpoints(c, []models.TouchpointRequest{req}, false)
+	if !ok {
+		return
+	}
+
+	logger.LogBusinessEvent("touchpoint_recorded", "lead", touchpoints[0].LeadID, map[string]interface{}{
+		"touchpoint_type": touchpoints[0].TouchpointType,
+		"blog_id":         touchpoints[0].BlogID,
+	})
+
+	respondSuccess(c, http.StatusCreated, "Touchpoint recorded successfully", touchpoints[0])
+}
+
+// RecordTouchpointBatch records up to 100 touchpoints in one transaction.
+// Either every touchpoint is stored or, when one is invalid, none is.
+func (h *LeadHandler) RecordTouchpointBatch(c *gin.Context) {
+	var req models.TouchpointBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	if isAutomated(c, nil) {
+		respondSuccess(c, http.StatusAccepted, "Automated traffic is not recorded", nil)
+		return
+	}
+
+	touchpoints, ok := h.ingestTouchpoints(c, req.Touchpoints, true)
+	if !ok {
+		return
+	}
+
+	leads := map[uint]bool{}
+	for _, tp := range touchpoints {
+		leads[tp.LeadID] = true
+	}
+	logger.LogBusinessEvent("touchpoints_recorded", "lead_touchpoint", nil, map[string]interface{}{
+		"count": len(touchpoints),
+		"leads": len(leads),
+	})
+
+	respondSuccess(c, http.StatusCreated, "Touchpoints recorded successfully", map[string]interface{}{
+		"recorded":    len(touchpoints),
+		"touchpoints": touchpoints,
+	})
+}
+
+// ingestTouchpoints validates touchpoint requests, resolves their leads and
+// stores them in occurrence order, writing the error response when it cannot.
+// Converted leads are re-attributed when an authenticated integration records
+// a touchpoint that predates the conversion.
+func (h *LeadHandler) ingestTouchpoints(c *gin.Context, reqs []models.TouchpointRequest, batch bool) ([]models.LeadTouchpoint, bool) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return nil, false
+	}
+
+	detail := func(i int, msg string) string {
+		if batch {
+			return fmt.Sprintf("touchpoints[%d]: %s", i, msg)
+		}
+		return msg
+	}
+
+	now := time.Now()
+	leadIDs := make([]uint, len(reqs))
+	blogIDs := []uint{}
+	for i := range reqs {
+		req := &reqs[i]
+		if !containsString(models.IngestibleTouchpointTypes(), req.TouchpointType) {
+			respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_TOUCHPOINT_TYPE",
+				detail(i, "touchpoint_type must be one of: "+strings.Join(models.IngestibleTouchpointTypes(), ", ")))
+			return nil, false
+		}
+		if req.OccurredAt != nil && req.OccurredAt.After(now.Add(maxTouchpointClockSkew)) {
+			respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", detail(i, "occurred_at must not be in the future"))
+			return nil, false
+		}
+
+		switch {
+		case req.LeadToken != "" && req.LeadID != 0:
+			respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", detail(i, "send either lead_id or lead_token, not both"))
+			return nil, false
+		case req.LeadToken != "":
+			id, err := leadtoken.Verify(leadtoken.PurposeTracking, req.LeadToken, leadTokenSecret(), now)
+			if err != nil {
+				respondError(c, http.StatusUnauthorized, "Invalid lead token", "INVALID_LEAD_TOKEN", detail(i, err.Error()))
+				return nil, false
+			}
+			leadIDs[i] = id
+		case req.LeadID != 0:
+			if !middleware.IsAuthenticated(c) {
+				respondError(c, http.StatusUnauthorized, "Authentication required", "UNAUTHENTICATED", detail(i, "lead_id requires authentication, use lead_token instead"))
+				return nil, false
+			}
+			if !middleware.CanAccessResource(c, "lead", "update") {
+				respondError(c, http.StatusForbidden, "Insufficient permissions", "INSUFFICIENT_PERMISSIONS", "Required permission: lead:update")
+				return nil, false
+			}
+			leadIDs[i] = req.LeadID
+		default:
+			respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", detail(i, "lead_id or lead_token is required"))
+			return nil, false
+		}
+
+		if req.BlogID != nil {
+			blogIDs = append(blogIDs, *req.BlogID)
+		}
+	}
+
+	leads, err := loadSurvivingLeads(db, uniqueIDs(leadIDs))
+	if err != nil {
+		logger.Error("Failed to load leads for touchpoints", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to record touchpoints", "DATABASE_ERROR", "Unable to load leads")
+		return nil, false
+	}
+	for i, id := range leadIDs {
+		if leads[id] == nil {
+			respondError(c, http.StatusNotFound, "Lead not found", "LEAD_NOT_FOUND", detail(i, "Lead does not exist"))
+			return nil, false
+		}
+		// Tracking needs the consent the lead withdrew or never gave
+		if leads[id].OptedOut || leads[id].QuarantinedAt != nil {
+			respondError(c, http.StatusForbidden, "Lead cannot be tracked", "LEAD_NOT_TRACKABLE", detail(i, "Lead opted out or has not given consent"))
+			return nil, false
+		}
+		// Token holders cannot rewrite a lead's history: their touchpoints
+		// neither predate the capture nor change a conversion's attribution
+		if reqs[i].LeadToken != "" && reqs[i].OccurredAt != nil {
+			if reqs[i].OccurredAt.Before(leads[id].CapturedAt) {
+				respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", detail(i, "occurred_at must not be before the lead was captured"))
+				return nil, false
+			}
+			if converted := leads[id].ConvertedAt; converted != nil && !reqs[i].OccurredAt.After(*converted) {
+				respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", detail(i, "occurred_at must be after the lead converted"))
+				return nil, false
+			}
+		}
+	}
+
+	blogTitles := map[uint]string{}
+	if len(blogIDs) > 0 {
+		blogs := []models.Blog{}
+		if err := db.Select("id", "title").Where("id IN ?", uniqueIDs(blogIDs)).Find(&blogs).Error; err != nil {
+			logger.Error("Failed to load blogs for touchpoints", err, nil)
+			respondError(c, http.StatusInternalServerError, "Failed to record touchpoints", "DATABASE_ERROR", "Unable to load blog posts")
+			return nil, false
+		}
+		for _, blog := range blogs {
+			blogTitles[blog.ID] = blog.Title
+		}
+		for i := range reqs {
+			if reqs[i].BlogID != nil {
+				if _, found := blogTitles[*reqs[i].BlogID]; !found {
+					respondError(c, http.StatusNotFound, "Blog not found", "BLOG_NOT_FOUND", detail(i, "Blog post does not exist"))
+					return nil, false
+				}
+			}
+		}
+	}
+
+	touchpoints := make([]models.LeadTouchpoint, len(reqs))
+	for i := range reqs {
+		req := &reqs[i]
+		title := req.Title
+		if title == "" && req.BlogID != nil {
+			title = truncateString(blogTitles[*req.BlogID], 500)
+		}
+		occurredAt := now
+		if req.OccurredAt != nil {
+			occurredAt = *req.OccurredAt
+		}
+		touchpoints[i] = models.LeadTouchpoint{
+			TouchpointType: req.TouchpointType,
+			BlogID:         req.BlogID,
+			URL:            req.URL,
+			Title:          title,
+			Source:         req.Source,
+			Medium:         req.Medium,
+			Campaign:       req.Campaign,
+			TimeSpent:      req.TimeSpent,
+			ScrollDepth:    req.ScrollDepth,
+			Interactions:   req.Interactions,
+			CreatedAt:      occurredAt,
+		}
+	}
+
+	// Conversion paths are built in the order touchpoints happened
+	order := make([]int, len(touchpoints))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return touchpoints[order[a]].CreatedAt.Before(touchpoints[order[b]].CreatedAt)
+	})
+
+	var merged *models.BlogLead
+	err = database.Transaction(func(tx *gorm.DB) error {
+		// The leads are locked in ID order up front, so concurrent batches
+		// touching the same leads wait on each other instead of deadlocking
+		resolvedIDs := []uint{}
+		for _, lead := range leads {
+			resolvedIDs = append(resolvedIDs, lead.ID)
+		}
+		locked := []models.BlogLead{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", uniqueIDs(resolvedIDs)).
+			Order("id ASC").
+			Find(&locked).Error; err != nil {
+			return err
+		}
+		for i := range locked {
+			for _, lead := range leads {
+				if lead.ID == locked[i].ID {
+					*lead = locked[i]
+				}
+			}
+			if locked[i].MergedIntoID != nil {
+				merged = &locked[i]
+				return errLeadMerged
+			}
+		}
+
+		reattribute := map[uint]*models.BlogLead{}
+		for _, i := range order {
+			lead := leads[leadIDs[i]]
+			if err := recordTouchpoint(tx, lead, &touchpoints[i]); err != nil {
+				return err
+			}
+			if reqs[i].LeadToken == "" && lead.Status == workflow.LeadStatusConverted && lead.ConvertedAt != nil &&
+				!touchpoints[i].CreatedAt.After(*lead.ConvertedAt) {
+				reattribute[lead.ID] = lead
+			}
+		}
+		for _, lead := range reattribute {
+			if err := reattributeLead(tx, lead); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errLeadMerged) {
+		rejectMergedLead(c, merged)
+		return nil, false
+	}
+	if err != nil {
+		logger.Error("Failed to record touchpoints", err, map[string]interface{}{"count": len(touchpoints)})
+		respondError(c, http.StatusInternalServerError, "Failed to record touchpoints", "DATABASE_ERROR", "Unable to store touchpoints")
+		return nil, false
+	}
+
+	return touchpoints, true
+}
+
+// loadSurvivingLeads loads leads by ID, following merges so a duplicate's ID
+// resolves to the lead it was merged into. IDs that do not exist are absent.
+func loadSurvivingLeads(db *gorm.DB, ids []uint) (map[uint]*models.BlogLead, error) {
+	loaded := map[uint]*models.BlogLead{}
+	pending := ids
+	for hop := 0; hop <= maxMergeHops && len(pending) > 0; hop++ {
+		leads := []models.BlogLead{}
+		if err := db.Where("id IN ?", pending).Find(&leads).Error; err != nil {
+			return nil, err
+		}
+		pending = nil
+		for i := range leads {
+			loaded[leads[i].ID] = &leads[i]
+			if target := leads[i].MergedIntoID; target != nil && loaded[*target] == nil {
+				pending = append(pending, *target)
+			}
+		}
+	}
+
+	resolved := make(map[uint]*models.BlogLead, len(ids))
+	for _, id := range ids {
+		lead := loaded[id]
+		for hop := 0; lead != nil && lead.MergedIntoID != nil && hop < maxMergeHops; hop++ {
+			lead = loaded[*lead.MergedIntoID]
+		}
+		if lead != nil && lead.MergedIntoID == nil {
+			resolved[id] = lead
+		}
+	}
+	return resolved, nil
+}
+
+// leadTokenSecret returns the key lead tokens are signed with
+func leadTokenSecret() []byte {
+	if secret := getEnv("LEAD_TOKEN_SECRET", ""); secret != "" {
+		return []byte(secret)
+	}
+	return auth.GetJWTSecret()
+}
+
+// issueLeadToken returns a tracking token for a lead, valid for LEAD_TOKEN_TTL_DAYS
+func issueLeadToken(leadID uint) string {
+	days, err := strconv.Atoi(getEnv("LEAD_TOKEN_TTL_DAYS", "365"))
+	if err != nil || days <= 0 {
+		days = 365
+	}
+	return leadtoken.Sign(leadtoken.PurposeTracking, leadID, time.Now().AddDate(0, 0, days), leadTokenSecret())
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errLeadStatusChanged is returned when a lead's status changed between read and write
+var errLeadStatusChanged = errors.New("lead status changed concurrently")
+
+// UpdateLeadStatus moves a lead to another pipeline status. Converted and lost
+// leads can only be reopened by users with lead:reopen.
+func (h *LeadHandler) UpdateLeadStatus(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid lead ID", "INVALID_ID", "Lead ID must be a positive integer")
+		return
+	}
+
+	var req models.LeadStatusUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	user, ok := middleware.GetCurrentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required", "UNAUTHENTICATED", "User context not found")
+		return
+	}
+	req.UpdatedBy = user.ID
+	req.UpdatedAt = time.Now()
+
+	if req.ConversionValue != nil && req.Status != workflow.LeadStatusConverted {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", "conversion_value can only be set when converting a lead")
+		return
+	}
+
+	lead, found := h.findLead(c, id)
+	if !found || rejectMergedLead(c, lead) {
+		return
+	}
+
+	if err := workflow.AuthorizeLead(user.Role, lead.Status, req.Status); err != nil {
+		respondLeadTransitionError(c, user, lead, req.Status, err)
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if customFields := mergeCustomFields(lead.CustomFields, req.CustomFields); customFields != nil {
+		updates["custom_fields"] = customFields
+	}
+	if req.ConversionValue != nil {
+		updates["conversion_value"] = *req.ConversionValue
+	}
+
+	fromStatus := lead.Status
+	err := database.Transaction(func(tx *gorm.DB) error {
+		return applyLeadStatusChange(tx, lead, req.Status, &req.UpdatedBy, req.Notes, updates)
+	})
+	if err != nil {
+		if errors.Is(err, errLeadStatusChanged) {
+			respondError(c, http.StatusConflict, "Status transition not allowed", "STATUS_CHANGED", "Lead status was changed by another request, reload and retry")
+			return
+		}
+		logger.Error("Failed to update lead status", err, map[string]interface{}{"lead_id": lead.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to update lead status", "DATABASE_ERROR", "Unable to update lead")
+		return
+	}
+
+	logger.LogBusinessEvent("lead_status_changed", "lead", lead.ID, map[string]interface{}{
+		"user_id":          req.UpdatedBy,
+		"from_status":      fromStatus,
+		"to_status":        lead.Status,
+		"conversion_value": lead.ConversionValue,
+	})
+
+	respondSuccess(c, http.StatusOK, "Lead status updated successfully", leadResponse(lead))
+}
+
+// ListLeadTransitions returns a lead's status history and the moves available to the caller
+func (h *LeadHandler) ListLeadTransitions(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid lead ID", "INVALID_ID", "Lead ID must be a positive integer")
+		return
+	}
+
+	lead, found := h.findLead(c, id)
+	if !found {
+		return
+	}
+
+	history := []models.LeadActivity{}
+	if err := database.GetDB().
+		Where("lead_id = ? AND activity_type = ?", lead.ID, models.LeadActivityStatusChanged).
+		Order("created_at DESC, id DESC").
+		Find(&history).Error; err != nil {
+		logger.Error("Failed to list lead transitions", err, map[string]interface{}{"lead_id": lead.ID})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve status history", "DATABASE_ERROR", "Unable to load status history")
+		return
+	}
+
+	role, _ := middleware.GetUserRole(c)
+
+	respondSuccess(c, http.StatusOK, "Status history retrieved successfully", map[string]interface{}{
+		"lead_id":   lead.ID,
+		"status":    lead.Status,
+		"history":   history,
+		"available": workflow.AvailableLeadTransitions(role, lead.Status),
+	})
+}
+
+// applyLeadStatusChange moves a lead to a new status, stamps the timestamp that
+// matches the new status and writes a status_changed activity. extra holds
+// additional column updates applied with the status. The update is guarded on
+// the current status so concurrent changes cannot both win. Converting or
+// reopening a converted lead recalculates its revenue attribution.
+func applyLeadStatusChange(tx *gorm.DB, lead *models.BlogLead, to string, userID *uint, notes string, extra map[string]interface{}) error {
+	from := lead.Status
+	now := time.Now().UTC()
+
+	updates := make(map[string]interface{}, len(extra)+3)
+	for column, value := range extra {
+		updates[column] = value
+	}
+	updates["status"] = to
+
+	switch to {
+	case workflow.LeadStatusContacted:
+		updates["last_contacted_at"] = now
+	case workflow.LeadStatusQualified:
+		if _, ok := updates["qualified_at"]; !ok {
+			updates["qualified_at"] = now
+			updates["qualified_by"] = userID
+		}
+	case workflow.LeadStatusConverted:
+		updates["converted_at"] = now
+	}
+	if from == workflow.LeadStatusConverted {
+		// A reopened lead no longer counts as converted
+		updates["converted_at"] = nil
+	}
+
+	result := tx.Model(&models.BlogLead{}).
+		Where("id = ? AND status = ?", lead.ID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errLeadStatusChanged
+	}
+
+	activity := models.LeadActivity{
+		LeadID:       lead.ID,
+		ActivityType: models.LeadActivityStatusChanged,
+		Title:        fmt.Sprintf("Status changed from %s to %s", from, to),
+		Description:  notes,
+		UserID:       userID,
+		Metadata: models.JSONMap{
+			"from_status": from,
+			"to_status":   to,
+			"reopened":    workflow.IsTerminalLeadStatus(from),
+		},
+	}
+	if err := tx.Create(&activity).Error; err != nil {
+		return err
+	}
+
+	if err := tx.First(lead, lead.ID).Error; err != nil {
+		return err
+	}
+
+	if from == workflow.LeadStatusConverted || to == workflow.LeadStatusConverted {
+		return reattributeLead(tx, lead)
+	}
+	return nil
+}
+
+// respondLeadTransitionError writes the response for a rejected lead status change
+func respondLeadTransitionError(c *gin.Context, user *middleware.UserInfo, lead *models.BlogLead, to string, err error) {
+	switch {
+	case errors.Is(err, workflow.ErrInvalidStatus):
+		respondError(c, http.StatusBadRequest, "Invalid request data", "INVALID_STATUS", err.Error())
+	case errors.Is(err, workflow.ErrPermissionDenied):
+		logger.LogSecurityEvent("insufficient_permissions", &user.ID, c.ClientIP(), map[string]interface{}{
+			"role":        user.Role,
+			"lead_id":     lead.ID,
+			"from_status": lead.Status,
+			"to_status":   to,
+			"path":        c.Request.URL.Path,
+		})
+		respondError(c, http.StatusForbidden, "Insufficient permissions", "INSUFFICIENT_PERMISSIONS", err.Error())
+	default:
+		respondError(c, http.StatusConflict, "Status transition not allowed", "INVALID_TRANSITION", err.Error())
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondSuccess writes the standard success envelope
+func respondSuccess(c *gin.Context, statusCode int, message string, data interface{}) {
+	c.JSON(statusCode, gin.H{
+		"success": true,
+		"message": message,
+		"data":    data,
+	})
+}
+
+// respondError writes the standard error envelope
+func respondError(c *gin.Context, statusCode int, message, code, detail string) {
+	c.JSON(statusCode, gin.H{
+		"success": false,
+		"message": message,
+		"error": map[string]string{
+			"code":    code,
+			"message": detail,
+		},
+	})
+}
+
+// parseIDParam parses a numeric path parameter
+func parseIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseIntQuery parses an integer query parameter with a default value
+func parseIntQuery(c *gin.Context, name string, defaultValue int) int {
+	if value := c.Query(name); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// parseUintQuery parses an optional unsigned integer query parameter
+func parseUintQuery(c *gin.Context, name string) *uint {
+	if value := c.Query(name); value != "" {
+		if parsed, err := strconv.ParseUint(value, 10, 64); err == nil {
+			id := uint(parsed)
+			return &id
+		}
+	}
+	return nil
+}
+
+// totalPages calculates the number of pages for a result set
+func totalPages(total int64, limit int) int {
+	if limit <= 0 {
+		return 0
+	}
+	return int((total + int64(limit) - 1) / int64(limit))
+}
+
+// normalizePagination applies the same bounds as database.Paginate
+func normalizePagination(page, limit int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"blog-service/internal/middleware"
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TagHandler handles blog tag endpoints
+type TagHandler struct{}
+
+// NewTagHandler creates a new tag handler instance
+func NewTagHandler() *TagHandler {
+	return &TagHandler{}
+}
+
+// ListTags returns tags with their blog counts, optionally filtered by name
+func (h *TagHandler) ListTags(c *gin.Context) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	query := tagQuery(c, db)
+	if search := c.Query("search"); search != "" {
+		query = query.Where("tags.name LIKE ?", "%"+search+"%")
+	}
+
+	tags := []models.Tag{}
+	if err := query.Order("tags.name ASC").Find(&tags).Error; err != nil {
+		logger.Error("Failed to list tags", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve tags", "DATABASE_ERROR", "Unable to list tags")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Tags retrieved successfully", tags)
+}
+
+// GetTag returns a single tag by ID
+func (h *TagHandler) GetTag(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid tag ID", "INVALID_ID", "Tag ID must be a positive integer")
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	var tag models.Tag
+	if err := tagQuery(c, db).Where("tags.id = ?", id).First(&tag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "Tag not found", "TAG_NOT_FOUND", "Tag does not exist")
+			return
+		}
+		logger.Error("Failed to load tag", err, map[string]interface{}{"tag_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve tag", "DATABASE_ERROR", "Unable to load tag")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Tag retrieved successfully", tag)
+}
+
+// CreateTag creates a new tag
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	tagSlug, taken, err := resolveTaxonomySlug(db, &models.Tag{}, req.Slug, req.Name, 0)
+	if err != nil {
+		logger.Error("Failed to generate tag slug", err, nil)
+		respondError(c, http.StatusInternalServerError, "Failed to create tag", "DATABASE_ERROR", "Unable to generate slug")
+		return
+	}
+	if taken {
+		respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another tag already uses this slug")
+		return
+	}
+
+	tag := models.Tag{Name: req.Name, Slug: tagSlug}
+
+	start := time.Now()
+	err = db.Create(&tag).Error
+	logger.LogDatabaseOperation("create", "tags", tag.ID, time.Since(start), err)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another tag already uses this slug")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to create tag", "DATABASE_ERROR", "Unable to create tag")
+		return
+	}
+
+	respondSuccess(c, http.StatusCreated, "Tag created successfully", tag)
+}
+
+// UpdateTag renames a tag. An empty slug is regenerated from the new name.
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid tag ID", "INVALID_ID", "Tag ID must be a positive integer")
+		return
+	}
+
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	tag, found := h.findTag(c, id)
+	if !found {
+		return
+	}
+
+	db := database.GetDB()
+	tagSlug, taken, err := resolveTaxonomySlug(db, &models.Tag{}, req.Slug, req.Name, tag.ID)
+	if err != nil {
+		logger.Error("Failed to generate tag slug", err, map[string]interface{}{"tag_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to update tag", "DATABASE_ERROR", "Unable to generate slug")
+		return
+	}
+	if taken {
+		respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another tag already uses this slug")
+		return
+	}
+
+	start := time.Now()
+	err = db.Model(tag).Updates(map[string]interface{}{"name": req.Name, "slug": tagSlug}).Error
+	logger.LogDatabaseOperation("update", "tags", tag.ID, time.Since(start), err)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		respondError(c, http.StatusConflict, "Slug already in use", "SLUG_EXISTS", "Another tag already uses this slug")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to update tag", "DATABASE_ERROR", "Unable to update tag")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "Tag updated successfully", tag)
+}
+
+// DeleteTag deletes a tag and removes it from all blog posts
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusBadRequest, "Invalid tag ID", "INVALID_ID", "Tag ID must be a positive integer")
+		return
+	}
+
+	tag, found := h.findTag(c, id)
+	if !found {
+		return
+	}
+
+	start := time.Now()
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM blog_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(tag).Error
+	})
+	logger.LogDatabaseOperation("delete", "tags", tag.ID, time.Since(start), err)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to delete tag", "DATABASE_ERROR", "Unable to delete tag")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	logger.LogBusinessEvent("tag_deleted", "tag", tag.ID, map[string]interface{}{
+		"user_id": userID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tag deleted successfully",
+	})
+}
+
+// findTag loads a tag by ID and writes the error response when it cannot
+func (h *TagHandler) findTag(c *gin.Context, id uint) (*models.Tag, bool) {
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return nil, false
+	}
+
+	var tag models.Tag
+	if err := db.First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "Tag not found", "TAG_NOT_FOUND", "Tag does not exist")
+			return nil, false
+		}
+		logger.Error("Failed to load tag", err, map[string]interface{}{"tag_id": id})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve tag", "DATABASE_ERROR", "Unable to load tag")
+		return nil, false
+	}
+
+	return &tag, true
+}
+
+// tagQuery selects tags with their blog counts. Public callers only count published posts.
+func tagQuery(c *gin.Context, db *gorm.DB) *gorm.DB {
+	countSQL := "(SELECT COUNT(*) FROM blog_tags JOIN blogs ON blogs.id = blog_tags.blog_id WHERE blog_tags.tag_id = tags.id AND blogs.deleted_at IS NULL"
+	if !middleware.CanAccessResource(c, "blog", "read") {
+		countSQL += " AND blogs.status = 'published'"
+	}
+	countSQL += ") AS blog_count"
+
+	return db.Model(&models.Tag{}).Select("tags.*, " + countSQL)
+}
+
+// loadTags resolves tag IDs for a blog post, reporting false if any ID is unknown
+func loadTags(db *gorm.DB, ids []uint) ([]models.Tag, bool, error) {
+	tags := []models.Tag{}
+	if len(ids) == 0 {
+		return tags, true, nil
+	}
+
+	unique := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	if err := db.Where("id IN ?", ids).Find(&tags).Error; err != nil {
+		return nil, false, err
+	}
+	return tags, len(tags) == len(unique), nil
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/botdetect"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/tracking"
+	"blog-service/pkg/workflow"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errVisitorHistoryClaimed aborts a capture when a concurrent capture stitched
+// some of the visitor's events first
+var errVisitorHistoryClaimed = errors.New("visitor history claimed concurrently")
+
+// maxStitchedEvents bounds the visitor history stitched into a lead at capture
+const maxStitchedEvents = 500
+
+// TrackingHandler handles anonymous visitor tracking
+type TrackingHandler struct{}
+
+// NewTrackingHandler creates a new tracking handler instance
+func NewTrackingHandler() *TrackingHandler {
+	return &TrackingHandler{}
+}
+
+// TrackEvent records a page view or engagement report for the anonymous
+// visitor in the visitor cookie, issuing the cookie on the first event
+func (h *TrackingHandler) TrackEvent(c *gin.Context) {
+	var req models.TrackEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if req.SessionID != "" && !tracking.IsValidSessionID(req.SessionID) {
+		respondError(c, http.StatusBadRequest, "Invalid request data", "VALIDATION_ERROR", "session_id may only contain letters, digits, dashes and underscores")
+		return
+	}
+	if isAutomated(c, req.BlogID) {
+		respondSuccess(c, http.StatusAccepted, "Automated traffic is not recorded", nil)
+		return
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		respondError(c, http.StatusServiceUnavailable, "Database unavailable", "DATABASE_UNAVAILABLE", "database not initialized")
+		return
+	}
+
+	if req.BlogID != nil {
+		var count int64
+		if err := db.Model(&models.Blog{}).Where("id = ? AND status = ?", *req.BlogID, workflow.StatusPublished).Count(&count).Error; err != nil {
+			logger.Error("Failed to check blog for tracking", err, map[string]interface{}{"blog_id": *req.BlogID})
+			respondError(c, http.StatusInternalServerError, "Failed to record event", "DATABASE_ERROR", "Unable to load blog post")
+			return
+		}
+		if count == 0 {
+			respondError(c, http.StatusNotFound, "Blog not found", "BLOG_NOT_FOUND", "Blog post does not exist")
+			return
+		}
+	}
+
+	visitorID, ok := ensureVisitorCookie(c)
+	if !ok {
+		return
+	}
+
+	event := models.VisitorEvent{
+		VisitorID:    visitorID,
+		SessionID:    req.SessionID,
+		EventType:    req.EventType,
+		BlogID:       req.BlogID,
+		URL:          req.URL,
+		Title:        req.Title,
+		Referrer:     req.Referrer,
+		UTMSource:    req.UTMSource,
+		UTMMedium:    req.UTMMedium,
+		UTMCampaign:  req.UTMCampaign,
+		TimeSpent:    req.TimeSpent,
+		ScrollDepth:  req.ScrollDepth,
+		Interactions: req.Interactions,
+	}
+	if err := db.Create(&event).Error; err != nil {
+		logger.Error("Failed to record visitor event", err, map[string]interface{}{"event_type": req.EventType})
+		respondError(c, http.StatusInternalServerError, "Failed to record event", "DATABASE_ERROR", "Unable to store event")
+		return
+	}
+
+	// The visitor ID stays in the HttpOnly cookie, out of reach of page scripts
+	respondSuccess(c, http.StatusAccepted, "Event recorded", nil)
+}
+
+// isAutomated reports whether BotFilter classified the request as automated
+// traffic, noting blogID for the bot hit log
+func isAutomated(c *gin.Context, blogID *uint) bool {
+	value, ok := c.Get("bot")
+	if !ok {
+		return false
+	}
+	classification, ok := value.(botdetect.Classification)
+	if !ok || !classification.Bot {
+		return false
+	}
+	if blogID != nil {
+		c.Set("bot_blog_id", *blogID)
+	}
+	return true
+}
+
+// ensureVisitorCookie returns the visitor ID from the visitor cookie, issuing
+// a new ID when the cookie is missing or invalid. The cookie is refreshed on
+// every call so active visitors keep their ID.
+func ensureVisitorCookie(c *gin.Context) (string, bool) {
+	visitorID := visitorIDFromCookie(c)
+	if visitorID == "" {
+		var err error
+		if visitorID, err = tracking.NewVisitorID(); err != nil {
+			logger.Error("Failed to generate visitor ID", err, nil)
+			respondError(c, http.StatusInternalServerError, "Failed to record event", "INTERNAL_ERROR", "Unable to identify visitor")
+			return "", false
+		}
+	}
+
+	days, err := strconv.Atoi(getEnv("VISITOR_COOKIE_DAYS", "395"))
+	if err != nil || days <= 0 {
+		days = 395
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     visitorCookieName(),
+		Value:    visitorID,
+		Path:     "/",
+		Domain:   getEnv("VISITOR_COOKIE_DOMAIN", ""),
+		MaxAge:   days * 24 * 60 * 60,
+		Secure:   c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https"),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return visitorID, true
+}
+
+// visitorIDFromCookie returns the visitor ID in the request's visitor cookie, or "" if none is valid
+func visitorIDFromCookie(c *gin.Context) string {
+	visitorID, err := c.Cookie(visitorCookieName())
+	if err != nil || !tracking.IsValidVisitorID(visitorID) {
+		return ""
+	}
+	return visitorID
+}
+
+// visitorCookieName returns the name of the first-party visitor cookie
+func visitorCookieName() string {
+	return getEnv("VISITOR_COOKIE_NAME", "bs_vid")
+}
+
+// visitorHistory is an anonymous visitor's unstitched events and their summary
+type visitorHistory struct {
+	events  []models.VisitorEvent
+	summary tracking.History
+}
+
+// loadVisitorHistory loads the events of a visitor not yet stitched into a lead, oldest first
+func loadVisitorHistory(db *gorm.DB, visitorID string) (*visitorHistory, error) {
+	history := &visitorHistory{}
+	if visitorID == "" {
+		return history, nil
+	}
+
+	// Newest events win when the history is longer than the stitch limit
+	if err := db.Where("visitor_id = ? AND lead_id IS NULL", visitorID).
+		Order("created_at DESC, id DESC").
+		Limit(maxStitchedEvents).
+		Find(&history.events).Error; err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(history.events)-1; i < j; i, j = i+1, j-1 {
+		history.events[i], history.events[j] = history.events[j], history.events[i]
+	}
+
+	events := make([]tracking.Event, len(history.events))
+	for i, event := range history.events {
+		events[i] = tracking.Event{
+			Type:         event.EventType,
+			SessionID:    event.SessionID,
+			URL:          event.URL,
+			At:           event.CreatedAt,
+			TimeSpent:    event.TimeSpent,
+			ScrollDepth:  event.ScrollDepth,
+			Interactions: event.Interactions,
+		}
+	}
+	history.summary = tracking.Summarize(events)
+
+	return history, nil
+}
+
+// applyPreCaptureHistory sets a new lead's pre-capture engagement fields from
+// the visitor's tracked history, replacing what the form reported
+func applyPreCaptureHistory(lead *models.BlogLead, history *visitorHistory) {
+	if len(history.summary.Visits) == 0 {
+		return
+	}
+	lead.PageViewsBeforeCapture = history.summary.PageViews
+	lead.TimeOnSiteBeforeCapture = history.summary.TimeOnSite
+	// The visit the form was submitted in is not a previous visit
+	lead.PreviousVisits = max(history.summary.Sessions-1, 0)
+}
+
+// stitchVisitorHistory records each tracked page visit as a touchpoint of the
+// lead, oldest first, and marks the visitor's events as stitched. When a
+// concurrent capture stitched some of the events first, it returns
+// errVisitorHistoryClaimed so the capture is rolled back and retried.
+func stitchVisitorHistory(tx *gorm.DB, lead *models.BlogLead, history *visitorHistory) error {
+	if len(history.events) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(history.events))
+	for i, event := range history.events {
+		ids[i] = event.ID
+	}
+	result := tx.Model(&models.VisitorEvent{}).
+		Where("id IN ? AND lead_id IS NULL", ids).
+		Update("lead_id", lead.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(ids)) {
+		return errVisitorHistoryClaimed
+	}
+
+	for _, visit := range history.summary.Visits {
+		event := history.events[visit.Index]
+		touchpointType := models.TouchpointPageView
+		if event.BlogID != nil {
+			touchpointType = models.TouchpointBlogView
+		}
+		source := event.UTMSource
+		if source == "" {
+			source = referrerDomain(event.Referrer)
+		}
+
+		touchpoint := models.LeadTouchpoint{
+			TouchpointType: touchpointType,
+			BlogID:         event.BlogID,
+			URL:            event.URL,
+			Title:          event.Title,
+			Source:         truncateString(source, 100),
+			Medium:         event.UTMMedium,
+			Campaign:       event.UTMCampaign,
+			TimeSpent:      visit.TimeSpent,
+			ScrollDepth:    visit.ScrollDepth,
+			Interactions:   visit.Interactions,
+			CreatedAt:      event.CreatedAt,
+		}
+		if err := recordTouchpoint(tx, lead, &touchpoint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
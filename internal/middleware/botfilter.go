@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"blog-service/pkg/botdetect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BotFilter classifies requests to tracking endpoints as human or automated.
+// The classification is stored in the context under "bot" so handlers can skip
+// counting automated hits; once the handler has run, automated hits are added
+// to hits for crawl analysis, with the blog the handler stored under
+// "bot_blog_id". Authenticated requests are integrations and never classified.
+func BotFilter(classifier *botdetect.Classifier, hits *botdetect.HitLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, authenticated := c.Get("user_id"); authenticated {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		classification := classifier.Classify(botdetect.Request{
+			UserAgent:      c.Request.UserAgent(),
+			IP:             c.ClientIP(),
+			AcceptLanguage: c.GetHeader("Accept-Language"),
+			ClientHintsUA:  c.GetHeader("Sec-CH-UA"),
+		}, now)
+		c.Set("bot", classification)
+
+		c.Next()
+
+		if !classification.Bot {
+			return
+		}
+		hit := botdetect.Hit{
+			At:        now,
+			Route:     c.FullPath(),
+			Path:      c.Request.URL.RequestURI(),
+			Name:      classification.Name,
+			Reason:    classification.Reason,
+			UserAgent: c.Request.UserAgent(),
+			IP:        c.ClientIP(),
+		}
+		if blogID, ok := c.Get("bot_blog_id"); ok {
+			if id, ok := blogID.(uint); ok && id > 0 {
+				hit.BlogID = &id
+			}
+		}
+		hits.Add(hit)
+	}
+}
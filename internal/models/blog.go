@@ -1,18 +1,20 @@
 package models
 
 import (
-	"gorm.io/gorm"
+	"blog-service/pkg/textdiff"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Blog represents a blog post with CRM integration
 type Blog struct {
 	ID              uint           `json:"id" gorm:"primaryKey"`
-	Title           string         `json:"title" gorm:"not null;size:500"`
+	Title           string         `json:"title" gorm:"not null;size:500;index:idx_blogs_fulltext,class:FULLTEXT,priority:1"`
 	Slug            string         `json:"slug" gorm:"uniqueIndex;not null;size:255"`
-	Content         string         `json:"content" gorm:"type:longtext"`
-	Excerpt         string         `json:"excerpt" gorm:"size:1000"`
-	Status          string         `json:"status" gorm:"default:'draft';size:20"` // draft, published, archived
+	Content         string         `json:"content" gorm:"type:longtext;index:idx_blogs_fulltext,class:FULLTEXT,priority:3"`
+	Excerpt         string         `json:"excerpt" gorm:"size:1000;index:idx_blogs_fulltext,class:FULLTEXT,priority:2"`
+	Status          string         `json:"status" gorm:"default:'draft';size:20;index"` // draft, in_review, approved, scheduled, published, archived
 	AuthorID        uint           `json:"author_id" gorm:"not null"`
 	CategoryID      *uint          `json:"category_id"`
 	FeaturedImage   string         `json:"featured_image" gorm:"size:500"`
@@ -24,6 +26,7 @@ type Blog struct {
 	CommentsCount   int            `json:"comments_count" gorm:"default:0"`
 	SharesCount     int            `json:"shares_count" gorm:"default:0"`
 	PublishedAt     *time.Time     `json:"published_at"`
+	ScheduledAt     *time.Time     `json:"scheduled_at" gorm:"index"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
@@ -39,6 +42,11 @@ type Blog struct {
 	UTMSource           string  `json:"utm_source" gorm:"size:100"`
 	UTMMedium           string  `json:"utm_medium" gorm:"size:100"`
 	UTMCampaign         string  `json:"utm_campaign" gorm:"size:100"`
+
+	Tags []Tag `json:"tags,omitempty" gorm:"many2many:blog_tags;"`
+
+	// ContentHTML is the sanitized rendering of Content, filled for readers
+	ContentHTML string `json:"content_html,omitempty" gorm:"-"`
 }
 
 // AdminUser represents the user who authors/manages blogs
@@ -51,3 +59,140 @@ type AdminUser struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// Request/Response Models
+
+// CreateBlogRequest represents a request to create a blog post
+type CreateBlogRequest struct {
+	Title           string `json:"title" binding:"required,max=500"`
+	Slug            string `json:"slug" binding:"max=255"` // generated from Title when empty
+	Content         string `json:"content" binding:"required"`
+	Excerpt         string `json:"excerpt" binding:"max=1000"`
+	Status          string `json:"status"`
+	CategoryID      *uint  `json:"category_id"`
+	FeaturedImage   string `json:"featured_image" binding:"max=500"`
+	MetaTitle       string `json:"meta_title" binding:"max=255"`
+	MetaDescription string `json:"meta_description" binding:"max=500"`
+	FocusKeyword    string `json:"focus_keyword" binding:"max=255"`
+	LeadSource      string `json:"lead_source"`
+	UTMSource       string `json:"utm_source"`
+	UTMMedium       string `json:"utm_medium"`
+	UTMCampaign     string `json:"utm_campaign"`
+	TagIDs          []uint `json:"tag_ids"`
+}
+
+// UpdateBlogRequest represents a partial update of a blog post.
+// Only non-nil fields are applied.
+type UpdateBlogRequest struct {
+	Title           *string `json:"title" binding:"omitempty,max=500"`
+	Slug            *string `json:"slug" binding:"omitempty,max=255"`
+	Content         *string `json:"content"`
+	Excerpt         *string `json:"excerpt" binding:"omitempty,max=1000"`
+	Status          *string `json:"status"`
+	CategoryID      *uint   `json:"category_id"`
+	FeaturedImage   *string `json:"featured_image" binding:"omitempty,max=500"`
+	MetaTitle       *string `json:"meta_title" binding:"omitempty,max=255"`
+	MetaDescription *string `json:"meta_description" binding:"omitempty,max=500"`
+	FocusKeyword    *string `json:"focus_keyword" binding:"omitempty,max=255"`
+	LeadSource      *string `json:"lead_source"`
+	UTMSource       *string `json:"utm_source"`
+	UTMMedium       *string `json:"utm_medium"`
+	UTMCampaign     *string `json:"utm_campaign"`
+	TagIDs          *[]uint `json:"tag_ids"` // replaces all tags when set
+}
+
+// BlogFilters represents filters for blog list queries
+type BlogFilters struct {
+	Status     string `json:"status"`
+	CategoryID *uint  `json:"category_id"` // matches the category and all its descendants
+	Category   string `json:"category"`    // category slug, matched with descendants
+	AuthorID   *uint  `json:"author_id"`
+	TagID      *uint  `json:"tag_id"`
+	Tag        string `json:"tag"` // tag slug
+	Search     string `json:"search"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+}
+
+// PaginatedBlogsResponse represents a paginated blog list response
+type PaginatedBlogsResponse struct {
+	Blogs      []Blog `json:"blogs"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	TotalPages int    `json:"total_pages"`
+}
+
+// BlogStatusTransition records a workflow status change for audit purposes
+type BlogStatusTransition struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	BlogID      uint       `json:"blog_id" gorm:"not null;index"`
+	FromStatus  string     `json:"from_status" gorm:"size:20;not null"`
+	ToStatus    string     `json:"to_status" gorm:"size:20;not null"`
+	UserID      *uint      `json:"user_id" gorm:"index"` // nil when performed by the scheduler
+	Notes       string     `json:"notes" gorm:"type:text"`
+	ScheduledAt *time.Time `json:"scheduled_at"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"index"`
+}
+
+// TableName specifies the table name for BlogStatusTransition
+func (BlogStatusTransition) TableName() string {
+	return "blog_status_transitions"
+}
+
+// BlogTransitionRequest represents a request to move a blog through the workflow
+type BlogTransitionRequest struct {
+	Status      string     `json:"status" binding:"required"`
+	Notes       string     `json:"notes"`
+	ScheduledAt *time.Time `json:"scheduled_at"`
+}
+
+// BlogRevision stores a snapshot of a blog's editable content after each change
+type BlogRevision struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	BlogID          uint      `json:"blog_id" gorm:"not null;uniqueIndex:idx_blog_revision"`
+	RevisionNumber  int       `json:"revision_number" gorm:"not null;uniqueIndex:idx_blog_revision"`
+	Title           string    `json:"title" gorm:"not null;size:500"`
+	Content         string    `json:"content,omitempty" gorm:"type:longtext"`
+	Excerpt         string    `json:"excerpt" gorm:"size:1000"`
+	MetaTitle       string    `json:"meta_title" gorm:"size:255"`
+	MetaDescription string    `json:"meta_description" gorm:"size:500"`
+	ChangedFields   JSONArray `json:"changed_fields" gorm:"type:json"`
+	AuthorID        *uint     `json:"author_id" gorm:"index"` // user who made the change
+	RestoredFrom    *int      `json:"restored_from"`          // revision number this one restores
+	CreatedAt       time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName specifies the table name for BlogRevision
+func (BlogRevision) TableName() string {
+	return "blog_revisions"
+}
+
+// BlogRevisionDiffResponse represents a line-level diff between two revisions
+type BlogRevisionDiffResponse struct {
+	BlogID       uint                     `json:"blog_id"`
+	FromRevision int                      `json:"from_revision"`
+	ToRevision   int                      `json:"to_revision"`
+	Fields       map[string]BlogFieldDiff `json:"fields"`
+}
+
+// BlogFieldDiff represents the diff of a single revision field
+type BlogFieldDiff struct {
+	Changed bool            `json:"changed"`
+	Added   int             `json:"added"`
+	Removed int             `json:"removed"`
+	Lines   []textdiff.Line `json:"lines"`
+}
+
+// BlogSlugRedirect maps a retired slug to the blog post that used it
+type BlogSlugRedirect struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	OldSlug   string    `json:"old_slug" gorm:"uniqueIndex;not null;size:255"`
+	BlogID    uint      `json:"blog_id" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for BlogSlugRedirect
+func (BlogSlugRedirect) TableName() string {
+	return "blog_slug_redirects"
+}
@@ -13,6 +13,7 @@ import (
 type BlogLead struct {
 	ID              uint   `json:"id" gorm:"primaryKey"`
 	Email           string `json:"email" gorm:"size:255;not null;index"`
+	NormalizedEmail string `json:"-" gorm:"size:255;index"` // duplicate detection key, see leadmatch.NormalizeEmail
 	Name            string `json:"name" gorm:"size:255"`
 	FirstName       string `json:"first_name" gorm:"size:100"`
 	LastName        string `json:"last_name" gorm:"size:100"`
@@ -53,6 +54,9 @@ type BlogLead struct {
 	AssignedTo  *uint `json:"assigned_to" gorm:"index"`
 	QualifiedBy *uint `json:"qualified_by"`
 
+	// MergedIntoID is set on a duplicate lead after it was merged into another lead
+	MergedIntoID *uint `json:"merged_into_id" gorm:"index"`
+
 	// UTM and tracking parameters
 	UTMSource   string `json:"utm_source" gorm:"size:100"`
 	UTMMedium   string `json:"utm_medium" gorm:"size:100"`
@@ -100,11 +104,17 @@ type BlogLead struct {
 	ConsentTimestamp *time.Time `json:"consent_timestamp"`
 	OptedOut         bool       `json:"opted_out" gorm:"default:false"`
 	OptOutTimestamp  *time.Time `json:"opt_out_timestamp"`
+	QuarantinedAt    *time.Time `json:"quarantined_at" gorm:"index"` // captured without required consent; held until consent is given
+	ErasedAt         *time.Time `json:"erased_at" gorm:"index"`      // personal data anonymized on a data subject request
 
-	// Relationships
+	// ReconsentRequestedAt is when a consent confirmation link was last sent to
+	// an opted-out or quarantined lead; cleared when the link is used
+	ReconsentRequestedAt *time.Time `json:"reconsent_requested_at"`
+
+	// Relationships. admin_users belongs to the CRM, so no foreign keys are migrated for it.
 	Blog            *Blog            `json:"blog,omitempty" gorm:"foreignKey:BlogID"`
-	AssignedUser    *AdminUser       `json:"assigned_user,omitempty" gorm:"foreignKey:AssignedTo"`
-	QualifiedByUser *AdminUser       `json:"qualified_by_user,omitempty" gorm:"foreignKey:QualifiedBy"`
+	AssignedUser    *AdminUser       `json:"assigned_user,omitempty" gorm:"foreignKey:AssignedTo;-:migration"`
+	QualifiedByUser *AdminUser       `json:"qualified_by_user,omitempty" gorm:"foreignKey:QualifiedBy;-:migration"`
 	Activities      []LeadActivity   `json:"activities,omitempty" gorm:"foreignKey:LeadID"`
 	Touchpoints     []LeadTouchpoint `json:"touchpoints,omitempty" gorm:"foreignKey:LeadID"`
 }
@@ -114,6 +124,41 @@ func (BlogLead) TableName() string {
 	return "blog_leads"
 }
 
+// Lead activity types recorded by the service
+const (
+	LeadActivityScored        = "scored"
+	LeadActivityQualified     = "qualified"
+	LeadActivityStatusChanged = "status_changed"
+	LeadActivityMerged        = "merged"
+	LeadActivityAssigned      = "assigned"
+	LeadActivityOptedOut      = "opted_out"
+	LeadActivityReconsented   = "reconsented"
+	LeadActivityErased        = "erased"
+	LeadActivityEmailAdded    = "email_added"
+)
+
+// Touchpoint types. lead_capture is recorded by the service; the others are
+// ingested from tracking scripts and integrations.
+const (
+	TouchpointLeadCapture = "lead_capture"
+	TouchpointBlogView    = "blog_view"
+	TouchpointPageView    = "page_view"
+	TouchpointEmailOpen   = "email_open"
+	TouchpointEmailClick  = "email_click"
+	TouchpointSocialShare = "social_share"
+	TouchpointCTAClick    = "cta_click"
+	TouchpointDownload    = "download"
+	TouchpointWebinar     = "webinar"
+)
+
+// IngestibleTouchpointTypes returns the touchpoint types clients can record
+func IngestibleTouchpointTypes() []string {
+	return []string{
+		TouchpointBlogView, TouchpointPageView, TouchpointEmailOpen, TouchpointEmailClick,
+		TouchpointSocialShare, TouchpointCTAClick, TouchpointDownload, TouchpointWebinar,
+	}
+}
+
 // LeadActivity represents activities performed on or by a lead
 type LeadActivity struct {
 	ID           uint      `json:"id" gorm:"primaryKey"`
@@ -127,7 +172,7 @@ type LeadActivity struct {
 
 	// Relationships
 	Lead *BlogLead  `json:"lead,omitempty" gorm:"foreignKey:LeadID"`
-	User *AdminUser `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	User *AdminUser `json:"user,omitempty" gorm:"foreignKey:UserID;-:migration"`
 }
 
 // TableName specifies the table name for LeadActivity
@@ -149,9 +194,10 @@ type LeadTouchpoint struct {
 	TimeSpent         int       `json:"time_spent"` // seconds
 	ScrollDepth       float64   `json:"scroll_depth"`
 	Interactions      int       `json:"interactions"`
-	ConversionValue   float64   `json:"conversion_value" gorm:"type:decimal(10,2)"`
-	AttributionWeight float64   `json:"attribution_weight" gorm:"type:decimal(3,2);default:0"`
-	CreatedAt         time.Time `json:"created_at" gorm:"index"`
+	ConversionValue   float64   `json:"conversion_value" gorm:"type:decimal(10,2)"`            // share of the lead's conversion value
+	AttributionWeight float64   `json:"attribution_weight" gorm:"type:decimal(5,4);default:0"` // share of conversion credit, 0-1
+	CreatedAt         time.Time `json:"created_at" gorm:"index"`                               // when the touchpoint happened
+	UpdatedAt         time.Time `json:"updated_at" gorm:"index"`                               // when the row was stored or last changed, e.g. by re-attribution
 
 	// Relationships
 	Lead *BlogLead `json:"lead,omitempty" gorm:"foreignKey:LeadID"`
@@ -163,36 +209,53 @@ func (LeadTouchpoint) TableName() string {
 	return "lead_touchpoints"
 }
 
+// LeadMerge is the audit record of a duplicate lead merged into a primary lead
+type LeadMerge struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	PrimaryLeadID uint      `json:"primary_lead_id" gorm:"not null;index"`
+	MergedLeadID  uint      `json:"merged_lead_id" gorm:"not null;index"`
+	MergedBy      uint      `json:"merged_by"`
+	Notes         string    `json:"notes" gorm:"type:text"`
+	MergedLead    JSONMap   `json:"merged_lead" gorm:"type:json"`    // snapshot of the duplicate before the merge
+	ChangedFields JSONArray `json:"changed_fields" gorm:"type:json"` // primary lead columns filled or combined
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for LeadMerge
+func (LeadMerge) TableName() string {
+	return "lead_merges"
+}
+
 // Request/Response Models
 
 // BlogLeadCaptureRequest represents a request to capture a blog lead
 type BlogLeadCaptureRequest struct {
 	// Required fields
-	Email      string `json:"email" binding:"required,email"`
-	BlogID     uint   `json:"blog_id" binding:"required"`
-	SourceType string `json:"source_type" binding:"required"`
+	Email      string `json:"email" binding:"required,email,max=255"`
+	BlogID     uint   `json:"blog_id"` // taken from the URL; must match it when given
+	SourceType string `json:"source_type" binding:"required,max=50"`
 
 	// Optional personal information
-	Name            string `json:"name"`
-	FirstName       string `json:"first_name"`
-	LastName        string `json:"last_name"`
-	Company         string `json:"company"`
-	JobTitle        string `json:"job_title"`
-	Phone           string `json:"phone"`
-	Website         string `json:"website"`
-	LinkedInProfile string `json:"linkedin_profile"`
+	Name            string `json:"name" binding:"max=255"`
+	FirstName       string `json:"first_name" binding:"max=100"`
+	LastName        string `json:"last_name" binding:"max=100"`
+	Company         string `json:"company" binding:"max=255"`
+	JobTitle        string `json:"job_title" binding:"max=255"`
+	Phone           string `json:"phone" binding:"max=50"`
+	Website         string `json:"website" binding:"max=500"`
+	LinkedInProfile string `json:"linkedin_profile" binding:"max=500"`
 
 	// Source and tracking information
 	SourceDetails map[string]interface{} `json:"source_details"`
-	CaptureMethod string                 `json:"capture_method"`
-	UTMSource     string                 `json:"utm_source"`
-	UTMMedium     string                 `json:"utm_medium"`
-	UTMCampaign   string                 `json:"utm_campaign"`
-	UTMTerm       string                 `json:"utm_term"`
-	UTMContent    string                 `json:"utm_content"`
-	TrafficSource string                 `json:"traffic_source"`
-	ReferrerURL   string                 `json:"referrer_url"`
-	LandingPage   string                 `json:"landing_page"`
+	CaptureMethod string                 `json:"capture_method" binding:"max=50"`
+	UTMSource     string                 `json:"utm_source" binding:"max=100"`
+	UTMMedium     string                 `json:"utm_medium" binding:"max=100"`
+	UTMCampaign   string                 `json:"utm_campaign" binding:"max=100"`
+	UTMTerm       string                 `json:"utm_term" binding:"max=100"`
+	UTMContent    string                 `json:"utm_content" binding:"max=100"`
+	TrafficSource string                 `json:"traffic_source" binding:"max=100"`
+	ReferrerURL   string                 `json:"referrer_url" binding:"max=1000"`
+	LandingPage   string                 `json:"landing_page" binding:"max=1000"`
 
 	// Device and behavior information
 	DeviceInfo     map[string]interface{} `json:"device_info"`
@@ -205,7 +268,7 @@ type BlogLeadCaptureRequest struct {
 
 	// Privacy and consent
 	ConsentGiven bool   `json:"consent_given"`
-	ConsentType  string `json:"consent_type"`
+	ConsentType  string `json:"consent_type" binding:"max=50"`
 }
 
 // BlogLeadResponse represents a blog lead response
@@ -230,6 +293,18 @@ type BlogLeadResponse struct {
 	UTMMedium           string     `json:"utm_medium"`
 	UTMCampaign         string     `json:"utm_campaign"`
 	TrafficSource       string     `json:"traffic_source"`
+	MergedIntoID        *uint      `json:"merged_into_id,omitempty"`
+	OptedOut            bool       `json:"opted_out"`
+	QuarantinedAt       *time.Time `json:"quarantined_at,omitempty"`
+	ErasedAt            *time.Time `json:"erased_at,omitempty"`
+	LeadToken           string     `json:"lead_token,omitempty"`  // returned at capture for touchpoint tracking
+	OptOutURL           string     `json:"opt_out_url,omitempty"` // signed unsubscribe link for emails to the lead
+}
+
+// LeadRecaptureResponse acknowledges a capture that matched an existing lead.
+// It carries none of the stored lead's data.
+type LeadRecaptureResponse struct {
+	Received bool `json:"received"`
 }
 
 // DetailedBlogLeadResponse represents a detailed blog lead response
@@ -261,28 +336,78 @@ type LeadQualificationRequest struct {
 
 // LeadStatusUpdateRequest represents a request to update lead status
 type LeadStatusUpdateRequest struct {
-	Status       string                 `json:"status" binding:"required"`
-	Notes        string                 `json:"notes"`
-	CustomFields map[string]interface{} `json:"custom_fields"`
-	UpdatedBy    uint                   `json:"-"` // Set by handler from context
-	UpdatedAt    time.Time              `json:"-"` // Set by handler
+	Status          string                 `json:"status" binding:"required"`
+	Notes           string                 `json:"notes"`
+	CustomFields    map[string]interface{} `json:"custom_fields"`
+	ConversionValue *float64               `json:"conversion_value" binding:"omitempty,min=0"` // only with status converted
+	UpdatedBy       uint                   `json:"-"`                                          // Set by handler from context
+	UpdatedAt       time.Time              `json:"-"`                                          // Set by handler
+}
+
+// LeadMergeRequest represents a request to merge a duplicate lead into another lead
+type LeadMergeRequest struct {
+	MergeLeadID uint   `json:"merge_lead_id" binding:"required"` // duplicate folded into the lead in the URL
+	Notes       string `json:"notes"`
+}
+
+// TouchpointRequest represents a touchpoint recorded for a known lead. The lead
+// is identified by lead_id (authenticated integrations) or by the lead_token
+// returned at capture (tracking scripts).
+type TouchpointRequest struct {
+	LeadID         uint       `json:"lead_id"`
+	LeadToken      string     `json:"lead_token" binding:"max=255"`
+	TouchpointType string     `json:"touchpoint_type" binding:"required,max=50"`
+	BlogID         *uint      `json:"blog_id"`
+	URL            string     `json:"url" binding:"max=1000"`
+	Title          string     `json:"title" binding:"max=500"`
+	Source         string     `json:"source" binding:"max=100"`
+	Medium         string     `json:"medium" binding:"max=100"`
+	Campaign       string     `json:"campaign" binding:"max=100"`
+	TimeSpent      int        `json:"time_spent" binding:"min=0"` // seconds
+	ScrollDepth    float64    `json:"scroll_depth" binding:"min=0,max=100"`
+	Interactions   int        `json:"interactions" binding:"min=0"`
+	OccurredAt     *time.Time `json:"occurred_at"` // defaults to now
+}
+
+// LeadDataSubjectRequest identifies the person behind a data subject export
+// or erasure, by email or by one of their leads
+type LeadDataSubjectRequest struct {
+	Email   string `json:"email" binding:"omitempty,email,max=255"`
+	LeadID  uint   `json:"lead_id"`
+	Confirm bool   `json:"confirm"` // erasure only; must be true
+	Reason  string `json:"reason" binding:"max=500"`
+}
+
+// LeadDataExport is everything stored about a data subject
+type LeadDataExport struct {
+	Email         string         `json:"email"`
+	ExportedAt    time.Time      `json:"exported_at"`
+	Leads         []BlogLead     `json:"leads"`
+	VisitorEvents []VisitorEvent `json:"visitor_events"`
+	Merges        []LeadMerge    `json:"merges"`
+}
+
+// TouchpointBatchRequest represents several touchpoints recorded at once
+type TouchpointBatchRequest struct {
+	Touchpoints []TouchpointRequest `json:"touchpoints" binding:"required,min=1,max=100,dive"`
 }
 
 // BlogLeadFilters represents filters for lead queries
 type BlogLeadFilters struct {
-	BlogID     uint       `json:"blog_id"`
-	Status     string     `json:"status"`
-	SourceType string     `json:"source_type"`
-	AssignedTo *uint      `json:"assigned_to"`
-	StartDate  *time.Time `json:"start_date"`
-	EndDate    *time.Time `json:"end_date"`
-	Tags       []string   `json:"tags"`
-	MinScore   *int       `json:"min_score"`
-	MaxScore   *int       `json:"max_score"`
-	Sort       string     `json:"sort"`
-	Order      string     `json:"order"`
-	Page       int        `json:"page"`
-	Limit      int        `json:"limit"`
+	BlogID      uint       `json:"blog_id"`
+	Status      string     `json:"status"`
+	SourceType  string     `json:"source_type"`
+	AssignedTo  *uint      `json:"assigned_to"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	Tags        []string   `json:"tags"`
+	MinScore    *int       `json:"min_score"`
+	MaxScore    *int       `json:"max_score"`
+	Quarantined bool       `json:"quarantined"`
+	Sort        string     `json:"sort"`
+	Order       string     `json:"order"`
+	Page        int        `json:"page"`
+	Limit       int        `json:"limit"`
 }
 
 // PaginatedBlogLeadsResponse represents paginated blog leads response
@@ -472,6 +597,26 @@ type LeadPredictions struct {
 	PredictionConfidence float64               `json:"prediction_confidence"`
 	TrendPredictions     []LeadTrendPrediction `json:"trend_predictions"`
 	RecommendedActions   []string              `json:"recommended_actions"`
+	Method               string                `json:"method"` // holt_winters, holt or insufficient_data
+	Horizons             []LeadForecastHorizon `json:"horizons"`
+	Backtest             *LeadForecastBacktest `json:"backtest,omitempty"`
+}
+
+// LeadForecastHorizon represents the leads and revenue predicted over the next days
+type LeadForecastHorizon struct {
+	Days             int             `json:"days"`
+	PredictedLeads   int             `json:"predicted_leads"`
+	PredictedRevenue float64         `json:"predicted_revenue"`
+	LeadRange        PredictionRange `json:"lead_range"`
+	RevenueRange     PredictionRange `json:"revenue_range"`
+}
+
+// LeadForecastBacktest represents the accuracy of the lead forecast on held-out days
+type LeadForecastBacktest struct {
+	HoldoutDays int     `json:"holdout_days"`
+	MAPE        float64 `json:"mape"`     // mean absolute percentage error
+	MAE         float64 `json:"mae"`      // mean absolute error, in leads per day
+	Coverage    float64 `json:"coverage"` // percentage of held-out days inside the prediction interval
 }
 
 // LeadTrendPrediction represents trend predictions
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+)
+
+// BlogSearchResult represents a ranked full-text search hit.
+// Title and Snippet are HTML-escaped with matches wrapped in <mark> tags.
+type BlogSearchResult struct {
+	ID            uint       `json:"id"`
+	Title         string     `json:"title"`
+	Slug          string     `json:"slug"`
+	Excerpt       string     `json:"excerpt"`
+	Status        string     `json:"status"`
+	AuthorID      uint       `json:"author_id"`
+	CategoryID    *uint      `json:"category_id"`
+	FeaturedImage string     `json:"featured_image"`
+	PublishedAt   *time.Time `json:"published_at"`
+	Tags          []Tag      `json:"tags"`
+	Relevance     float64    `json:"relevance"`
+	Highlight     string     `json:"highlight"`
+	Snippet       string     `json:"snippet"`
+}
+
+// SearchFacet represents the number of matches sharing a value
+type SearchFacet struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// BlogSearchFacets represents match counts grouped by category, author and status
+type BlogSearchFacets struct {
+	Categories []SearchFacet `json:"categories"`
+	Authors    []SearchFacet `json:"authors"`
+	Statuses   []SearchFacet `json:"statuses"`
+}
+
+// BlogSearchResponse represents a paginated full-text search response
+type BlogSearchResponse struct {
+	Query      string             `json:"query"`
+	Mode       string             `json:"mode"`
+	Results    []BlogSearchResult `json:"results"`
+	Facets     BlogSearchFacets   `json:"facets"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"total_pages"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"blog-service/pkg/seo"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SEO analysis triggers
+const (
+	SEOTriggerManual         = "manual"
+	SEOTriggerCreated        = "created"
+	SEOTriggerContentChanged = "content_changed"
+)
+
+// BlogSEOAnalysis stores a point-in-time SEO analysis of a blog post
+type BlogSEOAnalysis struct {
+	ID            uint             `json:"id" gorm:"primaryKey"`
+	BlogID        uint             `json:"blog_id" gorm:"not null;index:idx_seo_analyses_blog_created,priority:1"`
+	OverallScore  int              `json:"overall_score"`
+	ScoreChange   int              `json:"score_change"` // difference from the previous analysis of the same blog
+	WordCount     int              `json:"word_count"`
+	Trigger       string           `json:"trigger" gorm:"column:trigger_type;size:30"` // manual, created, content_changed
+	ChangedFields JSONArray        `json:"changed_fields" gorm:"type:json"`
+	TriggeredBy   *uint            `json:"triggered_by"`
+	Analysis      *SEOAnalysisJSON `json:"analysis,omitempty" gorm:"type:json"`
+	CreatedAt     time.Time        `json:"created_at" gorm:"index:idx_seo_analyses_blog_created,priority:2"`
+}
+
+// TableName specifies the table name for BlogSEOAnalysis
+func (BlogSEOAnalysis) TableName() string {
+	return "seo_analyses"
+}
+
+// SEOAnalysisJSON stores a full seo.SEOAnalysis as JSON
+type SEOAnalysisJSON seo.SEOAnalysis
+
+// Value implements the driver.Valuer interface for database storage
+func (a SEOAnalysisJSON) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (a *SEOAnalysisJSON) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into SEOAnalysisJSON", value)
+	}
+
+	return json.Unmarshal(bytes, a)
+}
+
+// SEOScoreHistoryResponse represents how a blog's SEO score moved over time
+type SEOScoreHistoryResponse struct {
+	BlogID       uint              `json:"blog_id"`
+	CurrentScore float64           `json:"current_score"`
+	Analyses     []BlogSEOAnalysis `json:"analyses"`
+}
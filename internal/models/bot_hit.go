@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// BotHit is a request to a tracking endpoint classified as crawler, monitor or
+// other automated traffic. Bot hits are not counted in engagement counters or
+// visitor history and are kept here for crawl analysis.
+type BotHit struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Route     string    `json:"route" gorm:"size:100;not null"` // e.g. /api/v1/pixel.gif
+	Path      string    `json:"path" gorm:"size:1000"`          // request path and query
+	BlogID    *uint     `json:"blog_id" gorm:"index"`
+	BotName   string    `json:"bot_name" gorm:"size:100;index"`
+	Reason    string    `json:"reason" gorm:"size:30;index"` // user_agent, headless, rate, monitoring_ip
+	UserAgent string    `json:"user_agent" gorm:"size:500"`
+	IPAddress string    `json:"ip_address" gorm:"size:45"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName specifies the table name for BotHit
+func (BotHit) TableName() string {
+	return "bot_hits"
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// BlogEngagementDaily holds a blog's deduplicated engagement counters for one
+// UTC day, written in batches from the pixel and beacon endpoints. The lifetime
+// totals are also added to the blog's view, like and share counts.
+type BlogEngagementDaily struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	BlogID    uint      `json:"blog_id" gorm:"not null;uniqueIndex:idx_blog_engagement_day,priority:1"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_blog_engagement_day,priority:2;index"`
+	Views     int       `json:"views" gorm:"default:0"`
+	Likes     int       `json:"likes" gorm:"default:0"`
+	Shares    int       `json:"shares" gorm:"default:0"`
+	Scroll25  int       `json:"scroll_25" gorm:"column:scroll_25;default:0"` // sessions reaching 25% of the post
+	Scroll50  int       `json:"scroll_50" gorm:"column:scroll_50;default:0"`
+	Scroll75  int       `json:"scroll_75" gorm:"column:scroll_75;default:0"`
+	Scroll100 int       `json:"scroll_100" gorm:"column:scroll_100;default:0"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for BlogEngagementDaily
+func (BlogEngagementDaily) TableName() string {
+	return "blog_engagement_daily"
+}
+
+// BlogLike records that a visitor liked a blog post, so a like counts once
+// per visitor across restarts and instances
+type BlogLike struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	BlogID    uint      `json:"blog_id" gorm:"not null;uniqueIndex:idx_blog_like_visitor,priority:1"`
+	VisitorID string    `json:"visitor_id" gorm:"size:64;not null;uniqueIndex:idx_blog_like_visitor,priority:2"` // visitor cookie, else a client fingerprint
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for BlogLike
+func (BlogLike) TableName() string {
+	return "blog_likes"
+}
+
+// Request/Response Models
+
+// BeaconRequest represents engagement events sent with navigator.sendBeacon
+type BeaconRequest struct {
+	BlogID    uint          `json:"blog_id" binding:"required"`
+	SessionID string        `json:"session_id" binding:"max=64"`
+	Events    []BeaconEvent `json:"events" binding:"required,min=1,max=20,dive"`
+}
+
+// BeaconEvent represents one engagement event in a beacon
+type BeaconEvent struct {
+	Type    string  `json:"type" binding:"required,oneof=view scroll like share"`
+	Depth   float64 `json:"depth" binding:"min=0,max=100"` // scroll depth in percent, for scroll events
+	Network string  `json:"network" binding:"max=50"`      // share target, for share events
+}
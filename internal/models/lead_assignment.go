@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+)
+
+// LeadAssignmentRule routes new or qualified leads to an owner. Active rules
+// are evaluated by ascending priority; the first rule that matches and has an
+// active target assigns the lead. A rule targets either one user or a
+// round-robin pool.
+type LeadAssignmentRule struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	Name     string `json:"name" gorm:"not null;size:100"`
+	Priority int    `json:"priority" gorm:"default:0;index"` // lower runs first
+	Active   bool   `json:"active" gorm:"default:true;index"`
+
+	// Match criteria, empty criteria match every lead
+	CategoryID        *uint  `json:"category_id" gorm:"index"` // includes subcategories
+	Country           string `json:"country" gorm:"size:100"`
+	AutoQualification string `json:"auto_qualification" gorm:"size:50"`
+	MinScore          *int   `json:"min_score"`
+	MaxScore          *int   `json:"max_score"`
+	UTMCampaign       string `json:"utm_campaign" gorm:"size:100"`
+
+	// Target
+	AssignToUserID     *uint     `json:"assign_to_user_id"`
+	UserPool           JSONArray `json:"user_pool" gorm:"type:json"` // admin user IDs in round-robin order
+	LastAssignedUserID *uint     `json:"last_assigned_user_id"`
+
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships. admin_users belongs to the CRM, so no foreign keys are migrated for it.
+	Category     *Category  `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	AssignToUser *AdminUser `json:"assign_to_user,omitempty" gorm:"foreignKey:AssignToUserID;-:migration"`
+}
+
+// TableName specifies the table name for LeadAssignmentRule
+func (LeadAssignmentRule) TableName() string {
+	return "lead_assignment_rules"
+}
+
+// PoolUserIDs returns the round-robin pool as user IDs
+func (r *LeadAssignmentRule) PoolUserIDs() []uint {
+	ids := make([]uint, 0, len(r.UserPool))
+	for _, value := range r.UserPool {
+		if id, ok := value.(float64); ok && id > 0 {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+// Request/Response Models
+
+// LeadAssignmentRuleRequest represents a request to create or replace an
+// assignment rule. Exactly one of AssignToUserID and UserPool must be set.
+type LeadAssignmentRuleRequest struct {
+	Name              string `json:"name" binding:"required,max=100"`
+	Priority          int    `json:"priority"`
+	Active            *bool  `json:"active"` // defaults to true
+	CategoryID        *uint  `json:"category_id"`
+	Country           string `json:"country" binding:"max=100"`
+	AutoQualification string `json:"auto_qualification" binding:"omitempty,oneof=hot warm cold unqualified"`
+	MinScore          *int   `json:"min_score" binding:"omitempty,min=0,max=100"`
+	MaxScore          *int   `json:"max_score" binding:"omitempty,min=0,max=100"`
+	UTMCampaign       string `json:"utm_campaign" binding:"max=100"`
+	AssignToUserID    *uint  `json:"assign_to_user_id"`
+	UserPool          []uint `json:"user_pool"`
+}
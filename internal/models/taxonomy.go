@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+)
+
+// Category represents a hierarchical blog category
+type Category struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"not null;size:100"`
+	Slug        string    `json:"slug" gorm:"uniqueIndex;not null;size:255"`
+	Description string    `json:"description" gorm:"type:text"`
+	ParentID    *uint     `json:"parent_id" gorm:"index"`
+	Path        string    `json:"path" gorm:"not null;size:255;index"` // ancestor IDs including self, e.g. /1/4/
+	Depth       int       `json:"depth" gorm:"default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	BlogCount int64 `json:"blog_count" gorm:"->;-:migration"`
+}
+
+// TableName specifies the table name for Category
+func (Category) TableName() string {
+	return "categories"
+}
+
+// Tag represents a free-form blog tag
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null;size:100"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex;not null;size:255"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	BlogCount int64 `json:"blog_count" gorm:"->;-:migration"`
+}
+
+// TableName specifies the table name for Tag
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// Request/Response Models
+
+// CreateCategoryRequest represents a request to create a category
+type CreateCategoryRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Slug        string `json:"slug" binding:"max=255"` // generated from Name when empty
+	Description string `json:"description"`
+	ParentID    *uint  `json:"parent_id"`
+}
+
+// UpdateCategoryRequest represents a partial update of a category.
+// ParentID 0 moves the category to the top level.
+type UpdateCategoryRequest struct {
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	Slug        *string `json:"slug" binding:"omitempty,max=255"`
+	Description *string `json:"description"`
+	ParentID    *uint   `json:"parent_id"`
+}
+
+// CategoryTreeNode represents a category with its nested children
+type CategoryTreeNode struct {
+	Category
+	Children []*CategoryTreeNode `json:"children"`
+}
+
+// TagRequest represents a request to create or rename a tag
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	Slug string `json:"slug" binding:"max=255"` // generated from Name when empty
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// VisitorEvent is a page view or engagement report from an anonymous visitor,
+// identified by a first-party cookie. When the visitor submits a lead form the
+// events are stitched into the lead's touchpoints and LeadID is set.
+type VisitorEvent struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	VisitorID    string    `json:"visitor_id" gorm:"size:64;not null;index:idx_visitor_events_visitor,priority:1"`
+	SessionID    string    `json:"session_id" gorm:"size:64"`
+	EventType    string    `json:"event_type" gorm:"size:50;not null"` // page_view, engagement
+	BlogID       *uint     `json:"blog_id" gorm:"index"`
+	URL          string    `json:"url" gorm:"size:1000"`
+	Title        string    `json:"title" gorm:"size:500"`
+	Referrer     string    `json:"referrer" gorm:"size:1000"`
+	UTMSource    string    `json:"utm_source" gorm:"size:100"`
+	UTMMedium    string    `json:"utm_medium" gorm:"size:100"`
+	UTMCampaign  string    `json:"utm_campaign" gorm:"size:100"`
+	TimeSpent    int       `json:"time_spent"` // seconds
+	ScrollDepth  float64   `json:"scroll_depth"`
+	Interactions int       `json:"interactions"`
+	LeadID       *uint     `json:"lead_id" gorm:"index"` // set once stitched into a lead
+	CreatedAt    time.Time `json:"created_at" gorm:"index:idx_visitor_events_visitor,priority:2"`
+}
+
+// TableName specifies the table name for VisitorEvent
+func (VisitorEvent) TableName() string {
+	return "visitor_events"
+}
+
+// Request/Response Models
+
+// TrackEventRequest represents an anonymous page view or engagement report
+type TrackEventRequest struct {
+	EventType    string  `json:"event_type" binding:"required,oneof=page_view engagement"`
+	SessionID    string  `json:"session_id" binding:"max=64"`
+	BlogID       *uint   `json:"blog_id"`
+	URL          string  `json:"url" binding:"required,max=1000"`
+	Title        string  `json:"title" binding:"max=500"`
+	Referrer     string  `json:"referrer" binding:"max=1000"`
+	UTMSource    string  `json:"utm_source" binding:"max=100"`
+	UTMMedium    string  `json:"utm_medium" binding:"max=100"`
+	UTMCampaign  string  `json:"utm_campaign" binding:"max=100"`
+	TimeSpent    int     `json:"time_spent" binding:"min=0,max=86400"` // seconds
+	ScrollDepth  float64 `json:"scroll_depth" binding:"min=0,max=100"`
+	Interactions int     `json:"interactions" binding:"min=0,max=10000"`
+}
@@ -0,0 +1,149 @@
+package workers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/botdetect"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// botHitInsertBatch is the number of bot hits written per statement
+const botHitInsertBatch = 500
+
+// BotHitFlusher periodically writes buffered bot hits to bot_hits and, once a
+// day, deletes hits older than the retention period
+type BotHitFlusher struct {
+	hits      *botdetect.HitLog
+	interval  time.Duration
+	retention time.Duration
+	lastPurge time.Time
+	stop      chan struct{}
+	done      chan struct{}
+	once      sync.Once
+}
+
+// NewBotHitFlusher creates a new bot hit flusher for hits. A retention of zero
+// keeps hits forever.
+func NewBotHitFlusher(hits *botdetect.HitLog, interval, retention time.Duration) *BotHitFlusher {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &BotHitFlusher{
+		hits:      hits,
+		interval:  interval,
+		retention: retention,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start runs the flush loop in the background
+func (f *BotHitFlusher) Start() {
+	go func() {
+		defer close(f.done)
+
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+
+		logger.Info("Bot hit flusher started", map[string]interface{}{
+			"interval":  f.interval.String(),
+			"retention": f.retention.String(),
+		})
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-f.stop:
+				if _, err := f.Flush(); err != nil {
+					logger.Error("Final bot hit flush failed", err, nil)
+				}
+				logger.Info("Bot hit flusher stopped", nil)
+				return
+			}
+
+			if _, err := f.Flush(); err != nil {
+				logger.Error("Bot hit flush failed", err, nil)
+			}
+			if err := f.purge(time.Now()); err != nil {
+				logger.Error("Bot hit purge failed", err, nil)
+			}
+		}
+	}()
+}
+
+// Stop flushes what is buffered, signals the flush loop to exit and waits for it
+func (f *BotHitFlusher) Stop() {
+	f.once.Do(func() {
+		close(f.stop)
+	})
+	<-f.done
+}
+
+// Flush writes the buffered bot hits and returns how many were written. Bot
+// hits are analytics data, so a failed write is logged and not retried.
+func (f *BotHitFlusher) Flush() (int, error) {
+	hits, dropped := f.hits.Drain()
+	if dropped > 0 {
+		logger.Warn("Bot hit log full, hits dropped", map[string]interface{}{"hits": dropped})
+	}
+	if len(hits) == 0 {
+		return 0, nil
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		return 0, fmt.Errorf("database not initialized")
+	}
+
+	rows := make([]models.BotHit, 0, len(hits))
+	for _, hit := range hits {
+		rows = append(rows, models.BotHit{
+			Route:     hit.Route,
+			Path:      truncate(hit.Path, 1000),
+			BlogID:    hit.BlogID,
+			BotName:   hit.Name,
+			Reason:    hit.Reason,
+			UserAgent: truncate(hit.UserAgent, 500),
+			IPAddress: hit.IP,
+			CreatedAt: hit.At,
+		})
+	}
+	if err := db.CreateInBatches(&rows, botHitInsertBatch).Error; err != nil {
+		return 0, fmt.Errorf("failed to write %d bot hits: %v", len(rows), err)
+	}
+
+	return len(rows), nil
+}
+
+// purge deletes bot hits older than the retention period, at most once a day
+func (f *BotHitFlusher) purge(now time.Time) error {
+	if f.retention <= 0 || now.Sub(f.lastPurge) < 24*time.Hour {
+		return nil
+	}
+	db := database.GetDB()
+	if db == nil {
+		return nil
+	}
+
+	result := db.Where("created_at < ?", now.Add(-f.retention)).Delete(&models.BotHit{})
+	if result.Error != nil {
+		return result.Error
+	}
+	f.lastPurge = now
+	if result.RowsAffected > 0 {
+		logger.Info("Purged old bot hits", map[string]interface{}{"deleted": result.RowsAffected})
+	}
+	return nil
+}
+
+// truncate shortens s to at most n bytes without splitting a character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
@@ -0,0 +1,210 @@
+package workers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/engagement"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// engagementUpsertBatch is the number of daily rows written per statement
+const engagementUpsertBatch = 500
+
+// EngagementFlusher periodically writes buffered engagement counters to the
+// database: daily rows in blog_engagement_daily and lifetime totals on blogs
+type EngagementFlusher struct {
+	buffer   *engagement.Buffer
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewEngagementFlusher creates a new engagement flusher for buffer
+func NewEngagementFlusher(buffer *engagement.Buffer, interval time.Duration) *EngagementFlusher {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return &EngagementFlusher{
+		buffer:   buffer,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the flush loop in the background. The buffer is flushed every
+// interval, and early when it signals that it is filling up.
+func (f *EngagementFlusher) Start() {
+	go func() {
+		defer close(f.done)
+
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+
+		logger.Info("Engagement flusher started", map[string]interface{}{
+			"interval": f.interval.String(),
+		})
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-f.buffer.Ready():
+			case <-f.stop:
+				if _, err := f.Flush(); err != nil {
+					logger.Error("Final engagement flush failed", err, nil)
+				}
+				logger.Info("Engagement flusher stopped", nil)
+				return
+			}
+
+			if _, err := f.Flush(); err != nil {
+				logger.Error("Engagement flush failed", err, nil)
+			}
+		}
+	}()
+}
+
+// Stop flushes what is buffered, signals the flush loop to exit and waits for it
+func (f *EngagementFlusher) Stop() {
+	f.once.Do(func() {
+		close(f.stop)
+	})
+	<-f.done
+}
+
+// Flush writes the buffered counters in one transaction and returns the number
+// of blog days written. Counters for blogs that are not published are
+// discarded. On failure the counters are put back for the next flush.
+func (f *EngagementFlusher) Flush() (int, error) {
+	pending, dropped := f.buffer.Drain()
+	if dropped > 0 {
+		logger.Warn("Engagement buffer full, counters dropped", map[string]interface{}{"blog_days": dropped})
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		f.buffer.Restore(pending)
+		return 0, fmt.Errorf("database not initialized")
+	}
+
+	blogIDs := make([]uint, 0, len(pending))
+	seen := map[uint]bool{}
+	for key := range pending {
+		if !seen[key.BlogID] {
+			seen[key.BlogID] = true
+			blogIDs = append(blogIDs, key.BlogID)
+		}
+	}
+
+	var published []uint
+	if err := db.Model(&models.Blog{}).
+		Where("id IN ? AND status = ?", blogIDs, workflow.StatusPublished).
+		Pluck("id", &published).Error; err != nil {
+		f.buffer.Restore(pending)
+		return 0, fmt.Errorf("failed to load blogs: %v", err)
+	}
+	// Locking blogs in ID order keeps concurrent flushes from deadlocking
+	sort.Slice(published, func(i, j int) bool { return published[i] < published[j] })
+	isPublished := make(map[uint]bool, len(published))
+	for _, id := range published {
+		isPublished[id] = true
+	}
+
+	rows := []models.BlogEngagementDaily{}
+	totals := map[uint]*engagement.Counts{}
+	for key, counts := range pending {
+		if !isPublished[key.BlogID] {
+			continue
+		}
+		day, err := time.Parse("2006-01-02", key.Day)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, models.BlogEngagementDaily{
+			BlogID:    key.BlogID,
+			Date:      day,
+			Views:     counts.Views,
+			Likes:     counts.Likes,
+			Shares:    counts.Shares,
+			Scroll25:  counts.Scroll[0],
+			Scroll50:  counts.Scroll[1],
+			Scroll75:  counts.Scroll[2],
+			Scroll100: counts.Scroll[3],
+		})
+
+		total, ok := totals[key.BlogID]
+		if !ok {
+			total = &engagement.Counts{}
+			totals[key.BlogID] = total
+		}
+		total.Views += counts.Views
+		total.Likes += counts.Likes
+		total.Shares += counts.Shares
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].BlogID != rows[j].BlogID {
+			return rows[i].BlogID < rows[j].BlogID
+		}
+		return rows[i].Date.Before(rows[j].Date)
+	})
+
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "blog_id"}, {Name: "date"}},
+			DoUpdates: incrementColumns("views", "likes", "shares", "scroll_25", "scroll_50", "scroll_75", "scroll_100", "updated_at"),
+		}).CreateInBatches(&rows, engagementUpsertBatch).Error; err != nil {
+			return err
+		}
+
+		// Counters are derived data, so they do not bump updated_at
+		for _, blogID := range published {
+			total := totals[blogID]
+			if total == nil || (total.Views == 0 && total.Likes == 0 && total.Shares == 0) {
+				continue
+			}
+			if err := tx.Model(&models.Blog{}).Where("id = ?", blogID).UpdateColumns(map[string]interface{}{
+				"views_count":  gorm.Expr("views_count + ?", total.Views),
+				"likes_count":  gorm.Expr("likes_count + ?", total.Likes),
+				"shares_count": gorm.Expr("shares_count + ?", total.Shares),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		f.buffer.Restore(pending)
+		return 0, err
+	}
+
+	return len(rows), nil
+}
+
+// incrementColumns builds ON DUPLICATE KEY assignments adding the inserted
+// values to the stored ones; updated_at takes the inserted value
+func incrementColumns(columns ...string) clause.Set {
+	set := make(clause.Set, 0, len(columns))
+	for _, column := range columns {
+		value := gorm.Expr(fmt.Sprintf("%s + VALUES(%s)", column, column))
+		if column == "updated_at" {
+			value = gorm.Expr("VALUES(updated_at)")
+		}
+		set = append(set, clause.Assignment{Column: clause.Column{Name: column}, Value: value})
+	}
+	return set
+}
@@ -0,0 +1,387 @@
+package workers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/engagement"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/tracking"
+	"blog-service/pkg/workflow"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// rollupInsertBatch is the number of rollup rows written per statement
+	rollupInsertBatch = 500
+
+	// rollupSlack re-reads changes this long before the previous run so rows
+	// committed by transactions that were open at the time are not missed
+	rollupSlack = time.Minute
+
+	// searchReferrerPattern matches referrers from search engines, counted as organic traffic
+	searchReferrerPattern = `(^|[/.])(google|bing|duckduckgo|yahoo|baidu|yandex|ecosia)\.`
+)
+
+// MetricsRollup periodically rolls engagement counters, visitor events,
+// touchpoints and leads up into daily blog metrics.
+// Each run rebuilds the days touched by rows changed since the previous run,
+// and today. Rebuilding a day replaces its rows, so runs can overlap and be
+// repeated safely.
+type MetricsRollup struct {
+	interval time.Duration
+	mu       sync.Mutex // serializes rebuilds between the loop and backfills
+	since    time.Time  // start of the previous run
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewMetricsRollup creates a new metrics rollup worker
+func NewMetricsRollup(interval time.Duration) *MetricsRollup {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	return &MetricsRollup{
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the rollup loop in the background
+func (r *MetricsRollup) Start() {
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		logger.Info("Metrics rollup started", map[string]interface{}{
+			"interval": r.interval.String(),
+		})
+
+		for {
+			if _, err := r.RollUp(time.Now().UTC()); err != nil {
+				logger.Error("Metrics rollup failed", err, nil)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-r.stop:
+				logger.Info("Metrics rollup stopped", nil)
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the rollup loop to exit and waits for it
+func (r *MetricsRollup) Stop() {
+	r.once.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+}
+
+// RollUp rebuilds the days with changes since the previous run and returns
+// the number of days rebuilt. The first run after startup resumes from the
+// latest rollup in the database, or rolls up all history when there is none.
+func (r *MetricsRollup) RollUp(now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	db := database.GetDB()
+	if db == nil {
+		return 0, fmt.Errorf("database not initialized")
+	}
+
+	since := r.since
+	if since.IsZero() {
+		var latest sql.NullTime
+		if err := db.Model(&models.BlogDailyMetrics{}).Select("MAX(rolled_up_at)").Row().Scan(&latest); err != nil {
+			return 0, fmt.Errorf("failed to load latest rollup: %v", err)
+		}
+		if latest.Valid {
+			since = latest.Time
+		}
+	}
+
+	var days []time.Time
+	var err error
+	if since.IsZero() {
+		days, err = historyDays(db, now)
+	} else {
+		days, err = changedDays(db, since.Add(-rollupSlack), now)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for _, day := range days {
+		if _, err := rollUpDay(db, day, now); err != nil {
+			return 0, fmt.Errorf("failed to roll up %s: %v", day.Format("2006-01-02"), err)
+		}
+	}
+	r.since = now
+
+	if len(days) > 1 {
+		logger.Info("Metrics rolled up", map[string]interface{}{
+			"days":  len(days),
+			"from":  days[0].Format("2006-01-02"),
+			"until": days[len(days)-1].Format("2006-01-02"),
+		})
+	}
+	return len(days), nil
+}
+
+// Backfill rebuilds every day from start to end and returns the number of
+// days and blog rows written
+func (r *MetricsRollup) Backfill(start, end time.Time) (int, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	db := database.GetDB()
+	if db == nil {
+		return 0, 0, fmt.Errorf("database not initialized")
+	}
+
+	now := time.Now().UTC()
+	days, rows := 0, 0
+	for day := utcDay(start); !day.After(end); day = day.AddDate(0, 0, 1) {
+		written, err := rollUpDay(db, day, now)
+		if err != nil {
+			return days, rows, fmt.Errorf("failed to roll up %s: %v", day.Format("2006-01-02"), err)
+		}
+		days++
+		rows += written
+	}
+	return days, rows, nil
+}
+
+// utcDay returns the start of t's UTC day
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// changedDays returns the days of engagement counters, visitor events,
+// touchpoints, lead captures and conversions written since since, and today.
+// Touchpoints are dated when they happened, which can be long before they are
+// stored (backdated or stitched), so they are found by updated_at.
+func changedDays(db *gorm.DB, since, now time.Time) ([]time.Time, error) {
+	sources := []struct {
+		query  *gorm.DB
+		column string
+	}{
+		{db.Table("blog_engagement_daily").Where("updated_at >= ?", since), "date"},
+		{db.Table("visitor_events").Where("created_at >= ? AND blog_id IS NOT NULL", since), "DATE(created_at)"},
+		{db.Table("lead_touchpoints").Where("updated_at >= ? AND blog_id IS NOT NULL", since), "DATE(created_at)"},
+		// A touchpoint's revenue share counts on its lead's conversion day
+		{db.Table("lead_touchpoints").
+			Joins("JOIN blog_leads ON blog_leads.id = lead_touchpoints.lead_id").
+			Where("lead_touchpoints.updated_at >= ? AND blog_leads.converted_at IS NOT NULL", since), "DATE(blog_leads.converted_at)"},
+		// Captures, conversions, merges, quarantines and re-attribution all touch updated_at
+		{db.Table("blog_leads").Where("updated_at >= ?", since), "DATE(captured_at)"},
+		{db.Table("blog_leads").Where("updated_at >= ? AND converted_at IS NOT NULL", since), "DATE(converted_at)"},
+	}
+
+	seen := map[time.Time]bool{utcDay(now): true}
+	for _, source := range sources {
+		var days []time.Time
+		if err := source.query.Distinct().Pluck(source.column, &days).Error; err != nil {
+			return nil, fmt.Errorf("failed to find changed days: %v", err)
+		}
+		for _, day := range days {
+			seen[utcDay(day)] = true
+		}
+	}
+	return sortedDays(seen, now), nil
+}
+
+// historyDays returns every day from the earliest engagement counter, visitor
+// event or lead up to today
+func historyDays(db *gorm.DB, now time.Time) ([]time.Time, error) {
+	first := utcDay(now)
+	for _, source := range []struct{ table, column string }{
+		{"blog_engagement_daily", "date"},
+		{"visitor_events", "created_at"},
+		{"blog_leads", "captured_at"},
+	} {
+		var earliest sql.NullTime
+		if err := db.Table(source.table).Select("MIN(" + source.column + ")").Row().Scan(&earliest); err != nil {
+			return nil, fmt.Errorf("failed to find earliest %s: %v", source.table, err)
+		}
+		if earliest.Valid && earliest.Time.Before(first) {
+			first = utcDay(earliest.Time)
+		}
+	}
+
+	seen := map[time.Time]bool{}
+	for day := first; !day.After(now); day = day.AddDate(0, 0, 1) {
+		seen[day] = true
+	}
+	return sortedDays(seen, now), nil
+}
+
+// sortedDays returns the days in set up to now in order
+func sortedDays(set map[time.Time]bool, now time.Time) []time.Time {
+	days := make([]time.Time, 0, len(set))
+	for day := range set {
+		if !day.After(now) {
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// rollUpDay replaces the blog rollups of one day in a single transaction and
+// returns the number of rows written. Category and author breakdowns group
+// these rows by each post's current category and author when read.
+func rollUpDay(db *gorm.DB, day, now time.Time) (int, error) {
+	blogRows, err := blogDayMetrics(db, day, now)
+	if err != nil {
+		return 0, err
+	}
+
+	err = database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("date = ?", day.Format("2006-01-02")).Delete(&models.BlogDailyMetrics{}).Error; err != nil {
+			return err
+		}
+		if len(blogRows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&blogRows, rollupInsertBatch).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(blogRows), nil
+}
+
+// blogDayMetrics aggregates each blog's activity on day. Leads exclude merged
+// duplicates and leads quarantined for missing consent; revenue is the
+// touchpoint share of leads converted on the day.
+func blogDayMetrics(db *gorm.DB, day, now time.Time) ([]models.BlogDailyMetrics, error) {
+	next := day.AddDate(0, 0, 1)
+	metrics := map[uint]*models.BlogDailyMetrics{}
+	blog := func(id uint) *models.BlogDailyMetrics {
+		m, ok := metrics[id]
+		if !ok {
+			m = &models.BlogDailyMetrics{BlogID: id, Date: day, RolledUpAt: now}
+			metrics[id] = m
+		}
+		return m
+	}
+
+	var counters []models.BlogEngagementDaily
+	if err := db.Where("date = ?", day.Format("2006-01-02")).Find(&counters).Error; err != nil {
+		return nil, err
+	}
+	for _, c := range counters {
+		m := blog(c.BlogID)
+		m.Views, m.Likes, m.Shares = c.Views, c.Likes, c.Shares
+		m.Scroll25, m.Scroll50, m.Scroll75, m.Scroll100 = c.Scroll25, c.Scroll50, c.Scroll75, c.Scroll100
+	}
+
+	var visits []struct {
+		BlogID          uint
+		UniqueVisitors  int
+		PageViews       int
+		Sessions        int
+		EngagedVisitors int
+		OrganicViews    int
+		TimeSpent       float64
+		TimedEvents     int
+	}
+	if err := db.Table("visitor_events").
+		Select(`blog_id, COUNT(DISTINCT NULLIF(visitor_id, '')) AS unique_visitors, SUM(event_type = ?) AS page_views,
+			COUNT(DISTINCT CASE WHEN session_id <> '' THEN CONCAT(visitor_id, '|', session_id) END) AS sessions,
+			COUNT(DISTINCT CASE WHEN time_spent >= 30 THEN NULLIF(visitor_id, '') END) AS engaged_visitors,
+			SUM(event_type = ? AND referrer REGEXP ?) AS organic_views,
+			SUM(time_spent) AS time_spent, SUM(time_spent > 0) AS timed_events`,
+			tracking.EventPageView, tracking.EventPageView, searchReferrerPattern).
+		Where("blog_id IS NOT NULL AND created_at >= ? AND created_at < ?", day, next).
+		Group("blog_id").
+		Scan(&visits).Error; err != nil {
+		return nil, err
+	}
+	for _, v := range visits {
+		m := blog(v.BlogID)
+		m.UniqueVisitors, m.PageViews, m.Sessions = v.UniqueVisitors, v.PageViews, v.Sessions
+		m.EngagedVisitors, m.OrganicViews = v.EngagedVisitors, v.OrganicViews
+		m.TimeSpent, m.TimedEvents = v.TimeSpent, v.TimedEvents
+	}
+
+	var touches []struct {
+		BlogID    uint
+		CTAClicks int
+		Downloads int
+	}
+	if err := db.Table("lead_touchpoints").
+		Select("blog_id, SUM(touchpoint_type = ?) AS cta_clicks, SUM(touchpoint_type = ?) AS downloads",
+			models.TouchpointCTAClick, models.TouchpointDownload).
+		Where("blog_id IS NOT NULL AND created_at >= ? AND created_at < ?", day, next).
+		Group("blog_id").
+		Scan(&touches).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range touches {
+		m := blog(t.BlogID)
+		m.CTAClicks, m.Downloads = t.CTAClicks, t.Downloads
+	}
+
+	var leads []struct {
+		BlogID      uint
+		Leads       int
+		Conversions int
+	}
+	if err := db.Table("blog_leads").
+		Select(`blog_id, SUM(captured_at >= ? AND captured_at < ?) AS leads,
+			SUM(status = ? AND converted_at >= ? AND converted_at < ?) AS conversions`,
+			day, next, workflow.LeadStatusConverted, day, next).
+		Where("merged_into_id IS NULL AND quarantined_at IS NULL").
+		Where("(captured_at >= ? AND captured_at < ?) OR (converted_at >= ? AND converted_at < ?)", day, next, day, next).
+		Group("blog_id").
+		Scan(&leads).Error; err != nil {
+		return nil, err
+	}
+	for _, l := range leads {
+		m := blog(l.BlogID)
+		m.Leads, m.Conversions = l.Leads, l.Conversions
+	}
+
+	var revenue []struct {
+		BlogID  uint
+		Revenue float64
+	}
+	if err := db.Table("lead_touchpoints").
+		Select("lead_touchpoints.blog_id, SUM(lead_touchpoints.conversion_value) AS revenue").
+		Joins("JOIN blog_leads ON blog_leads.id = lead_touchpoints.lead_id").
+		Where("lead_touchpoints.blog_id IS NOT NULL AND blog_leads.merged_into_id IS NULL").
+		Where("blog_leads.converted_at >= ? AND blog_leads.converted_at < ?", day, next).
+		Group("lead_touchpoints.blog_id").
+		Scan(&revenue).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range revenue {
+		blog(r.BlogID).Revenue = r.Revenue
+	}
+
+	rows := make([]models.BlogDailyMetrics, 0, len(metrics))
+	for _, m := range metrics {
+		if m.TimedEvents > 0 {
+			m.AvgTimeOnPage = m.TimeSpent / float64(m.TimedEvents)
+		}
+		m.BounceRate = engagement.BounceRate(m.Views, m.Scroll25)
+		rows = append(rows, *m)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].BlogID < rows[j].BlogID })
+	return rows, nil
+}
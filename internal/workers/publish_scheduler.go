@@ -0,0 +1,146 @@
+package workers
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PublishScheduler periodically publishes blog posts whose scheduled time has passed
+type PublishScheduler struct {
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewPublishScheduler creates a new publish scheduler
+func NewPublishScheduler(interval time.Duration) *PublishScheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &PublishScheduler{
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the scheduler loop in the background
+func (s *PublishScheduler) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		logger.Info("Publish scheduler started", map[string]interface{}{
+			"interval": s.interval.String(),
+		})
+
+		for {
+			if _, err := s.PublishDue(time.Now().UTC()); err != nil {
+				logger.Error("Scheduled publishing run failed", err, nil)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				logger.Info("Publish scheduler stopped", nil)
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the scheduler loop to exit and waits for it
+func (s *PublishScheduler) Stop() {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+// PublishDue publishes every scheduled blog whose scheduled time is at or before now
+// and returns the number of posts published
+func (s *PublishScheduler) PublishDue(now time.Time) (int, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, fmt.Errorf("database not initialized")
+	}
+
+	var due []models.Blog
+	if err := db.Where("status = ? AND scheduled_at <= ?", workflow.StatusScheduled, now).
+		Order("scheduled_at ASC").
+		Find(&due).Error; err != nil {
+		return 0, fmt.Errorf("failed to load scheduled blogs: %v", err)
+	}
+
+	published := 0
+	for i := range due {
+		blog := due[i]
+		ok, err := s.publish(blog, now)
+		if err != nil {
+			logger.Error("Failed to publish scheduled blog", err, map[string]interface{}{"blog_id": blog.ID})
+			continue
+		}
+		if ok {
+			published++
+			logger.LogBusinessEvent("blog_status_changed", "blog", blog.ID, map[string]interface{}{
+				"from_status":  workflow.StatusScheduled,
+				"to_status":    workflow.StatusPublished,
+				"scheduled_at": blog.ScheduledAt,
+				"performed_by": "scheduler",
+			})
+		}
+	}
+
+	return published, nil
+}
+
+// publish moves a single blog from scheduled to published and records the transition.
+// It returns false if another instance already handled the blog.
+func (s *PublishScheduler) publish(blog models.Blog, now time.Time) (bool, error) {
+	published := false
+
+	err := database.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"status":       workflow.StatusPublished,
+			"scheduled_at": nil,
+		}
+		if blog.PublishedAt == nil {
+			publishedAt := now
+			if blog.ScheduledAt != nil {
+				publishedAt = blog.ScheduledAt.UTC()
+			}
+			updates["published_at"] = publishedAt
+		}
+
+		result := tx.Model(&models.Blog{}).
+			Where("id = ? AND status = ?", blog.ID, workflow.StatusScheduled).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		published = true
+		return tx.Create(&models.BlogStatusTransition{
+			BlogID:      blog.ID,
+			FromStatus:  workflow.StatusScheduled,
+			ToStatus:    workflow.StatusPublished,
+			Notes:       "Published automatically by scheduler",
+			ScheduledAt: blog.ScheduledAt,
+		}).Error
+	})
+
+	return published, err
+}
@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"math"
+	"time"
+)
+
+// Multi-touch attribution models
+const (
+	AttributionFirstTouch    = "first_touch"
+	AttributionLastTouch     = "last_touch"
+	AttributionLinear        = "linear"
+	AttributionTimeDecay     = "time_decay"
+	AttributionPositionBased = "position_based"
+)
+
+// AttributionModels returns the supported attribution models
+func AttributionModels() []string {
+	return []string{
+		AttributionFirstTouch,
+		AttributionLastTouch,
+		AttributionLinear,
+		AttributionTimeDecay,
+		AttributionPositionBased,
+	}
+}
+
+// IsValidAttributionModel reports whether model is a supported attribution model
+func IsValidAttributionModel(model string) bool {
+	for _, m := range AttributionModels() {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
+
+// AttributionWeights splits the credit for a conversion across touchpoints
+// ordered oldest first. Weights follow the same model rules as
+// calculateIndirectRevenue and are normalized to sum to 1. Unknown models fall
+// back to linear.
+func (rc *ROICalculator) AttributionWeights(model string, touchedAt []time.Time, convertedAt time.Time) []float64 {
+	weights := make([]float64, len(touchedAt))
+	n := len(touchedAt)
+	if n == 0 {
+		return weights
+	}
+
+	switch model {
+	case AttributionFirstTouch:
+		weights[0] = 1
+	case AttributionLastTouch:
+		weights[n-1] = 1
+	case AttributionTimeDecay:
+		for i, t := range touchedAt {
+			days := int(math.Max(0, convertedAt.Sub(t).Hours()/24))
+			weights[i] = rc.calculateTimeDecayWeight(days)
+		}
+	case AttributionPositionBased:
+		for i := range touchedAt {
+			weights[i] = rc.calculatePositionBasedWeight(i+1, n)
+		}
+	default:
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	for i := range weights {
+		weights[i] /= total
+	}
+
+	return weights
+}
@@ -0,0 +1,90 @@
+package analytics
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Content formats inferred from post titles
+const (
+	FormatHowTo      = "how-to"
+	FormatListicle   = "listicle"
+	FormatGuide      = "guide"
+	FormatCaseStudy  = "case-study"
+	FormatComparison = "comparison"
+	FormatArticle    = "article"
+)
+
+// Impact levels of an engagement driver
+const (
+	ImpactHigh   = "high"
+	ImpactMedium = "medium"
+	ImpactLow    = "low"
+)
+
+var (
+	listiclePattern   = regexp.MustCompile(`^\s*(top\s+)?\d+\s+\w`)
+	comparisonPattern = regexp.MustCompile(`\b(vs\.?|versus|compared)\b`)
+)
+
+// ContentFormat infers the format of a post from its title
+func ContentFormat(title string) string {
+	title = strings.ToLower(title)
+	switch {
+	case strings.Contains(title, "how to") || strings.HasPrefix(title, "how "):
+		return FormatHowTo
+	case listiclePattern.MatchString(title):
+		return FormatListicle
+	case strings.Contains(title, "case study"):
+		return FormatCaseStudy
+	case comparisonPattern.MatchString(title):
+		return FormatComparison
+	case strings.Contains(title, "guide") || strings.Contains(title, "tutorial"):
+		return FormatGuide
+	default:
+		return FormatArticle
+	}
+}
+
+// LengthRanges returns the word count ranges used for content length analysis
+func LengthRanges() []string {
+	return []string{"0-500", "500-1000", "1000-2000", "2000+"}
+}
+
+// LengthRange returns the word count range of a post
+func LengthRange(words int) string {
+	switch {
+	case words < 500:
+		return "0-500"
+	case words < 1000:
+		return "500-1000"
+	case words < 2000:
+		return "1000-2000"
+	default:
+		return "2000+"
+	}
+}
+
+// Lift returns how much better with performs than without, in percent
+func Lift(with, without float64) float64 {
+	if without == 0 {
+		if with == 0 {
+			return 0
+		}
+		return 100
+	}
+	return (with - without) / without * 100
+}
+
+// ImpactLevel grades the size of a lift in either direction
+func ImpactLevel(lift float64) string {
+	switch lift = math.Abs(lift); {
+	case lift >= 25:
+		return ImpactHigh
+	case lift >= 10:
+		return ImpactMedium
+	default:
+		return ImpactLow
+	}
+}
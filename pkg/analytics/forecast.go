@@ -0,0 +1,257 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Forecast methods
+const (
+	ForecastHoltWinters      = "holt_winters"      // level, trend and weekly seasonality
+	ForecastHolt             = "holt"              // level and trend
+	ForecastInsufficientData = "insufficient_data" // fewer than two points
+)
+
+// weekLength is the season length of the weekly pattern in daily data
+const weekLength = 7
+
+// forecastZ is the normal quantile of the 95% prediction intervals
+const forecastZ = 1.96
+
+// Smoothing parameters tried when fitting, for the level, trend and season
+var (
+	alphaGrid = []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+	betaGrid  = []float64{0, 0.05, 0.1, 0.2}
+	gammaGrid = []float64{0.05, 0.1, 0.2, 0.3, 0.5}
+)
+
+// Forecaster forecasts daily series with additive Holt-Winters exponential
+// smoothing. The weekly season is used when detectSeasonality finds a weekday
+// pattern and the series covers at least two weeks; otherwise the season is
+// left out. Smoothing parameters are chosen by the smallest squared one-step
+// error over the series.
+type Forecaster struct {
+	analyzer *TrendAnalyzer
+}
+
+// NewForecaster creates a new forecaster
+func NewForecaster() *Forecaster {
+	return &Forecaster{analyzer: NewTrendAnalyzer()}
+}
+
+// Forecast is a fitted model and its daily predictions
+type Forecast struct {
+	Method         string          `json:"method"`
+	Alpha          float64         `json:"alpha"`
+	Beta           float64         `json:"beta"`
+	Gamma          float64         `json:"gamma"`
+	ResidualStdDev float64         `json:"residual_std_dev"` // of the one-step errors
+	Points         []ForecastPoint `json:"points"`
+}
+
+// Total sums the predictions and bounds of the first days of the forecast,
+// counting negative values as zero. Summing the daily bounds makes the range
+// of the total conservative.
+func (f Forecast) Total(days int) (total, lower, upper float64) {
+	for _, point := range f.Points[:min(max(days, 0), len(f.Points))] {
+		total += math.Max(point.PredictedValue, 0)
+		lower += math.Max(point.LowerBound, 0)
+		upper += math.Max(point.UpperBound, 0)
+	}
+	return total, lower, upper
+}
+
+// BacktestPoint compares a held-out value with its prediction
+type BacktestPoint struct {
+	Date       time.Time `json:"date"`
+	Actual     float64   `json:"actual"`
+	Predicted  float64   `json:"predicted"`
+	LowerBound float64   `json:"lower_bound"`
+	UpperBound float64   `json:"upper_bound"`
+}
+
+// Backtest reports how a forecast fitted without the last days of a series
+// predicted them
+type Backtest struct {
+	Method         string          `json:"method"`
+	TrainingPoints int             `json:"training_points"`
+	HoldoutPoints  int             `json:"holdout_points"`
+	MAPE           float64         `json:"mape"`     // mean absolute percentage error over held-out days with a non-zero value
+	MAE            float64         `json:"mae"`      // mean absolute error
+	Coverage       float64         `json:"coverage"` // percentage of held-out values inside the prediction interval
+	Points         []BacktestPoint `json:"points"`
+}
+
+// smoothingState is the level, trend and seasonal indices (by weekday) after a fit
+type smoothingState struct {
+	level, trend float64
+	season       [weekLength]float64
+	sse          float64
+	errors       int
+}
+
+// Forecast fits the model to a daily series without gaps and predicts the
+// next days, with prediction intervals widening with the horizon. A negative
+// number of days predicts none.
+func (f *Forecaster) Forecast(data []TrendDataPoint, days int) Forecast {
+	days = max(days, 0)
+	if len(data) < 2 {
+		return Forecast{Method: ForecastInsufficientData}
+	}
+	data = sortedByDate(data)
+
+	seasonal := len(data) >= 2*weekLength && f.analyzer.detectSeasonality(data).HasSeasonality
+	forecast := Forecast{Method: ForecastHolt}
+	gammas := []float64{0}
+	if seasonal {
+		forecast.Method = ForecastHoltWinters
+		gammas = gammaGrid
+	}
+
+	initial := f.initialState(data, seasonal)
+	var best smoothingState
+	bestSSE := math.Inf(1)
+	for _, alpha := range alphaGrid {
+		for _, beta := range betaGrid {
+			for _, gamma := range gammas {
+				state := smooth(data, initial, seasonal, alpha, beta, gamma)
+				if state.sse < bestSSE {
+					best, bestSSE = state, state.sse
+					forecast.Alpha, forecast.Beta, forecast.Gamma = alpha, beta, gamma
+				}
+			}
+		}
+	}
+	if best.errors > 0 {
+		forecast.ResidualStdDev = math.Sqrt(best.sse / float64(best.errors))
+	}
+
+	// The h-step error variance of additive Holt-Winters is
+	// σ²(1 + Σ c_j²) over j < h, with c_j = α(1 + jβ) + γ when j is a whole
+	// number of seasons, else α(1 + jβ)
+	lastDate := data[len(data)-1].Date
+	variance := 1.0
+	forecast.Points = make([]ForecastPoint, days)
+	for h := 1; h <= days; h++ {
+		if j := h - 1; j > 0 {
+			c := forecast.Alpha * (1 + float64(j)*forecast.Beta)
+			if seasonal && j%weekLength == 0 {
+				c += forecast.Gamma
+			}
+			variance += c * c
+		}
+		date := lastDate.AddDate(0, 0, h)
+		predicted := best.level + float64(h)*best.trend + best.season[date.Weekday()]
+		interval := forecastZ * forecast.ResidualStdDev * math.Sqrt(variance)
+		forecast.Points[h-1] = ForecastPoint{
+			Date:               date,
+			PredictedValue:     predicted,
+			ConfidenceInterval: interval,
+			LowerBound:         predicted - interval,
+			UpperBound:         predicted + interval,
+		}
+	}
+	return forecast
+}
+
+// Backtest fits the model to all but the last holdout days of a daily series
+// and scores its predictions for them. It needs at least two training days.
+func (f *Forecaster) Backtest(data []TrendDataPoint, holdout int) Backtest {
+	data = sortedByDate(data)
+	holdout = min(max(holdout, 0), len(data))
+	training, actual := data[:len(data)-holdout], data[len(data)-holdout:]
+
+	forecast := f.Forecast(training, holdout)
+	backtest := Backtest{
+		Method:         forecast.Method,
+		TrainingPoints: len(training),
+		HoldoutPoints:  holdout,
+		Points:         []BacktestPoint{},
+	}
+	if forecast.Method == ForecastInsufficientData || holdout == 0 {
+		return backtest
+	}
+
+	var absolute, percentage float64
+	var nonZero, covered int
+	for i, point := range forecast.Points {
+		value := actual[i].Value
+		backtest.Points = append(backtest.Points, BacktestPoint{
+			Date:       actual[i].Date,
+			Actual:     value,
+			Predicted:  point.PredictedValue,
+			LowerBound: point.LowerBound,
+			UpperBound: point.UpperBound,
+		})
+		absolute += math.Abs(value - point.PredictedValue)
+		if value != 0 {
+			percentage += math.Abs((value - point.PredictedValue) / value)
+			nonZero++
+		}
+		if value >= point.LowerBound && value <= point.UpperBound {
+			covered++
+		}
+	}
+	backtest.MAE = absolute / float64(holdout)
+	if nonZero > 0 {
+		backtest.MAPE = percentage / float64(nonZero) * 100
+	}
+	backtest.Coverage = float64(covered) / float64(holdout) * 100
+	return backtest
+}
+
+// initialState starts the level at the average of the first week without
+// its season, the trend at the regression slope and the season at the
+// weekday deviations from the average
+func (f *Forecaster) initialState(data []TrendDataPoint, seasonal bool) smoothingState {
+	var state smoothingState
+	if seasonal {
+		pattern := f.analyzer.detectSeasonality(data).DayOfWeekPattern
+		var mean float64
+		for _, average := range pattern {
+			mean += average
+		}
+		mean /= float64(len(pattern))
+		for day, average := range pattern {
+			state.season[day] = average - mean
+		}
+	}
+	state.trend = f.analyzer.calculateLinearRegression(data).Slope
+
+	first := min(len(data), weekLength)
+	for _, point := range data[:first] {
+		state.level += point.Value - state.season[point.Date.Weekday()]
+	}
+	// Move the first week's average back from its middle day to the first day
+	state.level = state.level/float64(first) - state.trend*float64(first-1)/2
+	return state
+}
+
+// smooth runs the smoothing equations over the series from the initial state
+// and sums the squared one-step errors
+func smooth(data []TrendDataPoint, state smoothingState, seasonal bool, alpha, beta, gamma float64) smoothingState {
+	for i, point := range data {
+		day := point.Date.Weekday()
+		if i > 0 {
+			predicted := state.level + state.trend + state.season[day]
+			state.sse += math.Pow(point.Value-predicted, 2)
+			state.errors++
+
+			previous := state.level
+			state.level = alpha*(point.Value-state.season[day]) + (1-alpha)*(state.level+state.trend)
+			state.trend = beta*(state.level-previous) + (1-beta)*state.trend
+		}
+		if seasonal {
+			state.season[day] = gamma*(point.Value-state.level) + (1-gamma)*state.season[day]
+		}
+	}
+	return state
+}
+
+// sortedByDate returns a copy of data in date order
+func sortedByDate(data []TrendDataPoint) []TrendDataPoint {
+	sorted := append([]TrendDataPoint(nil), data...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+	return sorted
+}
@@ -0,0 +1,50 @@
+package analytics
+
+import (
+	"math"
+)
+
+// Lead score thresholds of the quality tiers; scores below MediumQualityScore are low quality
+const (
+	HighQualityScore   = 80
+	MediumQualityScore = 50
+)
+
+// scoreRangeWidth is the width of the lead score ranges
+const scoreRangeWidth = 20
+
+// ScoreRanges returns the lead score ranges in ascending order
+func ScoreRanges() []string {
+	return []string{"0-19", "20-39", "40-59", "60-79", "80-100"}
+}
+
+// ScoreRangeIndex returns the index in ScoreRanges of the range holding score
+func ScoreRangeIndex(score int) int {
+	return min(max(score, 0)/scoreRangeWidth, len(ScoreRanges())-1)
+}
+
+// QualityTier returns high, medium or low for a lead score
+func QualityTier(score int) string {
+	switch {
+	case score >= HighQualityScore:
+		return ImpactHigh
+	case score >= MediumQualityScore:
+		return ImpactMedium
+	default:
+		return ImpactLow
+	}
+}
+
+// Correlation returns the Pearson correlation coefficient of n pairs from
+// their sums, or 0 when either variable is constant
+func Correlation(n, sumX, sumY, sumXY, sumX2, sumY2 float64) float64 {
+	if n < 2 {
+		return 0
+	}
+	covariance := n*sumXY - sumX*sumY
+	spread := math.Sqrt(n*sumX2-sumX*sumX) * math.Sqrt(n*sumY2-sumY*sumY)
+	if spread == 0 || math.IsNaN(spread) {
+		return 0
+	}
+	return math.Max(-1, math.Min(1, covariance/spread))
+}
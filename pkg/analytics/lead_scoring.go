@@ -14,27 +14,36 @@ func NewLeadScorer() *LeadScorer {
 	return &LeadScorer{}
 }
 
+// Lead score dimension weights
+const (
+	demographicWeight  = 0.25
+	behavioralWeight   = 0.35
+	firmographicWeight = 0.25 // for B2B leads
+	intentWeight       = 0.15
+)
+
 // CalculateLeadScore calculates a comprehensive lead score (0-100)
 func (ls *LeadScorer) CalculateLeadScore(profile LeadProfile) int {
-	var totalScore float64
-
-	// Demographic scoring (25% weight)
-	demographicScore := ls.calculateDemographicScore(profile.Demographics)
-	totalScore += demographicScore * 0.25
-
-	// Behavioral scoring (35% weight)
-	behavioralScore := ls.calculateBehavioralScore(profile.Behavior)
-	totalScore += behavioralScore * 0.35
+	return ls.ScoreBreakdown(profile).Total
+}
 
-	// Firmographic scoring (25% weight) - for B2B leads
-	firmographicScore := ls.calculateFirmographicScore(profile.Company)
-	totalScore += firmographicScore * 0.25
+// ScoreBreakdown scores each dimension of a lead profile (0-100) and
+// combines them into the weighted total returned by CalculateLeadScore
+func (ls *LeadScorer) ScoreBreakdown(profile LeadProfile) LeadScoreBreakdown {
+	breakdown := LeadScoreBreakdown{
+		Demographic:  ls.calculateDemographicScore(profile.Demographics),
+		Behavioral:   ls.calculateBehavioralScore(profile.Behavior),
+		Firmographic: ls.calculateFirmographicScore(profile.Company),
+		Intent:       ls.calculateIntentScore(profile.Intent),
+	}
 
-	// Intent scoring (15% weight)
-	intentScore := ls.calculateIntentScore(profile.Intent)
-	totalScore += intentScore * 0.15
+	totalScore := breakdown.Demographic*demographicWeight +
+		breakdown.Behavioral*behavioralWeight +
+		breakdown.Firmographic*firmographicWeight +
+		breakdown.Intent*intentWeight
+	breakdown.Total = int(math.Min(totalScore, 100))
 
-	return int(math.Min(totalScore, 100))
+	return breakdown
 }
 
 // calculateDemographicScore scores based on demographic information
@@ -266,7 +275,11 @@ func (ls *LeadScorer) scoreWebsiteActivity(behavior Behavior) float64 {
 	var score float64
 
 	// Depth of visit (pages per session)
-	avgPagesPerSession := float64(behavior.PageViews) / float64(behavior.VisitCount)
+	visits := behavior.VisitCount
+	if visits < 1 {
+		visits = 1
+	}
+	avgPagesPerSession := float64(behavior.PageViews) / float64(visits)
 	if avgPagesPerSession >= 5 {
 		score += 25.0
 	} else if avgPagesPerSession >= 3 {
@@ -450,6 +463,25 @@ func (ls *LeadScorer) AutoQualifyLead(leadScore int, profile LeadProfile) string
 
 // Data structures for lead scoring
 
+// LeadScoreBreakdown holds the per-dimension scores behind a lead score
+type LeadScoreBreakdown struct {
+	Demographic  float64 `json:"demographic"`
+	Behavioral   float64 `json:"behavioral"`
+	Firmographic float64 `json:"firmographic"`
+	Intent       float64 `json:"intent"`
+	Total        int     `json:"total"`
+}
+
+// LeadScoreWeights returns the share of each dimension in the total lead score
+func LeadScoreWeights() map[string]float64 {
+	return map[string]float64{
+		"demographic":  demographicWeight,
+		"behavioral":   behavioralWeight,
+		"firmographic": firmographicWeight,
+		"intent":       intentWeight,
+	}
+}
+
 type LeadProfile struct {
 	Demographics Demographics
 	Behavior     Behavior
@@ -0,0 +1,116 @@
+package analytics
+
+import (
+	"strings"
+	"time"
+)
+
+// Time series granularities
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// MaxBuckets caps the number of points in a time series
+const MaxBuckets = 400
+
+// IsValidGranularity reports whether granularity is day, week or month
+func IsValidGranularity(granularity string) bool {
+	return granularity == GranularityDay || granularity == GranularityWeek || granularity == GranularityMonth
+}
+
+// DefaultGranularity picks a granularity that keeps a range readable: days up
+// to a month, weeks up to half a year, months beyond
+func DefaultGranularity(start, end time.Time) string {
+	days := end.Sub(start).Hours() / 24
+	switch {
+	case days <= 31:
+		return GranularityDay
+	case days <= 183:
+		return GranularityWeek
+	default:
+		return GranularityMonth
+	}
+}
+
+// BucketStart returns the UTC start of the bucket containing t. Weeks start on Monday.
+func BucketStart(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch granularity {
+	case GranularityWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case GranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// NextBucket returns the start of the bucket following the one starting at start
+func NextBucket(start time.Time, granularity string) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Buckets returns the start of every bucket overlapping start..end, at most MaxBuckets
+func Buckets(start, end time.Time, granularity string) []time.Time {
+	buckets := []time.Time{}
+	for bucket := BucketStart(start, granularity); !bucket.After(end) && len(buckets) < MaxBuckets; bucket = NextBucket(bucket, granularity) {
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// BucketCount returns the number of buckets in start..end, counting no further
+// than MaxBuckets+1 so callers can reject ranges that Buckets would truncate
+func BucketCount(start, end time.Time, granularity string) int {
+	count := 0
+	for bucket := BucketStart(start, granularity); !bucket.After(end) && count <= MaxBuckets; bucket = NextBucket(bucket, granularity) {
+		count++
+	}
+	return count
+}
+
+// PreviousPeriod returns the window of the same length ending just before start
+func PreviousPeriod(start, end time.Time) (time.Time, time.Time) {
+	length := end.Sub(start)
+	previousEnd := start.Add(-time.Nanosecond)
+	return previousEnd.Add(-length), previousEnd
+}
+
+// ParsePeriod parses a relative period such as 24h, 7d or 90d
+func ParsePeriod(period string) (time.Duration, bool) {
+	period = strings.TrimSpace(period)
+	if len(period) < 2 {
+		return 0, false
+	}
+	value := 0
+	for _, r := range period[:len(period)-1] {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+		value = value*10 + int(r-'0')
+		if value > 3660 {
+			return 0, false
+		}
+	}
+	if value == 0 {
+		return 0, false
+	}
+	switch period[len(period)-1] {
+	case 'h':
+		return time.Duration(value) * time.Hour, true
+	case 'd':
+		return time.Duration(value) * 24 * time.Hour, true
+	default:
+		return 0, false
+	}
+}
@@ -398,38 +398,9 @@ func (ta *TrendAnalyzer) calculateVolatility(data []TrendDataPoint) float64 {
 	return math.Sqrt(variance)
 }
 
-// generateForecast generates simple forecast using linear regression
+// generateForecast forecasts the next days with Holt-Winters smoothing
 func (ta *TrendAnalyzer) generateForecast(data []TrendDataPoint, days int) []ForecastPoint {
-	if len(data) < 2 {
-		return nil
-	}
-
-	regression := ta.calculateLinearRegression(data)
-	baseTime := data[0].Date
-	lastDate := data[len(data)-1].Date
-
-	forecast := make([]ForecastPoint, days)
-
-	for i := 0; i < days; i++ {
-		forecastDate := lastDate.AddDate(0, 0, i+1)
-		x := float64(forecastDate.Sub(baseTime).Hours() / 24)
-
-		predictedValue := regression.Slope*x + regression.Intercept
-
-		// Add confidence interval based on historical volatility
-		volatility := ta.calculateVolatility(data)
-		confidenceInterval := volatility * 1.96 // 95% confidence interval
-
-		forecast[i] = ForecastPoint{
-			Date:               forecastDate,
-			PredictedValue:     predictedValue,
-			ConfidenceInterval: confidenceInterval,
-			LowerBound:         predictedValue - confidenceInterval,
-			UpperBound:         predictedValue + confidenceInterval,
-		}
-	}
-
-	return forecast
+	return (&Forecaster{analyzer: ta}).Forecast(data, days).Points
 }
 
 // generateInsights generates actionable insights based on trend analysis
@@ -0,0 +1,139 @@
+package analytics
+
+import (
+	"math"
+	"time"
+)
+
+// Simplified trend directions and strengths for API responses
+const (
+	TrendUp     = "up"
+	TrendDown   = "down"
+	TrendStable = "stable"
+
+	TrendStrong   = "strong"
+	TrendModerate = "moderate"
+	TrendWeak     = "weak"
+)
+
+// Impacts of a graded metric
+const (
+	ImpactPositive = "positive"
+	ImpactNegative = "negative"
+	ImpactNeutral  = "neutral"
+)
+
+// seasonalShare is the deviation from the mean that makes a month a peak or a low
+const seasonalShare = 0.2
+
+// SummarizeTrend reduces an analysis to an up, down or stable direction and a
+// strong, moderate or weak strength
+func SummarizeTrend(analysis TrendAnalysis) (direction, strength string) {
+	switch analysis.TrendDirection {
+	case "strongly_increasing", "increasing":
+		direction = TrendUp
+	case "strongly_decreasing", "decreasing":
+		direction = TrendDown
+	default:
+		direction = TrendStable
+	}
+
+	switch analysis.TrendStrength {
+	case "very_strong", "strong":
+		strength = TrendStrong
+	case "moderate":
+		strength = TrendModerate
+	default:
+		strength = TrendWeak
+	}
+	return direction, strength
+}
+
+// ProjectTotal sums the analysis's regression line over the days following its
+// last point. Days projected below zero count as zero.
+func (ta *TrendAnalyzer) ProjectTotal(analysis TrendAnalysis, days int) float64 {
+	if analysis.Points < 2 {
+		return analysis.AverageValue * float64(days)
+	}
+
+	last := analysis.EndDate.Sub(analysis.StartDate).Hours() / 24
+	var total float64
+	for i := 1; i <= days; i++ {
+		total += math.Max(analysis.LinearRegression.Slope*(last+float64(i))+analysis.LinearRegression.Intercept, 0)
+	}
+	return total
+}
+
+// SeasonalityScore rates 0-100 how far the busiest or quietest weekday strays from the mean
+func SeasonalityScore(pattern map[time.Weekday]float64) int {
+	if len(pattern) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, value := range pattern {
+		sum += value
+	}
+	mean := sum / float64(len(pattern))
+	if mean == 0 {
+		return 0
+	}
+
+	var deviation float64
+	for _, value := range pattern {
+		deviation = math.Max(deviation, math.Abs(value-mean)/mean)
+	}
+	return int(math.Round(math.Min(deviation, 1) * 100))
+}
+
+// PeakAndLowMonths returns the months whose average is at least 20% above or below the mean
+func PeakAndLowMonths(pattern map[int]float64) (peaks, lows []string) {
+	peaks, lows = []string{}, []string{}
+	if len(pattern) < 2 {
+		return peaks, lows
+	}
+	var sum float64
+	for _, value := range pattern {
+		sum += value
+	}
+	mean := sum / float64(len(pattern))
+	if mean == 0 {
+		return peaks, lows
+	}
+
+	for month := 1; month <= 12; month++ {
+		value, ok := pattern[month]
+		if !ok {
+			continue
+		}
+		switch {
+		case value >= mean*(1+seasonalShare):
+			peaks = append(peaks, time.Month(month).String())
+		case value <= mean*(1-seasonalShare):
+			lows = append(lows, time.Month(month).String())
+		}
+	}
+	return peaks, lows
+}
+
+// GradeMetric grades value against a good and a bad threshold; good is above
+// bad when higher values are better. Values past a threshold by at least half
+// the gap between the thresholds have high strength.
+func GradeMetric(value, good, bad float64) (impact, strength string) {
+	gap := math.Abs(good - bad)
+	higherIsBetter := good > bad
+
+	var past float64
+	switch {
+	case (higherIsBetter && value >= good) || (!higherIsBetter && value <= good):
+		impact, past = ImpactPositive, math.Abs(value-good)
+	case (higherIsBetter && value <= bad) || (!higherIsBetter && value >= bad):
+		impact, past = ImpactNegative, math.Abs(value-bad)
+	default:
+		return ImpactNeutral, ImpactLow
+	}
+
+	if past >= gap/2 {
+		return impact, ImpactHigh
+	}
+	return impact, ImpactMedium
+}
@@ -0,0 +1,76 @@
+package assignment
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Criteria are the conditions of an assignment rule. Empty conditions match
+// every lead; all set conditions must match.
+type Criteria struct {
+	CategoryID        *uint  // matches the category and its descendants
+	Country           string // case-insensitive
+	AutoQualification string // hot, warm, cold or unqualified
+	MinScore          *int
+	MaxScore          *int
+	UTMCampaign       string // case-insensitive
+}
+
+// Lead holds the lead attributes rules are matched against
+type Lead struct {
+	CategoryPath      string // materialized path of the lead's blog category, e.g. /1/4/
+	Country           string
+	AutoQualification string
+	Score             int
+	UTMCampaign       string
+}
+
+// Matches reports whether a lead satisfies every set condition
+func (c Criteria) Matches(lead Lead) bool {
+	if c.CategoryID != nil && !strings.Contains(lead.CategoryPath, "/"+strconv.FormatUint(uint64(*c.CategoryID), 10)+"/") {
+		return false
+	}
+	if c.Country != "" && !strings.EqualFold(c.Country, strings.TrimSpace(lead.Country)) {
+		return false
+	}
+	if c.AutoQualification != "" && c.AutoQualification != lead.AutoQualification {
+		return false
+	}
+	if c.MinScore != nil && lead.Score < *c.MinScore {
+		return false
+	}
+	if c.MaxScore != nil && lead.Score > *c.MaxScore {
+		return false
+	}
+	if c.UTMCampaign != "" && !strings.EqualFold(c.UTMCampaign, strings.TrimSpace(lead.UTMCampaign)) {
+		return false
+	}
+	return true
+}
+
+// NextInPool picks the next user of a round-robin pool after the last assigned
+// user, skipping users that are not active. When the last user is not in the
+// pool, the first active user is picked. Returns false when no user is active.
+func NextInPool(pool []uint, last *uint, active map[uint]bool) (uint, bool) {
+	if len(pool) == 0 {
+		return 0, false
+	}
+
+	start := 0
+	if last != nil {
+		for i, id := range pool {
+			if id == *last {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	for offset := 0; offset < len(pool); offset++ {
+		candidate := pool[(start+offset)%len(pool)]
+		if active[candidate] {
+			return candidate, true
+		}
+	}
+	return 0, false
+}
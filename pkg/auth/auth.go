@@ -113,17 +113,25 @@ func HasPermission(role, permission string) bool {
 		"admin": {
 			"blog:create", "blog:read", "blog:update", "blog:delete",
 			"blog:publish", "blog:unpublish", "blog:moderate",
+			"category:manage", "tag:manage",
+			"lead:read", "lead:update", "lead:reopen", "lead:merge", "lead:assign", "lead:attribution", "lead:privacy",
+			"analytics:read", "analytics:manage",
 			"user:create", "user:read", "user:update", "user:delete",
 			"admin:all",
 		},
 		"manager": {
 			"blog:create", "blog:read", "blog:update", "blog:delete",
 			"blog:publish", "blog:unpublish",
+			"category:manage", "tag:manage",
+			"lead:read", "lead:update", "lead:assign",
+			"analytics:read",
 			"user:read", "user:update",
 		},
 		"editor": {
 			"blog:create", "blog:read", "blog:update",
 			"blog:publish", "blog:unpublish",
+			"category:manage", "tag:manage",
+			"analytics:read",
 		},
 		"author": {
 			"blog:create", "blog:read", "blog:update",
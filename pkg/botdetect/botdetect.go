@@ -0,0 +1,348 @@
+package botdetect
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reasons a request is classified as automated
+const (
+	ReasonUserAgent    = "user_agent"
+	ReasonHeadless     = "headless"
+	ReasonRate         = "rate"
+	ReasonMonitoringIP = "monitoring_ip"
+)
+
+// agent maps a lower-case user-agent fragment to the name it is reported under
+type agent struct {
+	fragment string
+	name     string
+}
+
+// knownAgents are matched in order, so specific crawlers come before the
+// generic fragments that would also match them
+var knownAgents = []agent{
+	// Search engines
+	{"googlebot", "Googlebot"},
+	{"google-inspectiontool", "Googlebot"},
+	{"adsbot-google", "Google AdsBot"},
+	{"mediapartners-google", "Google AdSense"},
+	{"bingbot", "Bingbot"},
+	{"bingpreview", "Bingbot"},
+	{"slurp", "Yahoo Slurp"},
+	{"duckduckbot", "DuckDuckBot"},
+	{"baiduspider", "Baiduspider"},
+	{"yandex", "YandexBot"},
+	{"applebot", "Applebot"},
+	{"petalbot", "PetalBot"},
+	{"seznambot", "SeznamBot"},
+	// SEO tools
+	{"ahrefsbot", "AhrefsBot"},
+	{"semrushbot", "SemrushBot"},
+	{"mj12bot", "MJ12bot"},
+	{"dotbot", "DotBot"},
+	{"rogerbot", "Rogerbot"},
+	{"screaming frog", "Screaming Frog"},
+	// AI and dataset crawlers
+	{"gptbot", "GPTBot"},
+	{"ccbot", "CCBot"},
+	{"bytespider", "Bytespider"},
+	{"perplexitybot", "PerplexityBot"},
+	// Link previews
+	{"facebookexternalhit", "Facebook"},
+	{"facebot", "Facebook"},
+	{"twitterbot", "Twitterbot"},
+	{"linkedinbot", "LinkedInBot"},
+	{"slackbot", "Slackbot"},
+	{"discordbot", "Discordbot"},
+	{"telegrambot", "TelegramBot"},
+	{"whatsapp", "WhatsApp"},
+	{"skypeuripreview", "Skype"},
+	// Uptime monitors, including scripts/health-check.sh
+	{"mejona-health-check", "Health check"},
+	{"uptimerobot", "UptimeRobot"},
+	{"pingdom", "Pingdom"},
+	{"statuscake", "StatusCake"},
+	{"site24x7", "Site24x7"},
+	{"newrelicpinger", "New Relic"},
+	{"datadog", "Datadog"},
+	{"kube-probe", "Kubernetes probe"},
+	{"elb-healthchecker", "ELB health check"},
+	{"googlehc", "Google health check"},
+	// HTTP clients and tools
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+	{"python-requests", "python-requests"},
+	{"python-urllib", "Python urllib"},
+	{"aiohttp", "aiohttp"},
+	{"go-http-client", "Go http client"},
+	{"okhttp", "OkHttp"},
+	{"java/", "Java"},
+	{"apache-httpclient", "Apache HttpClient"},
+	{"axios/", "axios"},
+	{"node-fetch", "node-fetch"},
+	{"libwww-perl", "libwww-perl"},
+	{"postmanruntime", "Postman"},
+	{"insomnia", "Insomnia"},
+	{"scrapy", "Scrapy"},
+	// Generic markers
+	{"bot", "Other bot"},
+	{"crawler", "Other bot"},
+	{"spider", "Other bot"},
+	{"crawl", "Other bot"},
+	{"scraper", "Other bot"},
+}
+
+// headlessAgents are user-agent fragments of automated browsers
+var headlessAgents = []agent{
+	{"headlesschrome", "HeadlessChrome"},
+	{"phantomjs", "PhantomJS"},
+	{"puppeteer", "Puppeteer"},
+	{"playwright", "Playwright"},
+	{"selenium", "Selenium"},
+	{"webdriver", "WebDriver"},
+	{"slimerjs", "SlimerJS"},
+}
+
+// Request holds the signals used to classify a request
+type Request struct {
+	UserAgent      string
+	IP             string
+	AcceptLanguage string
+	ClientHintsUA  string // Sec-CH-UA header
+}
+
+// Classification is the outcome of classifying a request
+type Classification struct {
+	Bot    bool
+	Reason string
+	Name   string // crawler, monitor or tool name when known
+}
+
+// Classifier flags automated traffic from user-agent lists, headless-browser
+// signals, request rate per IP and known monitoring addresses. It is safe for
+// concurrent use.
+type Classifier struct {
+	monitoring []*net.IPNet
+	rate       *RateCounter
+}
+
+// NewClassifier creates a classifier treating requests from the monitoring
+// addresses or networks (CIDR or plain IPs) as automated, and IPs exceeding
+// rate requests per window as automated for the rest of the window. Invalid
+// entries are returned so they can be reported.
+func NewClassifier(monitoring []string, rate int, window time.Duration) (*Classifier, []string) {
+	classifier := &Classifier{rate: NewRateCounter(rate, window, 0)}
+
+	var invalid []string
+	for _, entry := range monitoring {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		network, err := parseNetwork(entry)
+		if err != nil {
+			invalid = append(invalid, entry)
+			continue
+		}
+		classifier.monitoring = append(classifier.monitoring, network)
+	}
+
+	return classifier, invalid
+}
+
+// parseNetwork parses a CIDR or a plain IP, which becomes a single-address network
+func parseNetwork(entry string) (*net.IPNet, error) {
+	cidr := entry
+	if !strings.Contains(cidr, "/") {
+		if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+			cidr += "/32"
+		} else {
+			cidr += "/128"
+		}
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	return network, err
+}
+
+// MonitoringOverlaps returns the entries (CIDR or plain IPs) sharing addresses
+// with the monitoring networks. Invalid entries are skipped.
+func (c *Classifier) MonitoringOverlaps(entries []string) []string {
+	var overlaps []string
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		network, err := parseNetwork(entry)
+		if entry == "" || err != nil {
+			continue
+		}
+		for _, monitoring := range c.monitoring {
+			if monitoring.Contains(network.IP) || network.Contains(monitoring.IP) {
+				overlaps = append(overlaps, entry)
+				break
+			}
+		}
+	}
+	return overlaps
+}
+
+// Classify classifies a request made at now. Every call counts towards the
+// IP's request rate.
+func (c *Classifier) Classify(req Request, now time.Time) Classification {
+	limited := c.rate.Hit(req.IP, now)
+
+	if ip := net.ParseIP(req.IP); ip != nil {
+		for _, network := range c.monitoring {
+			if network.Contains(ip) {
+				return Classification{Bot: true, Reason: ReasonMonitoringIP, Name: "Monitoring"}
+			}
+		}
+	}
+
+	ua := strings.ToLower(strings.TrimSpace(req.UserAgent))
+	if ua == "" {
+		return Classification{Bot: true, Reason: ReasonUserAgent, Name: "Empty user agent"}
+	}
+	if name, ok := matchAgent(headlessAgents, ua); ok {
+		return Classification{Bot: true, Reason: ReasonHeadless, Name: name}
+	}
+	if strings.Contains(strings.ToLower(req.ClientHintsUA), "headless") {
+		return Classification{Bot: true, Reason: ReasonHeadless, Name: "HeadlessChrome"}
+	}
+	if name, ok := matchAgent(knownAgents, ua); ok {
+		return Classification{Bot: true, Reason: ReasonUserAgent, Name: name}
+	}
+	// Browsers always send Accept-Language; automation posing as one often does not
+	if strings.HasPrefix(ua, "mozilla/") && strings.TrimSpace(req.AcceptLanguage) == "" {
+		return Classification{Bot: true, Reason: ReasonHeadless, Name: "Unknown automated browser"}
+	}
+
+	if limited {
+		return Classification{Bot: true, Reason: ReasonRate, Name: "High request rate"}
+	}
+	return Classification{}
+}
+
+// matchAgent returns the name of the first agent whose fragment ua contains
+func matchAgent(agents []agent, ua string) (string, bool) {
+	for _, a := range agents {
+		if strings.Contains(ua, a.fragment) {
+			return a.name, true
+		}
+	}
+	return "", false
+}
+
+// RateCounter counts requests per key in fixed windows. It is safe for
+// concurrent use.
+type RateCounter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	maxKeys int
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateCounter creates a counter allowing limit requests per key and window,
+// tracking at most maxKeys keys. A limit of zero or less disables it.
+func NewRateCounter(limit int, window time.Duration, maxKeys int) *RateCounter {
+	if window <= 0 {
+		window = time.Minute
+	}
+	if maxKeys <= 0 {
+		maxKeys = 100000
+	}
+	return &RateCounter{limit: limit, window: window, maxKeys: maxKeys, windows: map[string]*rateWindow{}}
+}
+
+// Hit counts a request for key at now and reports whether the key is over the
+// limit in the current window
+func (r *RateCounter) Hit(key string, now time.Time) bool {
+	if r.limit <= 0 || key == "" {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.windows[key]
+	if !ok || now.Sub(w.start) >= r.window {
+		if !ok && len(r.windows) >= r.maxKeys {
+			r.sweep(now)
+			if len(r.windows) >= r.maxKeys {
+				r.windows = map[string]*rateWindow{}
+			}
+		}
+		w = &rateWindow{start: now}
+		r.windows[key] = w
+	}
+	w.count++
+	return w.count > r.limit
+}
+
+func (r *RateCounter) sweep(now time.Time) {
+	for key, w := range r.windows {
+		if now.Sub(w.start) >= r.window {
+			delete(r.windows, key)
+		}
+	}
+}
+
+// Hit is one classified request kept for crawl analysis
+type Hit struct {
+	At        time.Time
+	Route     string
+	Path      string
+	BlogID    *uint
+	Name      string
+	Reason    string
+	UserAgent string
+	IP        string
+}
+
+// HitLog buffers bot hits in memory until they are written in batches. It is
+// safe for concurrent use.
+type HitLog struct {
+	mu      sync.Mutex
+	hits    []Hit
+	max     int
+	dropped int
+}
+
+// NewHitLog creates a log holding at most max hits between drains
+func NewHitLog(max int) *HitLog {
+	if max <= 0 {
+		max = 10000
+	}
+	return &HitLog{max: max}
+}
+
+// Add appends a hit, dropping it when the log is full
+func (l *HitLog) Add(hit Hit) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.hits) >= l.max {
+		l.dropped++
+		return false
+	}
+	l.hits = append(l.hits, hit)
+	return true
+}
+
+// Drain removes and returns the buffered hits and the number dropped since the
+// last drain
+func (l *HitLog) Drain() ([]Hit, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	hits, dropped := l.hits, l.dropped
+	l.hits, l.dropped = nil, 0
+	return hits, dropped
+}
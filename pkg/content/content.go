@@ -0,0 +1,301 @@
+package content
+
+import (
+	"blog-service/pkg/seo"
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Supported content formats
+const (
+	FormatAuto     = "auto"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// htmlContentPattern detects content that starts with a block-level HTML element
+var htmlContentPattern = regexp.MustCompile(`(?i)^\s*<(!doctype|html|body|article|section|div|p|h[1-6]|ul|ol|table|figure|img|blockquote|pre)[\s>/]`)
+
+// relPattern restricts the rel attribute to plain keyword lists
+var relPattern = regexp.MustCompile(`^[a-zA-Z\s]+$`)
+
+// Options configures content parsing
+type Options struct {
+	Format  string // auto (default), markdown or html
+	SiteURL string // links to this host (and relative links) are internal
+}
+
+// Document is the result of parsing blog content
+type Document struct {
+	Format        string            `json:"format"`
+	HTML          string            `json:"html"` // sanitized, safe to render
+	Text          string            `json:"text"` // plain text used for word counts and readability
+	WordCount     int               `json:"word_count"`
+	Headings      []seo.HeadingData `json:"headings"`
+	InternalLinks []seo.LinkData    `json:"internal_links"`
+	ExternalLinks []seo.LinkData    `json:"external_links"`
+	Images        []seo.ImageData   `json:"images"`
+}
+
+// Parser converts Markdown or HTML into sanitized HTML and extracts its structure
+type Parser struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+}
+
+// NewParser creates a new content parser
+func NewParser() *Parser {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		// Raw HTML inside Markdown is kept here and removed by the sanitizer
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+
+	policy := bluemonday.UGCPolicy()
+	policy.RequireNoFollowOnLinks(false)
+	policy.AllowAttrs("rel").Matching(relPattern).OnElements("a")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w-]+$`)).OnElements("code")
+
+	return &Parser{markdown: markdown, policy: policy}
+}
+
+// DetectFormat guesses whether raw content is HTML or Markdown
+func DetectFormat(raw string) string {
+	if htmlContentPattern.MatchString(raw) {
+		return FormatHTML
+	}
+	return FormatMarkdown
+}
+
+// Parse renders raw content to sanitized HTML and extracts headings, links and images
+func (p *Parser) Parse(raw string, opts Options) (*Document, error) {
+	format := opts.Format
+	if format == "" || format == FormatAuto {
+		format = DetectFormat(raw)
+	}
+
+	var rendered string
+	switch format {
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := p.markdown.Convert([]byte(raw), &buf); err != nil {
+			return nil, fmt.Errorf("failed to render markdown: %v", err)
+		}
+		rendered = buf.String()
+	case FormatHTML:
+		rendered = raw
+	default:
+		return nil, fmt.Errorf("unsupported content format: %s", format)
+	}
+
+	doc := &Document{
+		Format:        format,
+		HTML:          p.policy.Sanitize(rendered),
+		Headings:      []seo.HeadingData{},
+		InternalLinks: []seo.LinkData{},
+		ExternalLinks: []seo.LinkData{},
+		Images:        []seo.ImageData{},
+	}
+
+	siteHost := ""
+	if site, err := url.Parse(opts.SiteURL); err == nil {
+		siteHost = normalizeHost(site.Hostname())
+	}
+
+	if err := doc.extract(siteHost); err != nil {
+		return nil, err
+	}
+	doc.WordCount = len(strings.Fields(doc.Text))
+
+	return doc, nil
+}
+
+// ContentData copies the extracted content and structure into data for SEO analysis
+func (d *Document) ContentData(data seo.ContentData) seo.ContentData {
+	data.Content = d.Text
+	data.Headings = d.Headings
+	data.InternalLinks = d.InternalLinks
+	data.ExternalLinks = d.ExternalLinks
+	data.Images = d.Images
+	return data
+}
+
+// extract walks the sanitized HTML collecting text, headings, links and images
+func (d *Document) extract(siteHost string) error {
+	root, err := html.Parse(strings.NewReader(d.HTML))
+	if err != nil {
+		return fmt.Errorf("failed to parse rendered html: %v", err)
+	}
+
+	var text strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+			return
+		}
+
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				if heading := collapseSpace(nodeText(n)); heading != "" {
+					d.Headings = append(d.Headings, seo.HeadingData{Level: int(n.Data[1] - '0'), Text: heading})
+				}
+			case atom.A:
+				d.addLink(n, siteHost)
+			case atom.Img:
+				d.addImage(n)
+			}
+
+			// Keep block boundaries so words and sentences do not run together
+			if isBlock(n.DataAtom) {
+				text.WriteString("\n")
+			}
+		}
+
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+
+		if n.Type == html.ElementNode && isBlock(n.DataAtom) {
+			text.WriteString("\n")
+		}
+	}
+	walk(root)
+
+	d.Text = cleanText(text.String())
+	return nil
+}
+
+// addLink records an anchor as an internal or external link
+func (d *Document) addLink(n *html.Node, siteHost string) {
+	href := strings.TrimSpace(attr(n, "href"))
+	if href == "" || strings.HasPrefix(href, "#") {
+		return
+	}
+
+	target, err := url.Parse(href)
+	if err != nil {
+		return
+	}
+	if target.Scheme != "" && target.Scheme != "http" && target.Scheme != "https" {
+		return // mailto:, tel: and similar are not page links
+	}
+
+	rel := strings.Fields(strings.ToLower(attr(n, "rel")))
+	link := seo.LinkData{
+		URL:        href,
+		AnchorText: collapseSpace(nodeText(n)),
+		IsDoFollow: !containsAny(rel, "nofollow", "sponsored", "ugc"),
+		IsInternal: target.Host == "" || (siteHost != "" && normalizeHost(target.Hostname()) == siteHost),
+	}
+
+	if link.IsInternal {
+		d.InternalLinks = append(d.InternalLinks, link)
+	} else {
+		d.ExternalLinks = append(d.ExternalLinks, link)
+	}
+}
+
+// addImage records an image with its file name and alt text
+func (d *Document) addImage(n *html.Node) {
+	src := strings.TrimSpace(attr(n, "src"))
+	if src == "" {
+		return
+	}
+
+	fileName := ""
+	if parsed, err := url.Parse(src); err == nil {
+		fileName = path.Base(parsed.Path)
+		if fileName == "." || fileName == "/" {
+			fileName = ""
+		}
+	}
+
+	d.Images = append(d.Images, seo.ImageData{
+		URL:      src,
+		FileName: fileName,
+		AltText:  strings.TrimSpace(attr(n, "alt")),
+		Title:    strings.TrimSpace(attr(n, "title")),
+	})
+}
+
+// attr returns the value of an attribute, or "" when absent
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// nodeText returns the concatenated text of a node and its descendants
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(nodeText(child))
+	}
+	return b.String()
+}
+
+// isBlock reports whether an element starts a new block of text
+func isBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.Br, atom.Li, atom.Ul, atom.Ol, atom.Blockquote, atom.Pre,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Table, atom.Tr, atom.Td, atom.Th, atom.Section, atom.Article, atom.Figure, atom.Figcaption, atom.Hr:
+		return true
+	}
+	return false
+}
+
+// cleanText collapses spaces within lines and drops blank lines
+func cleanText(text string) string {
+	lines := strings.Split(text, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = collapseSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// collapseSpace trims text and collapses internal whitespace runs
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// normalizeHost lowercases a host name and strips a leading www.
+func normalizeHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
+
+// containsAny reports whether values contains any of the targets
+func containsAny(values []string, targets ...string) bool {
+	for _, value := range values {
+		for _, target := range targets {
+			if value == target {
+				return true
+			}
+		}
+	}
+	return false
+}
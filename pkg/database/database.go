@@ -64,6 +64,7 @@ func InitDB() error {
 			return time.Now().UTC()
 		},
 		PrepareStmt:                              true,
+		TranslateError:                           true,
 		DisableForeignKeyConstraintWhenMigrating: false,
 	})
 
@@ -274,15 +275,22 @@ func Paginate(page, pageSize int) func(db *gorm.DB) *gorm.DB {
 	}
 }
 
-// AutoMigrate runs database migrations for all models
-func AutoMigrate() error {
+// AutoMigrate runs database migrations for the given models.
+// Models are passed in from the main application to avoid circular imports.
+func AutoMigrate(models ...interface{}) error {
 	if DB == nil {
 		return fmt.Errorf("database not initialized")
 	}
 
-	// Import models here to avoid circular imports
-	// This would typically be done in the main application
-	log.Println("Auto-migration should be called from main application with all models")
+	if len(models) == 0 {
+		return nil
+	}
+
+	if err := DB.AutoMigrate(models...); err != nil {
+		return fmt.Errorf("auto-migration failed: %v", err)
+	}
+
+	log.Printf("Auto-migration completed for %d models", len(models))
 	return nil
 }
 
@@ -0,0 +1,26 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlSyntaxError is ER_PARSE_ERROR, also raised for malformed boolean-mode full-text queries
+const mysqlSyntaxError = 1064
+
+// mysqlDeadlock is ER_LOCK_DEADLOCK, raised on the transaction MySQL rolls back to break a deadlock
+const mysqlDeadlock = 1213
+
+// IsSyntaxError reports whether err is a MySQL syntax error
+func IsSyntaxError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlSyntaxError
+}
+
+// IsDeadlock reports whether err is a MySQL deadlock. The transaction was
+// rolled back and can be retried.
+func IsDeadlock(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDeadlock
+}
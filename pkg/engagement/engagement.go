@@ -0,0 +1,227 @@
+package engagement
+
+import (
+	"sync"
+	"time"
+)
+
+// Engagement event types recorded by the pixel and beacon endpoints
+const (
+	EventView   = "view"
+	EventScroll = "scroll"
+	EventLike   = "like"
+	EventShare  = "share"
+)
+
+// ScrollBuckets are the scroll depth milestones, in percent of the page
+var ScrollBuckets = [4]int{25, 50, 75, 100}
+
+// ScrollBucket returns the index of the deepest milestone a scroll depth
+// reached, or -1 when it is below the first milestone
+func ScrollBucket(depth float64) int {
+	bucket := -1
+	for i, milestone := range ScrollBuckets {
+		if depth >= float64(milestone) {
+			bucket = i
+		}
+	}
+	return bucket
+}
+
+// Key identifies the counters of one blog on one UTC day
+type Key struct {
+	BlogID uint
+	Day    string // YYYY-MM-DD
+}
+
+// Counts are engagement counter increments
+type Counts struct {
+	Views  int
+	Likes  int
+	Shares int
+	Scroll [4]int // sessions reaching each of ScrollBuckets
+}
+
+// IsZero reports whether there is nothing to write
+func (c Counts) IsZero() bool {
+	return c == Counts{}
+}
+
+// Buffer accumulates counter increments in memory so they can be written in
+// batches instead of one row update per hit. It is safe for concurrent use.
+type Buffer struct {
+	mu        sync.Mutex
+	pending   map[Key]*Counts
+	maxKeys   int
+	threshold int
+	dropped   int
+	ready     chan struct{}
+}
+
+// NewBuffer creates a buffer holding at most maxKeys blog days. Ready is
+// signalled once threshold blog days are pending.
+func NewBuffer(maxKeys, threshold int) *Buffer {
+	if maxKeys <= 0 {
+		maxKeys = 10000
+	}
+	if threshold <= 0 || threshold > maxKeys {
+		threshold = maxKeys
+	}
+	return &Buffer{
+		pending:   map[Key]*Counts{},
+		maxKeys:   maxKeys,
+		threshold: threshold,
+		ready:     make(chan struct{}, 1),
+	}
+}
+
+// Add records increments for a blog on the day of at. Increments for a new
+// blog day are dropped when the buffer is full; it returns false then.
+func (b *Buffer) Add(blogID uint, at time.Time, delta Counts) bool {
+	key := Key{BlogID: blogID, Day: at.UTC().Format("2006-01-02")}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	counts, ok := b.pending[key]
+	if !ok {
+		if len(b.pending) >= b.maxKeys {
+			b.dropped++
+			return false
+		}
+		counts = &Counts{}
+		b.pending[key] = counts
+	}
+
+	counts.Views += delta.Views
+	counts.Likes += delta.Likes
+	counts.Shares += delta.Shares
+	for i := range counts.Scroll {
+		counts.Scroll[i] += delta.Scroll[i]
+	}
+
+	if len(b.pending) >= b.threshold {
+		select {
+		case b.ready <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+// Ready is signalled when enough blog days are pending to flush early
+func (b *Buffer) Ready() <-chan struct{} {
+	return b.ready
+}
+
+// Drain removes and returns the pending increments and the number of blog
+// days dropped since the last drain
+func (b *Buffer) Drain() (map[Key]Counts, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	drained := make(map[Key]Counts, len(b.pending))
+	for key, counts := range b.pending {
+		if !counts.IsZero() {
+			drained[key] = *counts
+		}
+	}
+	dropped := b.dropped
+	b.pending = map[Key]*Counts{}
+	b.dropped = 0
+	return drained, dropped
+}
+
+// Restore puts increments that could not be written back into the buffer
+func (b *Buffer) Restore(counts map[Key]Counts) {
+	for key, delta := range counts {
+		day, err := time.Parse("2006-01-02", key.Day)
+		if err != nil {
+			continue
+		}
+		b.Add(key.BlogID, day, delta)
+	}
+}
+
+// Deduper remembers keys for a time window so repeated hits from the same
+// visitor or session are counted once. It is safe for concurrent use.
+type Deduper struct {
+	mu      sync.Mutex
+	window  time.Duration
+	maxKeys int
+	seen    map[string]time.Time
+}
+
+// NewDeduper creates a deduper remembering at most maxKeys keys for window
+func NewDeduper(window time.Duration, maxKeys int) *Deduper {
+	if maxKeys <= 0 {
+		maxKeys = 1000000
+	}
+	return &Deduper{window: window, maxKeys: maxKeys, seen: map[string]time.Time{}}
+}
+
+// First reports whether key was not seen within the window before now, and
+// remembers it. When the deduper is full after dropping expired keys, it
+// starts over, so a few hits may be counted twice rather than memory growing.
+func (d *Deduper) First(key string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if seenAt, ok := d.seen[key]; ok && now.Sub(seenAt) < d.window {
+		return false
+	}
+
+	if len(d.seen) >= d.maxKeys {
+		d.sweep(now)
+		if len(d.seen) >= d.maxKeys {
+			d.seen = map[string]time.Time{}
+		}
+	}
+	d.seen[key] = now
+	return true
+}
+
+// Sweep drops keys older than the window
+func (d *Deduper) Sweep(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sweep(now)
+}
+
+func (d *Deduper) sweep(now time.Time) {
+	for key, seenAt := range d.seen {
+		if now.Sub(seenAt) >= d.window {
+			delete(d.seen, key)
+		}
+	}
+}
+
+// ScrollHeatmap returns, for each scroll milestone, the share of views that
+// reached it and an estimated average scroll depth. reached holds the number
+// of sessions reaching each of ScrollBuckets.
+func ScrollHeatmap(views int, reached [4]int) (avgDepth float64, shares [4]float64) {
+	if views <= 0 {
+		return 0, shares
+	}
+
+	var depthSum float64
+	for i, milestone := range ScrollBuckets {
+		shares[i] = min(float64(reached[i])/float64(views), 1)
+		deeper := 0
+		if i+1 < len(reached) {
+			deeper = reached[i+1]
+		}
+		// Sessions that stopped between this milestone and the next
+		depthSum += float64(max(reached[i]-deeper, 0) * milestone)
+	}
+
+	return min(depthSum/float64(views), 100), shares
+}
+
+// BounceRate is the percentage of views that left before scrolling 25% of the post
+func BounceRate(views, scrolled int) float64 {
+	if views <= 0 {
+		return 0
+	}
+	return float64(max(views-scrolled, 0)) / float64(views) * 100
+}
@@ -0,0 +1,154 @@
+package leadmatch
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Default similarity thresholds for fuzzy person matching
+const (
+	DefaultNameThreshold    = 0.92
+	DefaultCompanyThreshold = 0.88
+)
+
+// gmailDomains are mailbox providers that ignore dots in the local part
+var gmailDomains = map[string]bool{"gmail.com": true, "googlemail.com": true}
+
+// companySuffixes are legal-form words ignored when comparing company names
+var companySuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "llc": true, "ltd": true, "limited": true, "corp": true,
+	"corporation": true, "co": true, "company": true, "gmbh": true, "ag": true, "sa": true,
+	"plc": true, "pvt": true, "private": true, "llp": true, "bv": true, "srl": true, "the": true,
+}
+
+// NormalizeEmail returns the canonical form of an email address used for
+// duplicate detection: lowercased, without a +tag, and for Gmail without
+// dots in the local part. Input without an @ is only trimmed and lowercased.
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || at == len(email)-1 {
+		return email
+	}
+
+	local, domain := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+	if gmailDomains[domain] {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+
+	return local + "@" + domain
+}
+
+// NormalizeName lowercases a person name, strips accents and punctuation and
+// collapses whitespace
+func NormalizeName(name string) string {
+	return strings.Join(words(name), " ")
+}
+
+// NormalizeCompany normalizes a company name like NormalizeName and drops
+// legal-form words such as Inc, Ltd or GmbH
+func NormalizeCompany(company string) string {
+	kept := []string{}
+	for _, word := range words(company) {
+		if !companySuffixes[word] {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// Similarity returns the Jaro-Winkler similarity of two strings, from 0
+// (nothing in common) to 1 (identical)
+func Similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// SamePerson reports whether two name and company pairs likely describe the
+// same person. Both names and both companies must be present.
+func SamePerson(nameA, companyA, nameB, companyB string, nameThreshold, companyThreshold float64) bool {
+	na, nb := NormalizeName(nameA), NormalizeName(nameB)
+	ca, cb := NormalizeCompany(companyA), NormalizeCompany(companyB)
+	if na == "" || nb == "" || ca == "" || cb == "" {
+		return false
+	}
+
+	return Similarity(na, nb) >= nameThreshold && Similarity(ca, cb) >= companyThreshold
+}
+
+// words splits text into lowercase ASCII-folded words of letters and digits
+func words(text string) []string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// combining diacritic left over from decomposition
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '\'' || r == '.':
+			// O'Brien, J.R. and Co. keep their letters together
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
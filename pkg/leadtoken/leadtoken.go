@@ -0,0 +1,66 @@
+package leadtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Token purposes. A token signed for one purpose is rejected for another.
+const (
+	PurposeTracking = "tracking"
+	PurposeOptOut   = "opt_out"
+	PurposeConsent  = "consent" // confirming consent of an opted-out or quarantined lead
+)
+
+// Errors returned by Verify
+var (
+	ErrMalformed        = errors.New("malformed lead token")
+	ErrInvalidSignature = errors.New("invalid lead token signature")
+	ErrExpired          = errors.New("lead token expired")
+)
+
+// Sign returns a token identifying a lead for a purpose until expiresAt.
+// The token has the form <lead id>.<expiry unix seconds>.<signature>.
+func Sign(purpose string, leadID uint, expiresAt time.Time, secret []byte) string {
+	payload := strconv.FormatUint(uint64(leadID), 10) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + signature(purpose, payload, secret)
+}
+
+// Verify checks a token's signature and expiry for a purpose and returns the lead ID
+func Verify(purpose, token string, secret []byte, now time.Time) (uint, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, ErrMalformed
+	}
+
+	leadID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || leadID == 0 {
+		return 0, ErrMalformed
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, ErrMalformed
+	}
+
+	expected := signature(purpose, parts[0]+"."+parts[1], secret)
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return 0, ErrInvalidSignature
+	}
+	if now.Unix() >= expires {
+		return 0, ErrExpired
+	}
+
+	return uint(leadID), nil
+}
+
+// signature is the URL-safe HMAC-SHA256 of a purpose and payload
+func signature(purpose, payload string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose + ":" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,85 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured is returned by Send when SMTP_HOST is not set
+var ErrNotConfigured = errors.New("smtp not configured")
+
+// Mailer sends plain-text emails through an SMTP server
+type Mailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+	fromName string
+}
+
+// FromEnv creates a mailer from SMTP_HOST, SMTP_PORT, SMTP_USERNAME,
+// SMTP_PASSWORD, SMTP_FROM_EMAIL and SMTP_FROM_NAME
+func FromEnv() *Mailer {
+	return &Mailer{
+		host:     os.Getenv("SMTP_HOST"),
+		port:     getEnv("SMTP_PORT", "587"),
+		username: os.Getenv("SMTP_USERNAME"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     os.Getenv("SMTP_FROM_EMAIL"),
+		fromName: os.Getenv("SMTP_FROM_NAME"),
+	}
+}
+
+// Send sends a plain-text email to one recipient. The server must offer
+// STARTTLS when credentials are configured.
+func (m *Mailer) Send(to, subject, body string) error {
+	if m.host == "" || m.from == "" {
+		return ErrNotConfigured
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+	msg := Message(m.from, m.fromName, to, subject, body, time.Now())
+	return smtp.SendMail(net.JoinHostPort(m.host, m.port), auth, m.from, []string{to}, msg)
+}
+
+// Message formats a plain-text email. Line breaks in header values are
+// removed so they cannot add headers.
+func Message(from, fromName, to, subject, body string, date time.Time) []byte {
+	sender := headerValue(from)
+	if fromName != "" {
+		sender = fmt.Sprintf("%s <%s>", mime.QEncoding.Encode("utf-8", headerValue(fromName)), sender)
+	}
+
+	var b strings.Builder
+	b.WriteString("From: " + sender + "\r\n")
+	b.WriteString("To: " + headerValue(to) + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", headerValue(subject)) + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// headerValue strips line breaks from a header value
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
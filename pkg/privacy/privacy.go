@@ -0,0 +1,150 @@
+package privacy
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Consent types a lead form can declare
+const (
+	ConsentGDPR    = "gdpr"
+	ConsentCCPA    = "ccpa"
+	ConsentGeneral = "general"
+)
+
+// Modes for captures without a required consent
+const (
+	ModeReject     = "reject"
+	ModeQuarantine = "quarantine"
+)
+
+// Decisions for a capture
+const (
+	DecisionAccept     = "accept"
+	DecisionReject     = "reject"
+	DecisionQuarantine = "quarantine"
+)
+
+// RedactedValue replaces personal data in free text
+const RedactedValue = "[erased]"
+
+// Policy decides what happens to captures that lack the consent their
+// consent type requires
+type Policy struct {
+	Mode     string
+	Required map[string]bool
+}
+
+// NewPolicy creates a policy requiring consent for the given consent types.
+// Unknown modes fall back to quarantine, which never loses a capture.
+func NewPolicy(mode string, requiredTypes []string) Policy {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode != ModeReject {
+		mode = ModeQuarantine
+	}
+	policy := Policy{Mode: mode, Required: map[string]bool{}}
+	for _, consentType := range requiredTypes {
+		if consentType = strings.ToLower(strings.TrimSpace(consentType)); consentType != "" {
+			policy.Required[consentType] = true
+		}
+	}
+	return policy
+}
+
+// Evaluate returns the decision for a capture with the given consent
+func (p Policy) Evaluate(consentGiven bool, consentType string) string {
+	if consentGiven || !p.Required[strings.ToLower(strings.TrimSpace(consentType))] {
+		return DecisionAccept
+	}
+	if p.Mode == ModeReject {
+		return DecisionReject
+	}
+	return DecisionQuarantine
+}
+
+// ErasedEmail is the placeholder address of an erased lead; the .invalid
+// domain can never receive mail
+func ErasedEmail(leadID uint) string {
+	return fmt.Sprintf("erased-%d@erased.invalid", leadID)
+}
+
+// emailPattern matches email addresses in free text
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// Redact replaces email addresses and, case-insensitively, each of values
+// (names, phone numbers and the like) in s. Values shorter than three
+// characters are ignored so they do not mangle unrelated words.
+func Redact(s string, values []string) string {
+	if s == "" {
+		return s
+	}
+	s = emailPattern.ReplaceAllString(s, RedactedValue)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < 3 {
+			continue
+		}
+		s = regexp.MustCompile(`(?i)`+regexp.QuoteMeta(value)).ReplaceAllString(s, RedactedValue)
+	}
+	return s
+}
+
+// personalKeys are metadata keys whose values are removed on erasure
+var personalKeys = map[string]bool{
+	"email": true, "name": true, "first_name": true, "last_name": true, "phone": true,
+	"company": true, "job_title": true, "website": true, "linkedin_profile": true,
+	"ip_address": true, "user_agent": true, "city": true, "region": true, "notes": true,
+}
+
+// RedactMap returns a copy of m without personal keys and with string values
+// redacted, descending into nested maps and arrays
+func RedactMap(m map[string]interface{}, values []string) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		if personalKeys[strings.ToLower(key)] {
+			continue
+		}
+		redacted[key] = redactValue(value, values)
+	}
+	return redacted
+}
+
+func redactValue(value interface{}, values []string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return Redact(v, values)
+	case map[string]interface{}:
+		return RedactMap(v, values)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			out[i] = redactValue(v[i], values)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// StripQuery removes the query string and fragment from a URL, where email
+// links and forms often carry personal data
+func StripQuery(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		if i := strings.IndexAny(rawURL, "?#"); i >= 0 {
+			return rawURL[:i]
+		}
+		return rawURL
+	}
+	parsed.RawQuery = ""
+	parsed.ForceQuery = false
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+	parsed.User = nil
+	return parsed.String()
+}
@@ -0,0 +1,140 @@
+package search
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultSnippetLength is the approximate snippet length in characters
+const DefaultSnippetLength = 200
+
+// MinTermLength is the shortest query word used for highlighting
+const MinTermLength = 2
+
+// MarkOpen and MarkClose wrap highlighted terms
+const (
+	MarkOpen  = "<mark>"
+	MarkClose = "</mark>"
+)
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	markdownPattern   = regexp.MustCompile("[#*_`>~|]+|!?\\[([^\\]]*)\\]\\([^)]*\\)")
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// Terms extracts the distinct, lowercased words of a search query. Boolean-mode
+// operators and words shorter than MinTermLength are dropped.
+func Terms(query string) []string {
+	seen := make(map[string]bool)
+	terms := []string{}
+
+	fields := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, field := range fields {
+		if utf8.RuneCountInString(field) < MinTermLength || seen[field] {
+			continue
+		}
+		seen[field] = true
+		terms = append(terms, field)
+	}
+
+	return terms
+}
+
+// PlainText strips HTML tags and Markdown markup and collapses whitespace
+func PlainText(content string) string {
+	text := htmlTagPattern.ReplaceAllString(content, " ")
+	text = html.UnescapeString(text)
+	text = markdownPattern.ReplaceAllString(text, "$1")
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " "))
+}
+
+// Highlight HTML-escapes text and wraps every occurrence of a term in <mark> tags
+func Highlight(text string, terms []string) string {
+	pattern := termPattern(terms)
+	if pattern == nil {
+		return html.EscapeString(text)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString(MarkOpen)
+		b.WriteString(html.EscapeString(text[loc[0]:loc[1]]))
+		b.WriteString(MarkClose)
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+
+	return b.String()
+}
+
+// Snippet returns a highlighted excerpt of about length characters centred on
+// the first matching term. Without a match it returns the start of the text.
+func Snippet(text string, terms []string, length int) string {
+	if length <= 0 {
+		length = DefaultSnippetLength
+	}
+
+	runes := []rune(text)
+	if len(runes) <= length {
+		return Highlight(text, terms)
+	}
+
+	start := 0
+	if pattern := termPattern(terms); pattern != nil {
+		if loc := pattern.FindStringIndex(text); loc != nil {
+			matchRune := utf8.RuneCountInString(text[:loc[0]])
+			start = matchRune - length/3
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > len(runes)-length {
+		start = len(runes) - length
+	}
+	end := start + length
+
+	// Snap to word boundaries so snippets do not start or end mid-word
+	for start > 0 && !unicode.IsSpace(runes[start-1]) {
+		start--
+	}
+	for end < len(runes) && !unicode.IsSpace(runes[end]) {
+		end++
+	}
+
+	snippet := Highlight(strings.TrimSpace(string(runes[start:end])), terms)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// termPattern builds a case-insensitive alternation of terms, longest first
+func termPattern(terms []string) *regexp.Regexp {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, regexp.QuoteMeta(term))
+	}
+	// Longer terms first so "marketing" wins over "market"
+	sort.SliceStable(quoted, func(i, j int) bool {
+		return len(quoted[i]) > len(quoted[j])
+	})
+
+	return regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+}
@@ -0,0 +1,117 @@
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the maximum slug length stored in blogs.slug
+const MaxLength = 255
+
+// Fallback is used when a title contains nothing that can be transliterated
+const Fallback = "post"
+
+// transliterations covers letters that do not decompose into ASCII base letters
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+	'&': " and ", '@': " at ",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i",
+	'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s",
+	'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// Make converts text into a lowercase, hyphen-separated ASCII slug.
+// Accented letters are reduced to their base letter and common non-Latin
+// scripts are transliterated. Returns Fallback when nothing usable remains.
+func Make(text string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	write := func(s string) {
+		for _, r := range s {
+			if r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				if pendingHyphen && b.Len() > 0 {
+					b.WriteByte('-')
+				}
+				pendingHyphen = false
+				b.WriteRune(r)
+				continue
+			}
+			pendingHyphen = true
+		}
+	}
+
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue // combining diacritic left over from decomposition
+		}
+		if t, ok := transliterations[r]; ok {
+			write(t)
+			continue
+		}
+		write(string(r))
+	}
+
+	result := truncate(b.String(), MaxLength)
+	if result == "" {
+		return Fallback
+	}
+	return result
+}
+
+// maxSuffixLength is the room kept for a numeric suffix (a hyphen and up to seven digits)
+const maxSuffixLength = 8
+
+// Stem returns the prefix shared by every suffixed variant of base: base
+// shortened to leave room for a suffix
+func Stem(base string) string {
+	return truncate(base, MaxLength-maxSuffixLength)
+}
+
+// WithSuffix appends a numeric suffix to the stem of base, so the result fits
+// MaxLength and all suffixed variants start with Stem(base)
+func WithSuffix(base string, n int) string {
+	return Stem(base) + "-" + strconv.Itoa(n)
+}
+
+// Unique returns base, or base with the lowest numeric suffix from 2 upward, that is not in used
+func Unique(base string, used []string) string {
+	taken := make(map[string]bool, len(used))
+	for _, s := range used {
+		taken[s] = true
+	}
+
+	if !taken[base] {
+		return base
+	}
+	for n := 2; ; n++ {
+		candidate := WithSuffix(base, n)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// IsValid reports whether s is already in canonical slug form
+func IsValid(s string) bool {
+	return s != "" && Make(s) == s
+}
+
+// truncate shortens a slug to max bytes without leaving a trailing hyphen
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return strings.TrimRight(s[:max], "-")
+}
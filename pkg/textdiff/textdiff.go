@@ -0,0 +1,126 @@
+package textdiff
+
+import (
+	"strings"
+)
+
+// Line operations
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// maxTableCells bounds the LCS table of a changed region. Larger regions are
+// reported as the old lines deleted and the new lines inserted.
+const maxTableCells = 1 << 22
+
+// Line represents a single line in a line-level diff
+type Line struct {
+	Op        string `json:"op"`
+	Text      string `json:"text"`
+	OldNumber int    `json:"old_number,omitempty"` // 1-based line number in the old text
+	NewNumber int    `json:"new_number,omitempty"` // 1-based line number in the new text
+}
+
+// Diff computes a line-level diff between two texts using the longest common
+// subsequence. Changed regions too large for an LCS table are diffed as a
+// whole block replaced.
+func Diff(oldText, newText string) []Line {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	// Trim the common prefix and suffix so the LCS table only covers the changed region
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]Line, 0, len(oldLines)+len(newLines))
+	for i := 0; i < prefix; i++ {
+		result = append(result, Line{Op: OpEqual, Text: oldLines[i], OldNumber: i + 1, NewNumber: i + 1})
+	}
+
+	result = append(result, diffMiddle(oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix], prefix, prefix)...)
+
+	for i := 0; i < suffix; i++ {
+		oldIndex := len(oldLines) - suffix + i
+		newIndex := len(newLines) - suffix + i
+		result = append(result, Line{Op: OpEqual, Text: oldLines[oldIndex], OldNumber: oldIndex + 1, NewNumber: newIndex + 1})
+	}
+
+	return result
+}
+
+// Stats counts inserted and deleted lines in a diff
+func Stats(lines []Line) (added, removed int) {
+	for _, line := range lines {
+		switch line.Op {
+		case OpInsert:
+			added++
+		case OpDelete:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// diffMiddle diffs the changed region with an LCS table
+func diffMiddle(oldLines, newLines []string, oldOffset, newOffset int) []Line {
+	n, m := len(oldLines), len(newLines)
+	result := make([]Line, 0, n+m)
+
+	i, j := 0, 0
+	if (n+1)*(m+1) <= maxTableCells {
+		// lcs[i*(m+1)+j] holds the LCS length of oldLines[i:] and newLines[j:]
+		width := m + 1
+		lcs := make([]int32, (n+1)*width)
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if oldLines[i] == newLines[j] {
+					lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+				} else {
+					lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+				}
+			}
+		}
+
+		for i < n && j < m {
+			switch {
+			case oldLines[i] == newLines[j]:
+				result = append(result, Line{Op: OpEqual, Text: oldLines[i], OldNumber: oldOffset + i + 1, NewNumber: newOffset + j + 1})
+				i++
+				j++
+			case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+				result = append(result, Line{Op: OpDelete, Text: oldLines[i], OldNumber: oldOffset + i + 1})
+				i++
+			default:
+				result = append(result, Line{Op: OpInsert, Text: newLines[j], NewNumber: newOffset + j + 1})
+				j++
+			}
+		}
+	}
+	for ; i < n; i++ {
+		result = append(result, Line{Op: OpDelete, Text: oldLines[i], OldNumber: oldOffset + i + 1})
+	}
+	for ; j < m; j++ {
+		result = append(result, Line{Op: OpInsert, Text: newLines[j], NewNumber: newOffset + j + 1})
+	}
+
+	return result
+}
+
+// splitLines splits text into lines, normalizing Windows line endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,130 @@
+package tracking
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// Anonymous visitor event types
+const (
+	EventPageView   = "page_view"
+	EventEngagement = "engagement"
+)
+
+// SessionTimeout is the inactivity gap that starts a new visit when the
+// client does not send a session ID
+const SessionTimeout = 30 * time.Minute
+
+// NewVisitorID returns a random anonymous visitor ID
+func NewVisitorID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// IsValidVisitorID reports whether id looks like a visitor ID: 16 to 64
+// letters, digits, dashes or underscores
+func IsValidVisitorID(id string) bool {
+	return len(id) >= 16 && isToken(id)
+}
+
+// IsValidSessionID reports whether id is a usable client session ID: 1 to 64
+// letters, digits, dashes or underscores
+func IsValidSessionID(id string) bool {
+	return id != "" && isToken(id)
+}
+
+// isToken reports whether s has at most 64 letters, digits, dashes or underscores
+func isToken(s string) bool {
+	if len(s) > 64 {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Event is an anonymous visitor event
+type Event struct {
+	Type         string
+	SessionID    string
+	URL          string
+	At           time.Time
+	TimeSpent    int // seconds
+	ScrollDepth  float64
+	Interactions int
+}
+
+// PageVisit is a page view with the engagement reported for it
+type PageVisit struct {
+	Index        int // position of the page view event in the input
+	TimeSpent    int
+	ScrollDepth  float64
+	Interactions int
+}
+
+// History summarizes a visitor's events
+type History struct {
+	PageViews  int
+	TimeOnSite int // seconds
+	Sessions   int
+	Visits     []PageVisit
+}
+
+// Summarize folds events, oldest first, into page visits and totals.
+// Engagement events add to the latest view of the same URL in their session.
+// Events without a session ID are grouped into visits split by SessionTimeout.
+func Summarize(events []Event) History {
+	history := History{}
+	sessions := map[string]bool{}
+	lastView := map[string]int{}
+
+	inferred := 0
+	var lastInferred time.Time
+	for i, event := range events {
+		key := event.SessionID
+		if key == "" {
+			if inferred == 0 || event.At.Sub(lastInferred) > SessionTimeout {
+				inferred++
+			}
+			lastInferred = event.At
+			// Session IDs are alphanumeric, so inferred keys never collide with them
+			key = "\x00" + strconv.Itoa(inferred)
+		}
+		sessions[key] = true
+		history.TimeOnSite += event.TimeSpent
+
+		switch event.Type {
+		case EventPageView:
+			history.PageViews++
+			history.Visits = append(history.Visits, PageVisit{
+				Index:        i,
+				TimeSpent:    event.TimeSpent,
+				ScrollDepth:  event.ScrollDepth,
+				Interactions: event.Interactions,
+			})
+			lastView[key] = len(history.Visits) - 1
+		case EventEngagement:
+			v, ok := lastView[key]
+			if !ok || (event.URL != "" && events[history.Visits[v].Index].URL != event.URL) {
+				continue
+			}
+			visit := &history.Visits[v]
+			visit.TimeSpent += event.TimeSpent
+			visit.ScrollDepth = max(visit.ScrollDepth, event.ScrollDepth)
+			visit.Interactions += event.Interactions
+		}
+	}
+
+	history.Sessions = len(sessions)
+	return history
+}
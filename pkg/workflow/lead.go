@@ -0,0 +1,101 @@
+package workflow
+
+import (
+	"blog-service/pkg/auth"
+	"fmt"
+)
+
+// Lead statuses
+const (
+	LeadStatusNew         = "new"
+	LeadStatusContacted   = "contacted"
+	LeadStatusQualified   = "qualified"
+	LeadStatusUnqualified = "unqualified"
+	LeadStatusNurturing   = "nurturing"
+	LeadStatusConverted   = "converted"
+	LeadStatusLost        = "lost"
+)
+
+// Lead pipeline permissions
+const (
+	leadUpdatePermission = "lead:update"
+	leadReopenPermission = "lead:reopen"
+)
+
+// leadTransitions is the lead pipeline. Converted and lost are terminal; they
+// can only be reopened with lead:reopen.
+var leadTransitions = map[string][]string{
+	LeadStatusNew:         {LeadStatusContacted, LeadStatusQualified, LeadStatusUnqualified, LeadStatusNurturing, LeadStatusLost},
+	LeadStatusContacted:   {LeadStatusQualified, LeadStatusUnqualified, LeadStatusNurturing, LeadStatusConverted, LeadStatusLost},
+	LeadStatusQualified:   {LeadStatusContacted, LeadStatusUnqualified, LeadStatusNurturing, LeadStatusConverted, LeadStatusLost},
+	LeadStatusUnqualified: {LeadStatusContacted, LeadStatusQualified, LeadStatusNurturing, LeadStatusLost},
+	LeadStatusNurturing:   {LeadStatusContacted, LeadStatusQualified, LeadStatusUnqualified, LeadStatusConverted, LeadStatusLost},
+	LeadStatusConverted:   {LeadStatusContacted, LeadStatusQualified, LeadStatusNurturing},
+	LeadStatusLost:        {LeadStatusContacted, LeadStatusQualified, LeadStatusNurturing},
+}
+
+// LeadStatuses returns all lead statuses in pipeline order
+func LeadStatuses() []string {
+	return []string{
+		LeadStatusNew, LeadStatusContacted, LeadStatusQualified, LeadStatusUnqualified,
+		LeadStatusNurturing, LeadStatusConverted, LeadStatusLost,
+	}
+}
+
+// IsValidLeadStatus checks if a status belongs to the lead pipeline
+func IsValidLeadStatus(status string) bool {
+	for _, s := range LeadStatuses() {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminalLeadStatus reports whether a lead status ends the pipeline
+func IsTerminalLeadStatus(status string) bool {
+	return status == LeadStatusConverted || status == LeadStatusLost
+}
+
+// FindLeadTransition returns the transition between two lead statuses, if allowed
+func FindLeadTransition(from, to string) (Transition, bool) {
+	for _, allowed := range leadTransitions[from] {
+		if allowed == to {
+			permission := leadUpdatePermission
+			if IsTerminalLeadStatus(from) {
+				permission = leadReopenPermission
+			}
+			return Transition{From: from, To: to, Permission: permission}, true
+		}
+	}
+	return Transition{}, false
+}
+
+// AuthorizeLead checks that a role may move a lead from one status to another
+func AuthorizeLead(role, from, to string) error {
+	if !IsValidLeadStatus(to) {
+		return fmt.Errorf("%w: %s", ErrInvalidStatus, to)
+	}
+
+	t, ok := FindLeadTransition(from, to)
+	if !ok {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+
+	if !auth.HasPermission(role, t.Permission) {
+		return fmt.Errorf("%w: %s requires %s", ErrPermissionDenied, to, t.Permission)
+	}
+
+	return nil
+}
+
+// AvailableLeadTransitions lists the statuses a role can move a lead to from its current status
+func AvailableLeadTransitions(role, from string) []Transition {
+	available := []Transition{}
+	for _, to := range leadTransitions[from] {
+		if t, ok := FindLeadTransition(from, to); ok && auth.HasPermission(role, t.Permission) {
+			available = append(available, t)
+		}
+	}
+	return available
+}
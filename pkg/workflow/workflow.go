@@ -0,0 +1,105 @@
+package workflow
+
+import (
+	"blog-service/pkg/auth"
+	"errors"
+	"fmt"
+)
+
+// Blog workflow statuses
+const (
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusApproved  = "approved"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+var (
+	// ErrInvalidStatus is returned for statuses outside the workflow
+	ErrInvalidStatus = errors.New("invalid status")
+	// ErrInvalidTransition is returned when the workflow does not allow a move
+	ErrInvalidTransition = errors.New("status transition not allowed")
+	// ErrPermissionDenied is returned when the role lacks the transition permission
+	ErrPermissionDenied = errors.New("insufficient permissions for status transition")
+)
+
+// Transition describes an allowed move between two statuses and the
+// permission required to perform it
+type Transition struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Permission string `json:"permission"`
+}
+
+// transitions is the editorial workflow:
+// draft -> in_review -> approved -> scheduled -> published -> archived
+var transitions = []Transition{
+	{From: StatusDraft, To: StatusInReview, Permission: "blog:update"},
+	{From: StatusDraft, To: StatusArchived, Permission: "blog:unpublish"},
+	{From: StatusInReview, To: StatusDraft, Permission: "blog:moderate"},
+	{From: StatusInReview, To: StatusApproved, Permission: "blog:moderate"},
+	{From: StatusApproved, To: StatusDraft, Permission: "blog:update"},
+	{From: StatusApproved, To: StatusScheduled, Permission: "blog:publish"},
+	{From: StatusApproved, To: StatusPublished, Permission: "blog:publish"},
+	{From: StatusScheduled, To: StatusApproved, Permission: "blog:publish"},
+	{From: StatusScheduled, To: StatusPublished, Permission: "blog:publish"},
+	{From: StatusPublished, To: StatusDraft, Permission: "blog:unpublish"},
+	{From: StatusPublished, To: StatusArchived, Permission: "blog:unpublish"},
+	{From: StatusArchived, To: StatusDraft, Permission: "blog:unpublish"},
+}
+
+// Statuses returns all workflow statuses in workflow order
+func Statuses() []string {
+	return []string{StatusDraft, StatusInReview, StatusApproved, StatusScheduled, StatusPublished, StatusArchived}
+}
+
+// IsValidStatus checks if a status belongs to the workflow
+func IsValidStatus(status string) bool {
+	for _, s := range Statuses() {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// FindTransition returns the transition between two statuses, if allowed
+func FindTransition(from, to string) (Transition, bool) {
+	for _, t := range transitions {
+		if t.From == from && t.To == to {
+			return t, true
+		}
+	}
+	return Transition{}, false
+}
+
+// Authorize checks that a role may move a blog from one status to another
+func Authorize(role, from, to string) error {
+	if !IsValidStatus(to) {
+		return fmt.Errorf("%w: %s", ErrInvalidStatus, to)
+	}
+
+	t, ok := FindTransition(from, to)
+	if !ok {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+
+	if !auth.HasPermission(role, t.Permission) {
+		return fmt.Errorf("%w: %s requires %s", ErrPermissionDenied, to, t.Permission)
+	}
+
+	return nil
+}
+
+// AvailableTransitions lists the statuses a role can move a blog to from its current status
+func AvailableTransitions(role, from string) []Transition {
+	available := []Transition{}
+	for _, t := range transitions {
+		if t.From == from && auth.HasPermission(role, t.Permission) {
+			available = append(available, t)
+		}
+	}
+	return available
+}
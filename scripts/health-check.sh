@@ -31,6 +31,9 @@ readonly DEEP_HEALTH_ENDPOINT="$BASE_URL/health/deep"
 readonly METRICS_ENDPOINT="$BASE_URL/metrics"
 readonly API_TEST_ENDPOINT="$BASE_URL/api/v1/test"
 
+# Identifies these checks so the service never counts them as visitors
+readonly USER_AGENT="mejona-health-check/1.0"
+
 # Alert thresholds
 readonly MAX_RESPONSE_TIME=5000    # milliseconds
 readonly MAX_CPU_PERCENT=80
@@ -109,7 +112,7 @@ check_health_endpoint() {
     
     start_time=$(date +%s%3N)
     
-    if response_code=$(curl -s -w "%{http_code}" -o /tmp/health_response.json "$HEALTH_ENDPOINT" --connect-timeout 10 --max-time 30); then
+    if response_code=$(curl -s -A "$USER_AGENT" -w "%{http_code}" -o /tmp/health_response.json "$HEALTH_ENDPOINT" --connect-timeout 10 --max-time 30); then
         end_time=$(date +%s%3N)
         response_time=$((end_time - start_time))
         
@@ -138,7 +141,7 @@ check_health_endpoint() {
 check_deep_health() {
     local response_code
     
-    if response_code=$(curl -s -w "%{http_code}" -o /tmp/deep_health_response.json "$DEEP_HEALTH_ENDPOINT" --connect-timeout 10 --max-time 30); then
+    if response_code=$(curl -s -A "$USER_AGENT" -w "%{http_code}" -o /tmp/deep_health_response.json "$DEEP_HEALTH_ENDPOINT" --connect-timeout 10 --max-time 30); then
         if [[ "$response_code" == "200" ]]; then
             success "Deep health check passed"
             log_health "DEEP_HEALTH: PASSED"
@@ -167,7 +170,7 @@ check_deep_health() {
 check_api_endpoint() {
     local response_code
     
-    if response_code=$(curl -s -w "%{http_code}" -o /tmp/api_test_response.json "$API_TEST_ENDPOINT" --connect-timeout 10 --max-time 30); then
+    if response_code=$(curl -s -A "$USER_AGENT" -w "%{http_code}" -o /tmp/api_test_response.json "$API_TEST_ENDPOINT" --connect-timeout 10 --max-time 30); then
         if [[ "$response_code" == "200" ]]; then
             success "API test endpoint is working"
             log_health "API_TEST: OK"
@@ -359,7 +362,7 @@ generate_health_report() {
         echo
         
         echo "HEALTH CHECK RESULTS:"
-        curl -s "$HEALTH_ENDPOINT" | jq . 2>/dev/null || curl -s "$HEALTH_ENDPOINT" || echo "Health endpoint not accessible"
+        curl -s -A "$USER_AGENT" "$HEALTH_ENDPOINT" | jq . 2>/dev/null || curl -s -A "$USER_AGENT" "$HEALTH_ENDPOINT" || echo "Health endpoint not accessible"
         
     } > "$report_file"
     
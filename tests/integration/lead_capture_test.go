@@ -0,0 +1,178 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"blog-service/internal/handlers"
+	"blog-service/internal/models"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+)
+
+// LeadCaptureIntegrationSuite tests the public lead capture endpoint against the test database
+type LeadCaptureIntegrationSuite struct {
+	suite.Suite
+	router *gin.Engine
+	blog   models.Blog
+}
+
+// SetupSuite migrates the lead tables and creates a published post to capture on
+func (suite *LeadCaptureIntegrationSuite) SetupSuite() {
+	if err := godotenv.Load("../../.env.test"); err != nil {
+		suite.T().Logf("No .env.test file found, using system environment")
+	}
+	gin.SetMode(gin.TestMode)
+	os.Setenv("GIN_MODE", "test")
+	logger.InitLogger()
+
+	suite.Require().NoError(database.InitDB(), "Failed to initialize test database")
+	suite.Require().NoError(database.AutoMigrate(
+		&models.Category{},
+		&models.Blog{},
+		&models.BlogLead{},
+		&models.LeadActivity{},
+		&models.LeadTouchpoint{},
+		&models.LeadAssignmentRule{},
+		&models.VisitorEvent{},
+	))
+
+	now := time.Now()
+	suite.blog = models.Blog{
+		Title:       "Lead capture integration test",
+		Slug:        fmt.Sprintf("lead-capture-integration-%d", now.UnixNano()),
+		AuthorID:    1,
+		Status:      workflow.StatusPublished,
+		PublishedAt: &now,
+	}
+	suite.Require().NoError(database.GetDB().Create(&suite.blog).Error)
+
+	suite.router = gin.New()
+	suite.router.POST("/api/v1/blogs/:id/leads", handlers.NewLeadHandler().CaptureLead)
+}
+
+// TearDownSuite removes the post and the leads captured on it
+func (suite *LeadCaptureIntegrationSuite) TearDownSuite() {
+	db := database.GetDB()
+	var leadIDs []uint
+	db.Model(&models.BlogLead{}).Where("blog_id = ?", suite.blog.ID).Pluck("id", &leadIDs)
+	if len(leadIDs) > 0 {
+		db.Where("lead_id IN ?", leadIDs).Delete(&models.LeadActivity{})
+		db.Where("lead_id IN ?", leadIDs).Delete(&models.LeadTouchpoint{})
+		db.Unscoped().Where("id IN ?", leadIDs).Delete(&models.BlogLead{})
+	}
+	db.Unscoped().Delete(&suite.blog)
+}
+
+// capture posts a lead capture and returns the status and raw body
+func (suite *LeadCaptureIntegrationSuite) capture(body map[string]interface{}) (int, string) {
+	payload, err := json.Marshal(body)
+	suite.Require().NoError(err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/blogs/"+strconv.FormatUint(uint64(suite.blog.ID), 10)+"/leads", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+
+	data, err := io.ReadAll(recorder.Body)
+	suite.Require().NoError(err)
+	return recorder.Code, string(data)
+}
+
+// TestDuplicateCaptureRevealsNothing tests that capturing a known email again
+// returns none of the stored lead's data or credentials
+func (suite *LeadCaptureIntegrationSuite) TestDuplicateCaptureRevealsNothing() {
+	email := fmt.Sprintf("capture-%d@example.com", time.Now().UnixNano())
+	status, body := suite.capture(map[string]interface{}{
+		"email":         email,
+		"name":          "Stored Person",
+		"company":       "Stored Company",
+		"job_title":     "Stored Title",
+		"phone":         "+1 555 0100",
+		"source_type":   "newsletter",
+		"consent_given": true,
+		"consent_type":  "gdpr",
+	})
+	suite.Require().Equal(http.StatusCreated, status, body)
+
+	var created struct {
+		Data models.BlogLeadResponse `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal([]byte(body), &created))
+	suite.NotEmpty(created.Data.LeadToken)
+
+	status, body = suite.capture(map[string]interface{}{
+		"email":         email,
+		"name":          "Someone Else",
+		"source_type":   "newsletter",
+		"consent_given": true,
+		"consent_type":  "gdpr",
+	})
+	suite.Require().Equal(http.StatusOK, status, body)
+
+	var recaptured struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal([]byte(body), &recaptured))
+	suite.Equal(map[string]interface{}{"received": true}, recaptured.Data)
+	for _, stored := range []string{email, "Stored Person", "Stored Company", "Stored Title", "555 0100",
+		"lead_token", "opt_out_url", created.Data.LeadToken} {
+		suite.NotContains(body, stored)
+	}
+}
+
+// TestFuzzyMatchKeepsOtherEmail tests that a capture matched on name and
+// company under another address keeps that address on the lead
+func (suite *LeadCaptureIntegrationSuite) TestFuzzyMatchKeepsOtherEmail() {
+	os.Setenv("LEAD_FUZZY_MATCHING", "true")
+	defer os.Unsetenv("LEAD_FUZZY_MATCHING")
+
+	suffix := time.Now().UnixNano()
+	company := fmt.Sprintf("Fuzzymatch%d Labs", suffix)
+	first := fmt.Sprintf("work-%d@example.com", suffix)
+	other := fmt.Sprintf("home-%d@example.com", suffix)
+	for _, email := range []string{first, other} {
+		status, body := suite.capture(map[string]interface{}{
+			"email":       email,
+			"name":        "Dana Fuzzy",
+			"company":     company,
+			"source_type": "newsletter",
+		})
+		suite.Require().Contains([]int{http.StatusCreated, http.StatusOK}, status, body)
+	}
+
+	var leads []models.BlogLead
+	suite.Require().NoError(database.GetDB().Where("company = ?", company).Find(&leads).Error)
+	suite.Require().Len(leads, 1)
+	suite.Equal(first, leads[0].Email)
+	suite.Equal([]interface{}{other}, leads[0].CustomFields["alternate_emails"])
+
+	var added int64
+	suite.Require().NoError(database.GetDB().Model(&models.LeadActivity{}).
+		Where("lead_id = ? AND activity_type = ?", leads[0].ID, models.LeadActivityEmailAdded).
+		Count(&added).Error)
+	suite.Equal(int64(1), added)
+}
+
+// TestLeadCaptureIntegration runs the lead capture integration suite
+func TestLeadCaptureIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+	suite.Run(t, new(LeadCaptureIntegrationSuite))
+}
@@ -0,0 +1,191 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"blog-service/internal/models"
+	"blog-service/internal/workers"
+	"blog-service/pkg/database"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/workflow"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// MetricsRollupIntegrationSuite tests the metrics rollup worker against the
+// test database. Its rows are dated in 2001 so they stay clear of real data.
+type MetricsRollupIntegrationSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	blog models.Blog
+	lead models.BlogLead
+}
+
+// rollupDay returns noon UTC of a day in March 2001
+func rollupDay(d int) time.Time {
+	return time.Date(2001, 3, d, 12, 0, 0, 0, time.UTC)
+}
+
+// SetupSuite migrates the rollup source and target tables
+func (suite *MetricsRollupIntegrationSuite) SetupSuite() {
+	if err := godotenv.Load("../../.env.test"); err != nil {
+		suite.T().Logf("No .env.test file found, using system environment")
+	}
+	os.Setenv("GIN_MODE", "test")
+	logger.InitLogger()
+
+	suite.Require().NoError(database.InitDB(), "Failed to initialize test database")
+	suite.Require().NoError(database.AutoMigrate(
+		&models.Blog{},
+		&models.BlogLead{},
+		&models.LeadTouchpoint{},
+		&models.VisitorEvent{},
+		&models.BlogEngagementDaily{},
+		&models.BlogDailyMetrics{},
+	))
+	suite.db = database.GetDB()
+}
+
+// SetupTest creates a blog with a lead captured on March 1 and converted on March 10
+func (suite *MetricsRollupIntegrationSuite) SetupTest() {
+	suite.blog = models.Blog{
+		Title:    "Rollup integration test",
+		Slug:     fmt.Sprintf("rollup-integration-%d", time.Now().UnixNano()),
+		AuthorID: 1,
+		Status:   workflow.StatusPublished,
+	}
+	suite.Require().NoError(suite.db.Create(&suite.blog).Error)
+
+	converted := rollupDay(10)
+	suite.lead = models.BlogLead{
+		Email:           fmt.Sprintf("rollup-%d@example.com", time.Now().UnixNano()),
+		BlogID:          suite.blog.ID,
+		SourceType:      "newsletter",
+		Status:          workflow.LeadStatusConverted,
+		CapturedAt:      rollupDay(1),
+		ConvertedAt:     &converted,
+		ConversionValue: 100,
+	}
+	suite.Require().NoError(suite.db.Create(&suite.lead).Error)
+	suite.age(&models.BlogLead{}, suite.lead.ID)
+}
+
+// TearDownTest removes the test rows and the rollups of March 2001
+func (suite *MetricsRollupIntegrationSuite) TearDownTest() {
+	suite.db.Where("lead_id = ?", suite.lead.ID).Delete(&models.LeadTouchpoint{})
+	suite.db.Unscoped().Delete(&suite.lead)
+	suite.db.Unscoped().Delete(&suite.blog)
+	suite.db.Where("date >= ? AND date < ?", "2001-03-01", "2001-04-01").Delete(&models.BlogDailyMetrics{})
+}
+
+// age moves a row's updated_at an hour back, as if it was written before the previous run
+func (suite *MetricsRollupIntegrationSuite) age(model interface{}, id uint) {
+	suite.Require().NoError(suite.db.Model(model).Where("id = ?", id).
+		UpdateColumn("updated_at", time.Now().Add(-time.Hour)).Error)
+}
+
+// touchpoint stores a CTA click of the test lead that happened at occurredAt
+func (suite *MetricsRollupIntegrationSuite) touchpoint(occurredAt time.Time) models.LeadTouchpoint {
+	tp := models.LeadTouchpoint{
+		LeadID:         suite.lead.ID,
+		TouchpointType: models.TouchpointCTAClick,
+		BlogID:         &suite.blog.ID,
+		CreatedAt:      occurredAt,
+	}
+	suite.Require().NoError(suite.db.Create(&tp).Error)
+	return tp
+}
+
+// attribute gives a touchpoint the lead's whole conversion value
+func (suite *MetricsRollupIntegrationSuite) attribute(tp *models.LeadTouchpoint) {
+	suite.Require().NoError(suite.db.Model(tp).Updates(map[string]interface{}{
+		"attribution_weight": 1,
+		"conversion_value":   100,
+	}).Error)
+}
+
+// blogDay returns the test blog's rollup of a day, without its ID and run time
+func (suite *MetricsRollupIntegrationSuite) blogDay(d time.Time) []models.BlogDailyMetrics {
+	var rows []models.BlogDailyMetrics
+	suite.Require().NoError(suite.db.Where("date = ? AND blog_id = ?", d.Format("2006-01-02"), suite.blog.ID).Find(&rows).Error)
+	for i := range rows {
+		rows[i].ID, rows[i].RolledUpAt = 0, time.Time{}
+	}
+	return rows
+}
+
+// TestRollUpRebuildsBackdatedTouchpointDays tests that a touchpoint stored now
+// but dated in the past rebuilds the day it happened
+func (suite *MetricsRollupIntegrationSuite) TestRollUpRebuildsBackdatedTouchpointDays() {
+	rollup := workers.NewMetricsRollup(time.Minute)
+	_, _, err := rollup.Backfill(rollupDay(1), rollupDay(1))
+	suite.Require().NoError(err)
+
+	suite.touchpoint(rollupDay(5))
+	_, err = rollup.RollUp(time.Now().UTC())
+	suite.Require().NoError(err)
+
+	rows := suite.blogDay(rollupDay(5))
+	suite.Require().Len(rows, 1)
+	suite.Equal(1, rows[0].CTAClicks)
+}
+
+// TestRollUpRebuildsReattributedConversionDays tests that changing a
+// touchpoint's attribution rebuilds its lead's conversion day
+func (suite *MetricsRollupIntegrationSuite) TestRollUpRebuildsReattributedConversionDays() {
+	tp := suite.touchpoint(rollupDay(5))
+	suite.age(&models.LeadTouchpoint{}, tp.ID)
+
+	rollup := workers.NewMetricsRollup(time.Minute)
+	_, _, err := rollup.Backfill(rollupDay(10), rollupDay(10))
+	suite.Require().NoError(err)
+	rows := suite.blogDay(rollupDay(10))
+	suite.Require().Len(rows, 1)
+	suite.Equal(0.0, rows[0].Revenue)
+
+	suite.attribute(&tp)
+	_, err = rollup.RollUp(time.Now().UTC())
+	suite.Require().NoError(err)
+
+	rows = suite.blogDay(rollupDay(10))
+	suite.Require().Len(rows, 1)
+	suite.Equal(1, rows[0].Conversions)
+	suite.Equal(100.0, rows[0].Revenue)
+}
+
+// TestBackfillIsIdempotent tests that rebuilding days twice leaves the same rows
+func (suite *MetricsRollupIntegrationSuite) TestBackfillIsIdempotent() {
+	tp := suite.touchpoint(rollupDay(5))
+	suite.attribute(&tp)
+
+	rollup := workers.NewMetricsRollup(time.Minute)
+	days, first, err := rollup.Backfill(rollupDay(1), rollupDay(10))
+	suite.Require().NoError(err)
+	suite.Equal(10, days)
+	before := [][]models.BlogDailyMetrics{suite.blogDay(rollupDay(5)), suite.blogDay(rollupDay(10))}
+
+	_, second, err := rollup.Backfill(rollupDay(1), rollupDay(10))
+	suite.Require().NoError(err)
+	suite.Equal(first, second)
+	suite.Equal(before, [][]models.BlogDailyMetrics{suite.blogDay(rollupDay(5)), suite.blogDay(rollupDay(10))})
+
+	suite.Require().Len(before[0], 1)
+	suite.Require().Len(before[1], 1)
+	suite.Equal(1, before[0][0].CTAClicks)
+	suite.Equal(100.0, before[1][0].Revenue)
+}
+
+// TestMetricsRollupIntegration runs the metrics rollup integration suite
+func TestMetricsRollupIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+	suite.Run(t, new(MetricsRollupIntegrationSuite))
+}
@@ -0,0 +1,206 @@
+package unit
+
+import (
+	"blog-service/pkg/analytics"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAnalyticsBuckets tests bucket starts and series for each granularity
+func TestAnalyticsBuckets(t *testing.T) {
+	thursday := time.Date(2025, 1, 16, 15, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC), analytics.BucketStart(thursday, analytics.GranularityDay))
+	assert.Equal(t, time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), analytics.BucketStart(thursday, analytics.GranularityWeek))
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), analytics.BucketStart(thursday, analytics.GranularityMonth))
+
+	sunday := time.Date(2025, 1, 19, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), analytics.BucketStart(sunday, analytics.GranularityWeek), "weeks start on Monday")
+
+	start := time.Date(2025, 1, 30, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	}, analytics.Buckets(start, end, analytics.GranularityMonth))
+	assert.Len(t, analytics.Buckets(start, end, analytics.GranularityDay), 32)
+	assert.Equal(t, 32, analytics.BucketCount(start, end, analytics.GranularityDay))
+
+	decades := analytics.BucketCount(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), end, analytics.GranularityDay)
+	assert.Equal(t, analytics.MaxBuckets+1, decades, "counting stops past the cap")
+	assert.Len(t, analytics.Buckets(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), end, analytics.GranularityDay), analytics.MaxBuckets)
+}
+
+// TestAnalyticsPeriods tests period parsing, default granularity and the previous window
+func TestAnalyticsPeriods(t *testing.T) {
+	for period, want := range map[string]time.Duration{"24h": 24 * time.Hour, "7d": 7 * 24 * time.Hour, "90d": 90 * 24 * time.Hour} {
+		got, ok := analytics.ParsePeriod(period)
+		assert.True(t, ok, period)
+		assert.Equal(t, want, got, period)
+	}
+	for _, period := range []string{"", "d", "0d", "7w", "-7d", "1.5d", "99999d"} {
+		_, ok := analytics.ParsePeriod(period)
+		assert.False(t, ok, period)
+	}
+
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, analytics.GranularityDay, analytics.DefaultGranularity(end.AddDate(0, 0, -30), end))
+	assert.Equal(t, analytics.GranularityWeek, analytics.DefaultGranularity(end.AddDate(0, 0, -90), end))
+	assert.Equal(t, analytics.GranularityMonth, analytics.DefaultGranularity(end.AddDate(-1, 0, 0), end))
+
+	assert.True(t, analytics.IsValidGranularity("week"))
+	assert.False(t, analytics.IsValidGranularity("year"))
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	previousStart, previousEnd := analytics.PreviousPeriod(start, end)
+	assert.Equal(t, end.Sub(start), previousEnd.Sub(previousStart))
+	assert.True(t, previousEnd.Before(start))
+}
+
+// TestContentInsights tests format inference, length ranges and driver impact
+func TestContentInsights(t *testing.T) {
+	formats := map[string]string{
+		"How to Build Modern Web Applications": analytics.FormatHowTo,
+		"10 Tips for Faster Go Builds":         analytics.FormatListicle,
+		"Top 5 CRM Integrations":               analytics.FormatListicle,
+		"Case Study: Scaling Lead Capture":     analytics.FormatCaseStudy,
+		"MySQL vs PostgreSQL for Analytics":    analytics.FormatComparison,
+		"The Complete Guide to Gin Middleware": analytics.FormatGuide,
+		"Why We Moved to Microservices":        analytics.FormatArticle,
+	}
+	for title, want := range formats {
+		assert.Equal(t, want, analytics.ContentFormat(title), title)
+	}
+
+	assert.Equal(t, "0-500", analytics.LengthRange(0))
+	assert.Equal(t, "500-1000", analytics.LengthRange(500))
+	assert.Equal(t, "1000-2000", analytics.LengthRange(1999))
+	assert.Equal(t, "2000+", analytics.LengthRange(2000))
+	assert.Len(t, analytics.LengthRanges(), 4)
+
+	assert.InDelta(t, 50.0, analytics.Lift(6, 4), 1e-9)
+	assert.InDelta(t, -25.0, analytics.Lift(3, 4), 1e-9)
+	assert.Equal(t, 100.0, analytics.Lift(2, 0))
+	assert.Equal(t, 0.0, analytics.Lift(0, 0))
+
+	assert.Equal(t, analytics.ImpactHigh, analytics.ImpactLevel(50))
+	assert.Equal(t, analytics.ImpactHigh, analytics.ImpactLevel(-30))
+	assert.Equal(t, analytics.ImpactMedium, analytics.ImpactLevel(12))
+	assert.Equal(t, analytics.ImpactLow, analytics.ImpactLevel(5))
+}
+
+// TestTrendSummary tests trend summaries, projections, seasonality and metric grading
+func TestTrendSummary(t *testing.T) {
+	analyzer := analytics.NewTrendAnalyzer()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rising := make([]analytics.TrendDataPoint, 14)
+	for i := range rising {
+		rising[i] = analytics.TrendDataPoint{Date: start.AddDate(0, 0, i), Value: float64(10 + 5*i)}
+	}
+	analysis := analyzer.AnalyzeTrends(rising)
+	direction, strength := analytics.SummarizeTrend(analysis)
+	assert.Equal(t, analytics.TrendUp, direction)
+	assert.Equal(t, analytics.TrendStrong, strength)
+	// Days 14 and 15 on the line 10 + 5x
+	assert.InDelta(t, 80.0+85.0, analyzer.ProjectTotal(analysis, 2), 1e-6)
+
+	direction, _ = analytics.SummarizeTrend(analytics.TrendAnalysis{Status: "insufficient_data"})
+	assert.Equal(t, analytics.TrendStable, direction)
+	assert.Equal(t, 0.0, analyzer.ProjectTotal(analyzer.AnalyzeTrends(rising[:1]), 30))
+
+	falling := analytics.TrendAnalysis{Points: 2, StartDate: start, EndDate: start.AddDate(0, 0, 1), LinearRegression: analytics.LinearRegression{Slope: -5, Intercept: 20}}
+	assert.InDelta(t, 10.0+5.0, analyzer.ProjectTotal(falling, 5), 1e-9, "projections stop at zero")
+
+	assert.Equal(t, 0, analytics.SeasonalityScore(nil))
+	assert.Equal(t, 50, analytics.SeasonalityScore(map[time.Weekday]float64{time.Monday: 150, time.Tuesday: 50}))
+
+	peaks, lows := analytics.PeakAndLowMonths(map[int]float64{1: 100, 6: 140, 12: 60})
+	assert.Equal(t, []string{"June"}, peaks)
+	assert.Equal(t, []string{"December"}, lows)
+	peaks, lows = analytics.PeakAndLowMonths(nil)
+	assert.NotNil(t, peaks)
+	assert.NotNil(t, lows)
+
+	impact, grade := analytics.GradeMetric(80, 50, 25)
+	assert.Equal(t, analytics.ImpactPositive, impact)
+	assert.Equal(t, analytics.ImpactHigh, grade)
+	impact, grade = analytics.GradeMetric(65, 40, 60)
+	assert.Equal(t, analytics.ImpactNegative, impact)
+	assert.Equal(t, analytics.ImpactMedium, grade)
+	impact, grade = analytics.GradeMetric(45, 40, 60)
+	assert.Equal(t, analytics.ImpactNeutral, impact)
+	assert.Equal(t, analytics.ImpactLow, grade)
+}
+
+func TestLeadQuality(t *testing.T) {
+	ranges := analytics.ScoreRanges()
+	assert.Equal(t, []string{"0-19", "20-39", "40-59", "60-79", "80-100"}, ranges)
+	assert.Equal(t, 0, analytics.ScoreRangeIndex(-5))
+	assert.Equal(t, 1, analytics.ScoreRangeIndex(20))
+	assert.Equal(t, 3, analytics.ScoreRangeIndex(79))
+	assert.Equal(t, 4, analytics.ScoreRangeIndex(100))
+
+	assert.Equal(t, analytics.ImpactHigh, analytics.QualityTier(80))
+	assert.Equal(t, analytics.ImpactMedium, analytics.QualityTier(50))
+	assert.Equal(t, analytics.ImpactLow, analytics.QualityTier(49))
+
+	// Scores 20, 40, 60, 80 with the two highest converting
+	assert.InDelta(t, 0.894, analytics.Correlation(4, 200, 2, 140, 12000, 2), 1e-3)
+	assert.InDelta(t, -0.894, analytics.Correlation(4, 200, 2, 60, 12000, 2), 1e-3)
+	assert.Equal(t, 0.0, analytics.Correlation(4, 200, 4, 200, 12000, 4), "constant conversion")
+	assert.Equal(t, 0.0, analytics.Correlation(1, 20, 1, 20, 400, 1))
+}
+
+func TestForecaster(t *testing.T) {
+	forecaster := analytics.NewForecaster()
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC) // a Monday
+	weekly := []float64{10, 12, 12, 10, 8, -20, -22}     // Monday to Sunday
+	series := make([]analytics.TrendDataPoint, 56)
+	for i := range series {
+		series[i] = analytics.TrendDataPoint{Date: start.AddDate(0, 0, i), Value: 40 + 0.5*float64(i) + weekly[i%7]}
+	}
+
+	forecast := forecaster.Forecast(series, 14)
+	assert.Equal(t, analytics.ForecastHoltWinters, forecast.Method)
+	assert.Len(t, forecast.Points, 14)
+	for i, point := range forecast.Points {
+		day := 56 + i
+		assert.Equal(t, start.AddDate(0, 0, day), point.Date)
+		assert.InDelta(t, 40+0.5*float64(day)+weekly[day%7], point.PredictedValue, 1, "day %d", day)
+		assert.LessOrEqual(t, point.LowerBound, point.PredictedValue)
+		if i > 0 {
+			assert.GreaterOrEqual(t, point.ConfidenceInterval, forecast.Points[i-1].ConfidenceInterval, "intervals widen")
+		}
+	}
+	total, lower, upper := forecast.Total(7)
+	assert.InDelta(t, 7*(40+0.5*59)+(10+12+12+10+8-20-22), total, 7)
+	assert.LessOrEqual(t, lower, total)
+	assert.GreaterOrEqual(t, upper, total)
+
+	backtest := forecaster.Backtest(series, 14)
+	assert.Equal(t, 42, backtest.TrainingPoints)
+	assert.Equal(t, 14, backtest.HoldoutPoints)
+	assert.Len(t, backtest.Points, 14)
+	assert.Less(t, backtest.MAPE, 5.0)
+	assert.Less(t, backtest.MAE, 1.0)
+
+	flat := make([]analytics.TrendDataPoint, 10)
+	for i := range flat {
+		flat[i] = analytics.TrendDataPoint{Date: start.AddDate(0, 0, i), Value: 10}
+	}
+	forecast = forecaster.Forecast(flat, 3)
+	assert.Equal(t, analytics.ForecastHolt, forecast.Method)
+	assert.Equal(t, 0.0, forecast.ResidualStdDev)
+	assert.InDelta(t, 10.0, forecast.Points[2].PredictedValue, 1e-9)
+	total, _, _ = forecast.Total(30)
+	assert.InDelta(t, 30.0, total, 1e-9, "totals stop at the forecast's end")
+	assert.Empty(t, forecaster.Forecast(flat, -1).Points)
+
+	forecast = forecaster.Forecast(flat[:1], 30)
+	assert.Equal(t, analytics.ForecastInsufficientData, forecast.Method)
+	total, _, _ = forecast.Total(30)
+	assert.Equal(t, 0.0, total)
+	assert.Equal(t, analytics.ForecastInsufficientData, forecaster.Backtest(flat[:3], 2).Method)
+}
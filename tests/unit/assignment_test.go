@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"blog-service/pkg/assignment"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAssignmentCriteriaMatches tests rule matching on each lead attribute
+func TestAssignmentCriteriaMatches(t *testing.T) {
+	category, minScore, maxScore := uint(4), 60, 90
+	lead := assignment.Lead{
+		CategoryPath:      "/1/4/12/",
+		Country:           "IN",
+		AutoQualification: "warm",
+		Score:             72,
+		UTMCampaign:       "Spring-Launch",
+	}
+
+	testCases := []struct {
+		name     string
+		criteria assignment.Criteria
+		expected bool
+	}{
+		{"empty criteria", assignment.Criteria{}, true},
+		{"parent category", assignment.Criteria{CategoryID: &category}, true},
+		{"other category", assignment.Criteria{CategoryID: uintPtr(2)}, false},
+		{"ancestor category", assignment.Criteria{CategoryID: uintPtr(1)}, true},
+		{"country case", assignment.Criteria{Country: "in"}, true},
+		{"other country", assignment.Criteria{Country: "US"}, false},
+		{"qualification", assignment.Criteria{AutoQualification: "warm"}, true},
+		{"other qualification", assignment.Criteria{AutoQualification: "hot"}, false},
+		{"score in range", assignment.Criteria{MinScore: &minScore, MaxScore: &maxScore}, true},
+		{"score below min", assignment.Criteria{MinScore: &maxScore}, false},
+		{"score above max", assignment.Criteria{MaxScore: &minScore}, false},
+		{"campaign case", assignment.Criteria{UTMCampaign: "spring-launch"}, true},
+		{"all criteria", assignment.Criteria{CategoryID: &category, Country: "IN", AutoQualification: "warm", MinScore: &minScore, UTMCampaign: "Spring-Launch"}, true},
+		{"one criterion fails", assignment.Criteria{CategoryID: &category, Country: "US"}, false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tc.criteria.Matches(lead), tc.name)
+	}
+
+	// Category 12 must not match a lead in category 2 or 120
+	assert.False(t, assignment.Criteria{CategoryID: uintPtr(12)}.Matches(assignment.Lead{CategoryPath: "/1/120/"}))
+	assert.False(t, assignment.Criteria{CategoryID: uintPtr(4)}.Matches(assignment.Lead{}))
+}
+
+// TestNextInPool tests round-robin rotation and skipping of inactive users
+func TestNextInPool(t *testing.T) {
+	pool := []uint{3, 5, 8}
+	allActive := map[uint]bool{3: true, 5: true, 8: true}
+
+	next, ok := assignment.NextInPool(pool, nil, allActive)
+	assert.True(t, ok)
+	assert.Equal(t, uint(3), next)
+
+	next, _ = assignment.NextInPool(pool, uintPtr(3), allActive)
+	assert.Equal(t, uint(5), next)
+
+	next, _ = assignment.NextInPool(pool, uintPtr(8), allActive)
+	assert.Equal(t, uint(3), next, "wraps around")
+
+	next, _ = assignment.NextInPool(pool, uintPtr(3), map[uint]bool{3: true, 8: true})
+	assert.Equal(t, uint(8), next, "skips inactive user")
+
+	next, _ = assignment.NextInPool(pool, uintPtr(42), allActive)
+	assert.Equal(t, uint(3), next, "last user removed from pool")
+
+	next, ok = assignment.NextInPool(pool, uintPtr(5), map[uint]bool{5: true})
+	assert.True(t, ok)
+	assert.Equal(t, uint(5), next, "only active user is picked again")
+
+	_, ok = assignment.NextInPool(pool, nil, map[uint]bool{})
+	assert.False(t, ok)
+
+	_, ok = assignment.NextInPool(nil, nil, allActive)
+	assert.False(t, ok)
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
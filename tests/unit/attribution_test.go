@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"blog-service/pkg/analytics"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAttributionWeights tests how each model splits conversion credit
+func TestAttributionWeights(t *testing.T) {
+	calc := analytics.NewROICalculator()
+	converted := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	touches := []time.Time{
+		converted.AddDate(0, 0, -30),
+		converted.AddDate(0, 0, -20),
+		converted.AddDate(0, 0, -10),
+		converted.AddDate(0, 0, -1),
+	}
+
+	assert.Equal(t, []float64{1, 0, 0, 0}, calc.AttributionWeights(analytics.AttributionFirstTouch, touches, converted))
+	assert.Equal(t, []float64{0, 0, 0, 1}, calc.AttributionWeights(analytics.AttributionLastTouch, touches, converted))
+	assert.Equal(t, []float64{0.25, 0.25, 0.25, 0.25}, calc.AttributionWeights(analytics.AttributionLinear, touches, converted))
+	assert.Equal(t, []float64{0.25, 0.25, 0.25, 0.25}, calc.AttributionWeights("unknown", touches, converted))
+
+	decay := calc.AttributionWeights(analytics.AttributionTimeDecay, touches, converted)
+	for i := 1; i < len(decay); i++ {
+		assert.Greater(t, decay[i], decay[i-1], "recent touches get more credit")
+	}
+	assertSumsToOne(t, decay)
+
+	position := calc.AttributionWeights(analytics.AttributionPositionBased, touches, converted)
+	assert.InDelta(t, 0.4, position[0], 1e-9)
+	assert.InDelta(t, 0.2, position[1], 1e-9)
+	assert.InDelta(t, 0.2, position[2], 1e-9)
+	assert.InDelta(t, 0.2, position[3], 1e-9)
+
+	assert.Equal(t, []float64{1}, calc.AttributionWeights(analytics.AttributionPositionBased, touches[:1], converted))
+	assert.Empty(t, calc.AttributionWeights(analytics.AttributionLinear, nil, converted))
+	for _, model := range analytics.AttributionModels() {
+		assertSumsToOne(t, calc.AttributionWeights(model, touches[:2], converted))
+		assert.True(t, analytics.IsValidAttributionModel(model))
+	}
+	assert.False(t, analytics.IsValidAttributionModel("data_driven"))
+}
+
+func assertSumsToOne(t *testing.T, weights []float64) {
+	t.Helper()
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	assert.InDelta(t, 1, total, 1e-9)
+}
@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"blog-service/pkg/botdetect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const chromeUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+// TestBotClassification tests the user-agent, headless and monitoring signals
+func TestBotClassification(t *testing.T) {
+	classifier, invalid := botdetect.NewClassifier([]string{"127.0.0.1", "10.20.0.0/16", "not-an-ip"}, 0, time.Minute)
+	assert.Equal(t, []string{"not-an-ip"}, invalid)
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		req    botdetect.Request
+		reason string
+		bot    string
+	}{
+		{"browser", botdetect.Request{UserAgent: chromeUA, IP: "203.0.113.5", AcceptLanguage: "en-US"}, "", ""},
+		{"search engine", botdetect.Request{UserAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", IP: "66.249.66.1"}, botdetect.ReasonUserAgent, "Googlebot"},
+		{"link preview", botdetect.Request{UserAgent: "facebookexternalhit/1.1", IP: "203.0.113.6"}, botdetect.ReasonUserAgent, "Facebook"},
+		{"generic crawler", botdetect.Request{UserAgent: "SomeCrawler/3.0", IP: "203.0.113.7"}, botdetect.ReasonUserAgent, "Other bot"},
+		{"http tool", botdetect.Request{UserAgent: "curl/8.4.0", IP: "203.0.113.8"}, botdetect.ReasonUserAgent, "curl"},
+		{"empty user agent", botdetect.Request{IP: "203.0.113.9"}, botdetect.ReasonUserAgent, "Empty user agent"},
+		{"headless chrome", botdetect.Request{UserAgent: "Mozilla/5.0 (X11; Linux x86_64) HeadlessChrome/124.0.0.0", IP: "203.0.113.10", AcceptLanguage: "en"}, botdetect.ReasonHeadless, "HeadlessChrome"},
+		{"headless client hint", botdetect.Request{UserAgent: chromeUA, IP: "203.0.113.11", AcceptLanguage: "en", ClientHintsUA: `"HeadlessChrome";v="124"`}, botdetect.ReasonHeadless, "HeadlessChrome"},
+		{"browser without language", botdetect.Request{UserAgent: chromeUA, IP: "203.0.113.12"}, botdetect.ReasonHeadless, "Unknown automated browser"},
+		{"health check", botdetect.Request{UserAgent: "mejona-health-check/1.0", IP: "198.51.100.1"}, botdetect.ReasonUserAgent, "Health check"},
+		{"monitoring ip", botdetect.Request{UserAgent: chromeUA, IP: "127.0.0.1", AcceptLanguage: "en"}, botdetect.ReasonMonitoringIP, "Monitoring"},
+		{"monitoring network", botdetect.Request{UserAgent: chromeUA, IP: "10.20.3.4", AcceptLanguage: "en"}, botdetect.ReasonMonitoringIP, "Monitoring"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifier.Classify(tt.req, now)
+			assert.Equal(t, tt.reason != "", got.Bot)
+			assert.Equal(t, tt.reason, got.Reason)
+			assert.Equal(t, tt.bot, got.Name)
+		})
+	}
+}
+
+// TestMonitoringOverlaps tests that addresses and networks sharing monitoring addresses are reported
+func TestMonitoringOverlaps(t *testing.T) {
+	classifier, _ := botdetect.NewClassifier([]string{"127.0.0.1", "10.20.0.0/16"}, 0, time.Minute)
+	assert.Equal(t, []string{"127.0.0.0/8", "10.20.5.1", "10.0.0.0/8"},
+		classifier.MonitoringOverlaps([]string{"127.0.0.0/8", "::1", "10.20.5.1", "10.0.0.0/8", "172.21.0.0/16", "not-an-ip", ""}))
+
+	classifier, _ = botdetect.NewClassifier(nil, 0, time.Minute)
+	assert.Empty(t, classifier.MonitoringOverlaps([]string{"127.0.0.1", "::1"}))
+}
+
+// TestBotRateLimit tests that an IP is flagged once over the rate for the rest of the window
+func TestBotRateLimit(t *testing.T) {
+	classifier, _ := botdetect.NewClassifier(nil, 3, time.Minute)
+	req := botdetect.Request{UserAgent: chromeUA, IP: "203.0.113.20", AcceptLanguage: "en"}
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		assert.False(t, classifier.Classify(req, now.Add(time.Duration(i)*time.Second)).Bot)
+	}
+	got := classifier.Classify(req, now.Add(10*time.Second))
+	assert.True(t, got.Bot)
+	assert.Equal(t, botdetect.ReasonRate, got.Reason)
+
+	other := req
+	other.IP = "203.0.113.21"
+	assert.False(t, classifier.Classify(other, now.Add(10*time.Second)).Bot, "rate is per IP")
+
+	assert.False(t, classifier.Classify(req, now.Add(time.Minute)).Bot, "new window")
+}
+
+// TestBotHitLog tests that the hit log drops hits when full and reports them on drain
+func TestBotHitLog(t *testing.T) {
+	hits := botdetect.NewHitLog(2)
+	assert.True(t, hits.Add(botdetect.Hit{Name: "Googlebot"}))
+	assert.True(t, hits.Add(botdetect.Hit{Name: "Bingbot"}))
+	assert.False(t, hits.Add(botdetect.Hit{Name: "curl"}))
+
+	drained, dropped := hits.Drain()
+	assert.Len(t, drained, 2)
+	assert.Equal(t, 1, dropped)
+
+	drained, dropped = hits.Drain()
+	assert.Empty(t, drained)
+	assert.Zero(t, dropped)
+}
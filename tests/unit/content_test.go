@@ -0,0 +1,62 @@
+package unit
+
+import (
+	"blog-service/pkg/content"
+	"blog-service/pkg/seo"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContentParseMarkdown tests Markdown rendering and structure extraction
+func TestContentParseMarkdown(t *testing.T) {
+	raw := "# Growth Guide\n\n" +
+		"Read the [pricing page](/pricing) and our [case study](https://www.mejona.com/case-study).\n\n" +
+		"## Sources\n\n" +
+		"See [Google](https://developers.google.com/search) and <a href=\"https://ads.example.com\" rel=\"sponsored\">this offer</a>.\n\n" +
+		"![Growth chart](https://cdn.mejona.com/img/growth-chart.png \"Quarterly growth\")\n\n" +
+		"<script>alert('x')</script>\n\n" +
+		"[mail us](mailto:hi@mejona.com)"
+
+	doc, err := content.NewParser().Parse(raw, content.Options{SiteURL: "https://mejona.com/blog"})
+	require.NoError(t, err)
+
+	assert.Equal(t, content.FormatMarkdown, doc.Format)
+	assert.NotContains(t, doc.HTML, "<script")
+	assert.Equal(t, []seo.HeadingData{{Level: 1, Text: "Growth Guide"}, {Level: 2, Text: "Sources"}}, doc.Headings)
+
+	require.Len(t, doc.InternalLinks, 2)
+	assert.Equal(t, "/pricing", doc.InternalLinks[0].URL)
+	assert.Equal(t, "case study", doc.InternalLinks[1].AnchorText)
+
+	require.Len(t, doc.ExternalLinks, 2)
+	assert.True(t, doc.ExternalLinks[0].IsDoFollow)
+	assert.False(t, doc.ExternalLinks[1].IsDoFollow)
+
+	require.Len(t, doc.Images, 1)
+	assert.Equal(t, "growth-chart.png", doc.Images[0].FileName)
+	assert.Equal(t, "Growth chart", doc.Images[0].AltText)
+	assert.Equal(t, "Quarterly growth", doc.Images[0].Title)
+
+	data := doc.ContentData(seo.ContentData{Title: "Growth Guide"})
+	assert.Equal(t, doc.Text, data.Content)
+	assert.Len(t, data.ExternalLinks, 2)
+	assert.Greater(t, doc.WordCount, 10)
+}
+
+// TestContentParseHTML tests HTML detection and sanitization
+func TestContentParseHTML(t *testing.T) {
+	raw := `<h2 onclick="steal()">Intro</h2><p>Hello <a href="javascript:alert(1)">bad</a> world</p><img src="/img/a.jpg">`
+
+	doc, err := content.NewParser().Parse(raw, content.Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, content.FormatHTML, doc.Format)
+	assert.NotContains(t, doc.HTML, "onclick")
+	assert.NotContains(t, doc.HTML, "javascript:")
+	assert.Equal(t, "Intro\nHello bad world", doc.Text)
+	assert.Empty(t, doc.InternalLinks)
+	require.Len(t, doc.Images, 1)
+	assert.Empty(t, doc.Images[0].AltText)
+}
@@ -0,0 +1,30 @@
+package unit
+
+import (
+	"blog-service/pkg/database"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsSyntaxError tests recognizing MySQL syntax errors through wrapping
+func TestIsSyntaxError(t *testing.T) {
+	syntax := &mysql.MySQLError{Number: 1064, Message: "syntax error, unexpected '+'"}
+	assert.True(t, database.IsSyntaxError(syntax))
+	assert.True(t, database.IsSyntaxError(fmt.Errorf("search failed: %w", syntax)))
+	assert.False(t, database.IsSyntaxError(&mysql.MySQLError{Number: 1062}))
+	assert.False(t, database.IsSyntaxError(errors.New("syntax error")))
+	assert.False(t, database.IsSyntaxError(nil))
+}
+
+// TestIsDeadlock tests recognizing MySQL deadlocks through wrapping
+func TestIsDeadlock(t *testing.T) {
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+	assert.True(t, database.IsDeadlock(deadlock))
+	assert.True(t, database.IsDeadlock(fmt.Errorf("capture failed: %w", deadlock)))
+	assert.False(t, database.IsDeadlock(&mysql.MySQLError{Number: 1205}))
+	assert.False(t, database.IsDeadlock(nil))
+}
@@ -0,0 +1,82 @@
+package unit
+
+import (
+	"blog-service/pkg/engagement"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEngagementBuffer tests that increments are merged per blog day and drained once
+func TestEngagementBuffer(t *testing.T) {
+	buffer := engagement.NewBuffer(2, 2)
+	day := time.Date(2025, 4, 2, 23, 0, 0, 0, time.UTC)
+
+	assert.True(t, buffer.Add(1, day, engagement.Counts{Views: 1}))
+	assert.True(t, buffer.Add(1, day.Add(30*time.Minute), engagement.Counts{Views: 1, Scroll: [4]int{1, 1, 0, 0}}))
+	assert.True(t, buffer.Add(1, day.Add(2*time.Hour), engagement.Counts{Likes: 1}), "next UTC day")
+
+	select {
+	case <-buffer.Ready():
+	default:
+		t.Fatal("buffer should signal once the threshold is reached")
+	}
+
+	assert.False(t, buffer.Add(2, day, engagement.Counts{Views: 1}), "buffer full")
+
+	pending, dropped := buffer.Drain()
+	assert.Equal(t, 1, dropped)
+	assert.Equal(t, engagement.Counts{Views: 2, Scroll: [4]int{1, 1, 0, 0}}, pending[engagement.Key{BlogID: 1, Day: "2025-04-02"}])
+	assert.Equal(t, engagement.Counts{Likes: 1}, pending[engagement.Key{BlogID: 1, Day: "2025-04-03"}])
+
+	again, dropped := buffer.Drain()
+	assert.Empty(t, again)
+	assert.Zero(t, dropped)
+
+	buffer.Restore(pending)
+	restored, _ := buffer.Drain()
+	assert.Equal(t, pending, restored)
+}
+
+// TestEngagementDeduper tests that keys count once per window
+func TestEngagementDeduper(t *testing.T) {
+	now := time.Date(2025, 4, 2, 12, 0, 0, 0, time.UTC)
+	deduper := engagement.NewDeduper(time.Hour, 2)
+
+	assert.True(t, deduper.First("view|1|a", now))
+	assert.False(t, deduper.First("view|1|a", now.Add(59*time.Minute)))
+	assert.True(t, deduper.First("view|1|a", now.Add(61*time.Minute)), "window expired")
+	assert.True(t, deduper.First("view|1|b", now.Add(61*time.Minute)))
+
+	// Full with nothing expired: the deduper starts over instead of growing
+	assert.True(t, deduper.First("view|1|c", now.Add(62*time.Minute)))
+	assert.True(t, deduper.First("view|1|a", now.Add(63*time.Minute)))
+}
+
+// TestScrollBucket tests mapping scroll depths to milestones
+func TestScrollBucket(t *testing.T) {
+	assert.Equal(t, -1, engagement.ScrollBucket(10))
+	assert.Equal(t, 0, engagement.ScrollBucket(25))
+	assert.Equal(t, 1, engagement.ScrollBucket(60))
+	assert.Equal(t, 3, engagement.ScrollBucket(100))
+}
+
+// TestScrollHeatmap tests milestone shares and the estimated average depth
+func TestScrollHeatmap(t *testing.T) {
+	avg, shares := engagement.ScrollHeatmap(100, [4]int{80, 60, 40, 20})
+	assert.Equal(t, [4]float64{0.8, 0.6, 0.4, 0.2}, shares)
+	// 20 stop at 25%, 20 at 50%, 20 at 75%, 20 reach 100%, 20 below 25%
+	assert.InDelta(t, 50, avg, 1e-9)
+
+	avg, shares = engagement.ScrollHeatmap(0, [4]int{1, 0, 0, 0})
+	assert.Zero(t, avg)
+	assert.Equal(t, [4]float64{}, shares)
+}
+
+// TestBounceRate tests the share of views that never reached the first scroll milestone
+func TestBounceRate(t *testing.T) {
+	assert.InDelta(t, 35.0, engagement.BounceRate(200, 130), 1e-9)
+	assert.Zero(t, engagement.BounceRate(0, 0))
+	assert.Zero(t, engagement.BounceRate(10, 12), "scroll counts above views do not go negative")
+}
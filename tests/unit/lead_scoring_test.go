@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"blog-service/pkg/analytics"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLeadScoreBreakdown tests that the breakdown explains the total score
+func TestLeadScoreBreakdown(t *testing.T) {
+	scorer := analytics.NewLeadScorer()
+	profile := analytics.LeadProfile{
+		Demographics: analytics.Demographics{JobTitle: "VP Engineering", Industry: "technology"},
+		Behavior: analytics.Behavior{
+			PageViews:           6,
+			TotalTimeOnSite:     1200,
+			VisitCount:          3,
+			BlogPostsRead:       3,
+			PricingPagesVisited: true,
+			LastActivity:        time.Now(),
+		},
+		Company: analytics.Company{Name: "Acme", Size: "enterprise"},
+		Intent:  analytics.Intent{SourceType: "contact_form", CTAInteractions: 2, FormCompletions: 1},
+	}
+
+	breakdown := scorer.ScoreBreakdown(profile)
+	assert.Equal(t, scorer.CalculateLeadScore(profile), breakdown.Total)
+
+	weights := analytics.LeadScoreWeights()
+	weighted := breakdown.Demographic*weights["demographic"] +
+		breakdown.Behavioral*weights["behavioral"] +
+		breakdown.Firmographic*weights["firmographic"] +
+		breakdown.Intent*weights["intent"]
+	assert.Equal(t, int(math.Min(weighted, 100)), breakdown.Total)
+	assert.InDelta(t, 1.0, weights["demographic"]+weights["behavioral"]+weights["firmographic"]+weights["intent"], 1e-9)
+
+	assert.Equal(t, "hot", scorer.AutoQualifyLead(85, profile))
+	assert.Equal(t, "warm", scorer.AutoQualifyLead(60, profile))
+	assert.Equal(t, "cold", scorer.AutoQualifyLead(40, profile))
+	assert.Equal(t, "unqualified", scorer.AutoQualifyLead(39, profile))
+}
+
+// TestLeadScoreWithoutVisits tests scoring a profile with no recorded visits
+func TestLeadScoreWithoutVisits(t *testing.T) {
+	breakdown := analytics.NewLeadScorer().ScoreBreakdown(analytics.LeadProfile{
+		Behavior: analytics.Behavior{PageViews: 0, VisitCount: 0},
+	})
+
+	assert.False(t, math.IsNaN(breakdown.Behavioral))
+	assert.GreaterOrEqual(t, breakdown.Total, 0)
+	assert.LessOrEqual(t, breakdown.Total, 100)
+}
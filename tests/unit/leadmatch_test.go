@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"blog-service/pkg/leadmatch"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeEmail tests the duplicate detection key for email addresses
+func TestNormalizeEmail(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"  Jane.Doe@Example.com ", "jane.doe@example.com"},
+		{"jane+newsletter@example.com", "jane@example.com"},
+		{"Jane.Doe+blog@gmail.com", "janedoe@gmail.com"},
+		{"j.a.n.e@googlemail.com", "jane@gmail.com"},
+		{"+tag@example.com", "+tag@example.com"},
+		{"not-an-email", "not-an-email"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, leadmatch.NormalizeEmail(tc.input), tc.input)
+	}
+}
+
+// TestNormalizeCompany tests that legal forms and punctuation are ignored
+func TestNormalizeCompany(t *testing.T) {
+	assert.Equal(t, "acme", leadmatch.NormalizeCompany("ACME, Inc."))
+	assert.Equal(t, "acme", leadmatch.NormalizeCompany("The Acme Co"))
+	assert.Equal(t, "muller technik", leadmatch.NormalizeCompany("Müller Technik GmbH"))
+	assert.Equal(t, "", leadmatch.NormalizeCompany("LLC"))
+	assert.Equal(t, "jose o brien", leadmatch.NormalizeName("  José  O-Brien "))
+}
+
+// TestSimilarity tests Jaro-Winkler similarity bounds and ordering
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, leadmatch.Similarity("martha", "martha"))
+	assert.Equal(t, 0.0, leadmatch.Similarity("", "martha"))
+	assert.InDelta(t, 0.961, leadmatch.Similarity("martha", "marhta"), 0.001)
+	assert.Greater(t, leadmatch.Similarity("jonathan smith", "jonathon smith"), leadmatch.Similarity("jonathan smith", "maria garcia"))
+}
+
+// TestSamePerson tests fuzzy name plus company matching
+func TestSamePerson(t *testing.T) {
+	name, company := leadmatch.DefaultNameThreshold, leadmatch.DefaultCompanyThreshold
+
+	assert.True(t, leadmatch.SamePerson("Jonathan Smith", "Acme Inc.", "jonathan smith", "ACME", name, company))
+	assert.True(t, leadmatch.SamePerson("Jonathon Smith", "Acme Corporation", "Jonathan Smith", "Acme Corp", name, company))
+	assert.False(t, leadmatch.SamePerson("Jonathan Smith", "Acme", "Jonathan Smith", "Globex", name, company))
+	assert.False(t, leadmatch.SamePerson("Jonathan Smith", "Acme", "Maria Garcia", "Acme", name, company))
+	assert.False(t, leadmatch.SamePerson("Jonathan Smith", "", "Jonathan Smith", "", name, company))
+}
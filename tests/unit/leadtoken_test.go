@@ -0,0 +1,54 @@
+package unit
+
+import (
+	"blog-service/pkg/leadtoken"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLeadTokenRoundTrip tests that a signed token resolves to its lead until it expires
+func TestLeadTokenRoundTrip(t *testing.T) {
+	secret := []byte("test-secret")
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	token := leadtoken.Sign(leadtoken.PurposeTracking, 311, now.Add(time.Hour), secret)
+
+	leadID, err := leadtoken.Verify(leadtoken.PurposeTracking, token, secret, now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(311), leadID)
+
+	_, err = leadtoken.Verify(leadtoken.PurposeTracking, token, secret, now.Add(time.Hour))
+	assert.ErrorIs(t, err, leadtoken.ErrExpired)
+}
+
+// TestLeadTokenRejectsTampering tests that altered, foreign and malformed tokens are rejected
+func TestLeadTokenRejectsTampering(t *testing.T) {
+	secret := []byte("test-secret")
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	token := leadtoken.Sign(leadtoken.PurposeTracking, 311, now.Add(time.Hour), secret)
+	parts := strings.Split(token, ".")
+
+	testCases := []struct {
+		name     string
+		purpose  string
+		token    string
+		secret   []byte
+		expected error
+	}{
+		{"other lead", leadtoken.PurposeTracking, "312." + parts[1] + "." + parts[2], secret, leadtoken.ErrInvalidSignature},
+		{"extended expiry", leadtoken.PurposeTracking, parts[0] + ".9999999999." + parts[2], secret, leadtoken.ErrInvalidSignature},
+		{"other secret", leadtoken.PurposeTracking, token, []byte("other"), leadtoken.ErrInvalidSignature},
+		{"other purpose", "opt_out", token, secret, leadtoken.ErrInvalidSignature},
+		{"consent purpose", leadtoken.PurposeConsent, token, secret, leadtoken.ErrInvalidSignature},
+		{"missing part", leadtoken.PurposeTracking, parts[0] + "." + parts[2], secret, leadtoken.ErrMalformed},
+		{"zero lead", leadtoken.PurposeTracking, "0." + parts[1] + "." + parts[2], secret, leadtoken.ErrMalformed},
+		{"empty", leadtoken.PurposeTracking, "", secret, leadtoken.ErrMalformed},
+	}
+
+	for _, tc := range testCases {
+		_, err := leadtoken.Verify(tc.purpose, tc.token, tc.secret, now)
+		assert.ErrorIs(t, err, tc.expected, tc.name)
+	}
+}
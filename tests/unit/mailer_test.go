@@ -0,0 +1,33 @@
+package unit
+
+import (
+	"blog-service/pkg/mailer"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMailerMessage tests the headers and line endings of a plain-text email
+func TestMailerMessage(t *testing.T) {
+	date := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	msg := string(mailer.Message("blog@example.com", "Blog Team", "jane@example.com", "Confirm", "line one\nline two", date))
+
+	assert.Contains(t, msg, "From: Blog Team <blog@example.com>\r\n")
+	assert.Contains(t, msg, "To: jane@example.com\r\n")
+	assert.Contains(t, msg, "Subject: Confirm\r\n")
+	assert.Contains(t, msg, "Date: Sun, 01 Jun 2025 12:00:00 +0000\r\n")
+	assert.True(t, strings.HasSuffix(msg, "\r\n\r\nline one\r\nline two"))
+}
+
+// TestMailerMessageHeaderInjection tests that line breaks cannot add headers
+func TestMailerMessageHeaderInjection(t *testing.T) {
+	msg := string(mailer.Message("blog@example.com", "", "jane@example.com\r\nBcc: x@example.com", "Hi\nBcc: y@example.com", "", time.Now()))
+
+	headers, _, _ := strings.Cut(msg, "\r\n\r\n")
+	for _, line := range strings.Split(headers, "\r\n") {
+		assert.False(t, strings.HasPrefix(line, "Bcc:"), line)
+	}
+	assert.Contains(t, msg, "From: blog@example.com\r\n")
+}
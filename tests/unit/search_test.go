@@ -0,0 +1,37 @@
+package unit
+
+import (
+	"blog-service/pkg/search"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSearchTerms tests query term extraction
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"react", "hooks"}, search.Terms(`+React -a "hooks" react`))
+	assert.Empty(t, search.Terms("a + -"))
+}
+
+// TestSearchHighlight tests escaping and case-insensitive highlighting
+func TestSearchHighlight(t *testing.T) {
+	highlighted := search.Highlight("Market <b>Marketing</b> tips", []string{"market", "marketing"})
+	assert.Equal(t, "<mark>Market</mark> &lt;b&gt;<mark>Marketing</mark>&lt;/b&gt; tips", highlighted)
+	assert.Equal(t, "plain &amp; simple", search.Highlight("plain & simple", nil))
+}
+
+// TestSearchSnippet tests snippet windows around the first match
+func TestSearchSnippet(t *testing.T) {
+	text := strings.Repeat("filler words here ", 30) + "the golang scheduler is cooperative " + strings.Repeat("more trailing text ", 30)
+
+	snippet := search.Snippet(text, []string{"scheduler"}, 80)
+	assert.True(t, strings.HasPrefix(snippet, "…"))
+	assert.True(t, strings.HasSuffix(snippet, "…"))
+	assert.Contains(t, snippet, "<mark>scheduler</mark>")
+
+	short := search.Snippet("Short text", []string{"text"}, 80)
+	assert.Equal(t, "Short <mark>text</mark>", short)
+
+	assert.Equal(t, "Title link and bold", search.PlainText("<h1>Title</h1> [link](http://x.io) and **bold**"))
+}
@@ -0,0 +1,33 @@
+package unit
+
+import (
+	"blog-service/internal/models"
+	"blog-service/pkg/seo"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSEOAnalysisJSONRoundTrip tests storing a full analysis as JSON
+func TestSEOAnalysisJSONRoundTrip(t *testing.T) {
+	analysis := seo.NewSEOAnalyzer().AnalyzeContent(seo.ContentData{
+		ID:              7,
+		Title:           "Lead Generation Strategies for B2B SaaS",
+		MetaDescription: "Proven lead generation strategies for B2B SaaS teams.",
+		Content:         "Lead generation for B2B SaaS starts with content. Good content converts readers into leads.",
+		PrimaryKeyword:  "lead generation",
+	})
+
+	stored := models.SEOAnalysisJSON(analysis)
+	value, err := stored.Value()
+	require.NoError(t, err)
+
+	var loaded models.SEOAnalysisJSON
+	require.NoError(t, loaded.Scan(value))
+	assert.Equal(t, analysis.OverallScore, loaded.OverallScore)
+	assert.Equal(t, uint(7), loaded.ContentID)
+	assert.Equal(t, analysis.TitleAnalysis.LengthScore, loaded.TitleAnalysis.LengthScore)
+
+	assert.Error(t, loaded.Scan(42))
+}
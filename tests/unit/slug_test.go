@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"blog-service/pkg/slug"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSlugMake tests slug generation and transliteration
+func TestSlugMake(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"Advanced React Patterns", "advanced-react-patterns"},
+		{"  Go 1.23: What's New?  ", "go-1-23-what-s-new"},
+		{"Café Déjà Vu", "cafe-deja-vu"},
+		{"Straße & Co", "strasse-and-co"},
+		{"Привет мир", "privet-mir"},
+		{"Øresund Ærø", "oresund-aero"},
+		{"---", slug.Fallback},
+		{"日本語", slug.Fallback},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, slug.Make(tc.input))
+		})
+	}
+}
+
+// TestSlugLength tests that long titles and suffixes stay within the column size
+func TestSlugLength(t *testing.T) {
+	long := slug.Make(strings.Repeat("word ", 100))
+	assert.LessOrEqual(t, len(long), slug.MaxLength)
+	assert.False(t, strings.HasSuffix(long, "-"))
+
+	suffixed := slug.WithSuffix(long, 12)
+	assert.LessOrEqual(t, len(suffixed), slug.MaxLength)
+	assert.True(t, strings.HasSuffix(suffixed, "-12"))
+
+	assert.True(t, slug.IsValid("cafe-deja-vu"))
+	assert.False(t, slug.IsValid("Cafe Deja Vu"))
+}
+
+// TestSlugUnique tests numeric suffixes on collision
+func TestSlugUnique(t *testing.T) {
+	assert.Equal(t, "frontend", slug.Unique("frontend", nil))
+	assert.Equal(t, "frontend-2", slug.Unique("frontend", []string{"frontend"}))
+	assert.Equal(t, "frontend-4", slug.Unique("frontend", []string{"frontend", "frontend-2", "frontend-3"}))
+}
+
+// TestSlugUniqueLong tests that suffixed variants of a full-length slug share one stem
+func TestSlugUniqueLong(t *testing.T) {
+	long := slug.Make(strings.Repeat("word ", 100))
+	stem := slug.Stem(long)
+	assert.Less(t, len(stem), len(long))
+
+	for _, n := range []int{2, 9, 10, 1234567} {
+		suffixed := slug.WithSuffix(long, n)
+		assert.LessOrEqual(t, len(suffixed), slug.MaxLength)
+		assert.True(t, strings.HasPrefix(suffixed, stem+"-"), suffixed)
+	}
+
+	used := []string{long, slug.WithSuffix(long, 2)}
+	assert.Equal(t, slug.WithSuffix(long, 3), slug.Unique(long, used))
+	assert.Equal(t, "frontend", slug.Stem("frontend"))
+}
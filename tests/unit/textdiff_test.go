@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"blog-service/pkg/textdiff"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTextDiff tests line-level diffs between revisions
+func TestTextDiff(t *testing.T) {
+	lines := textdiff.Diff("intro\nold body\noutro", "intro\nnew body\nextra\noutro")
+
+	ops := []string{}
+	for _, line := range lines {
+		ops = append(ops, line.Op)
+	}
+	assert.Equal(t, []string{textdiff.OpEqual, textdiff.OpDelete, textdiff.OpInsert, textdiff.OpInsert, textdiff.OpEqual}, ops)
+
+	added, removed := textdiff.Stats(lines)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 1, removed)
+
+	last := lines[len(lines)-1]
+	assert.Equal(t, 3, last.OldNumber)
+	assert.Equal(t, 4, last.NewNumber)
+}
+
+// TestTextDiffIdenticalAndEmpty tests edge cases
+func TestTextDiffIdenticalAndEmpty(t *testing.T) {
+	same := textdiff.Diff("a\r\nb", "a\nb")
+	added, removed := textdiff.Stats(same)
+	assert.Zero(t, added)
+	assert.Zero(t, removed)
+	assert.Len(t, same, 2)
+
+	fromEmpty := textdiff.Diff("", "line one\nline two")
+	added, removed = textdiff.Stats(fromEmpty)
+	assert.Equal(t, 2, added)
+	assert.Zero(t, removed)
+}
+
+// TestTextDiffLargeRegion tests that regions too large for an LCS table are diffed as a block
+func TestTextDiffLargeRegion(t *testing.T) {
+	oldLines := make([]string, 3000)
+	newLines := make([]string, 3000)
+	for i := range oldLines {
+		oldLines[i] = fmt.Sprintf("old %d", i)
+		newLines[i] = fmt.Sprintf("new %d", i)
+	}
+	lines := textdiff.Diff("title\n"+strings.Join(oldLines, "\n"), "title\n"+strings.Join(newLines, "\n"))
+
+	added, removed := textdiff.Stats(lines)
+	assert.Equal(t, 3000, added)
+	assert.Equal(t, 3000, removed)
+	assert.Equal(t, textdiff.OpEqual, lines[0].Op)
+	assert.Equal(t, textdiff.OpDelete, lines[1].Op)
+	assert.Equal(t, 3001, lines[3000].OldNumber)
+	assert.Equal(t, textdiff.OpInsert, lines[3001].Op)
+	assert.Equal(t, 2, lines[3001].NewNumber)
+}
@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"blog-service/pkg/tracking"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestVisitorIDs tests visitor ID generation and validation
+func TestVisitorIDs(t *testing.T) {
+	id, err := tracking.NewVisitorID()
+	assert.NoError(t, err)
+	assert.True(t, tracking.IsValidVisitorID(id))
+
+	other, _ := tracking.NewVisitorID()
+	assert.NotEqual(t, id, other)
+
+	assert.False(t, tracking.IsValidVisitorID("short"))
+	assert.False(t, tracking.IsValidVisitorID("3f9a1c0e5b7d4e21;drop"))
+	assert.True(t, tracking.IsValidSessionID("s-8f2c"))
+	assert.False(t, tracking.IsValidSessionID(""))
+	assert.False(t, tracking.IsValidSessionID("s 8f2c"))
+}
+
+// TestSummarizeVisitorHistory tests page visits, engagement folding and visit counting
+func TestSummarizeVisitorHistory(t *testing.T) {
+	start := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	events := []tracking.Event{
+		{Type: tracking.EventPageView, URL: "/blog/a", At: start},
+		{Type: tracking.EventEngagement, URL: "/blog/a", At: start.Add(time.Minute), TimeSpent: 60, ScrollDepth: 40, Interactions: 1},
+		{Type: tracking.EventEngagement, URL: "/blog/a", At: start.Add(2 * time.Minute), TimeSpent: 30, ScrollDepth: 90},
+		{Type: tracking.EventPageView, URL: "/blog/b", At: start.Add(3 * time.Minute), TimeSpent: 5},
+		// Engagement for a page not viewed last in the session is only counted in the totals
+		{Type: tracking.EventEngagement, URL: "/blog/a", At: start.Add(4 * time.Minute), TimeSpent: 10},
+		// A day later: a second visit
+		{Type: tracking.EventPageView, URL: "/blog/c", At: start.Add(24 * time.Hour)},
+		// A tagged session is its own visit
+		{Type: tracking.EventPageView, SessionID: "s-1", URL: "/pricing", At: start.Add(25 * time.Hour)},
+		{Type: tracking.EventEngagement, SessionID: "s-1", URL: "/pricing", At: start.Add(25*time.Hour + time.Minute), Interactions: 2},
+	}
+
+	history := tracking.Summarize(events)
+
+	assert.Equal(t, 4, history.PageViews)
+	assert.Equal(t, 105, history.TimeOnSite)
+	assert.Equal(t, 3, history.Sessions)
+	if assert.Len(t, history.Visits, 4) {
+		assert.Equal(t, tracking.PageVisit{Index: 0, TimeSpent: 90, ScrollDepth: 90, Interactions: 1}, history.Visits[0])
+		assert.Equal(t, tracking.PageVisit{Index: 3, TimeSpent: 5}, history.Visits[1])
+		assert.Equal(t, 5, history.Visits[2].Index)
+		assert.Equal(t, tracking.PageVisit{Index: 6, Interactions: 2}, history.Visits[3])
+	}
+
+	empty := tracking.Summarize(nil)
+	assert.Equal(t, 0, empty.Sessions)
+	assert.Empty(t, empty.Visits)
+}
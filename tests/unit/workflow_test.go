@@ -0,0 +1,99 @@
+package unit
+
+import (
+	"blog-service/pkg/workflow"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWorkflowAuthorize tests transition validation against role permissions
+func TestWorkflowAuthorize(t *testing.T) {
+	testCases := []struct {
+		name    string
+		role    string
+		from    string
+		to      string
+		wantErr error
+	}{
+		{"Author submits draft for review", "author", workflow.StatusDraft, workflow.StatusInReview, nil},
+		{"Author cannot publish draft directly", "author", workflow.StatusDraft, workflow.StatusPublished, workflow.ErrInvalidTransition},
+		{"Editor cannot approve without moderation", "editor", workflow.StatusInReview, workflow.StatusApproved, workflow.ErrPermissionDenied},
+		{"Admin approves review", "admin", workflow.StatusInReview, workflow.StatusApproved, nil},
+		{"Editor schedules approved post", "editor", workflow.StatusApproved, workflow.StatusScheduled, nil},
+		{"Author cannot schedule", "author", workflow.StatusApproved, workflow.StatusScheduled, workflow.ErrPermissionDenied},
+		{"Archived cannot jump to published", "admin", workflow.StatusArchived, workflow.StatusPublished, workflow.ErrInvalidTransition},
+		{"Editor archives published post", "editor", workflow.StatusPublished, workflow.StatusArchived, nil},
+		{"Unknown target status", "admin", workflow.StatusDraft, "deleted", workflow.ErrInvalidStatus},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := workflow.Authorize(tc.role, tc.from, tc.to)
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, tc.wantErr), "expected %v, got %v", tc.wantErr, err)
+		})
+	}
+}
+
+// TestWorkflowAvailableTransitions tests that available moves respect permissions
+func TestWorkflowAvailableTransitions(t *testing.T) {
+	authorMoves := workflow.AvailableTransitions("author", workflow.StatusInReview)
+	assert.Empty(t, authorMoves, "authors cannot moderate posts in review")
+
+	adminMoves := workflow.AvailableTransitions("admin", workflow.StatusInReview)
+	targets := []string{}
+	for _, move := range adminMoves {
+		targets = append(targets, move.To)
+	}
+	assert.ElementsMatch(t, []string{workflow.StatusDraft, workflow.StatusApproved}, targets)
+}
+
+// TestLeadWorkflowAuthorize tests the lead pipeline rules
+func TestLeadWorkflowAuthorize(t *testing.T) {
+	testCases := []struct {
+		name    string
+		role    string
+		from    string
+		to      string
+		wantErr error
+	}{
+		{"Manager contacts new lead", "manager", workflow.LeadStatusNew, workflow.LeadStatusContacted, nil},
+		{"Manager converts qualified lead", "manager", workflow.LeadStatusQualified, workflow.LeadStatusConverted, nil},
+		{"New lead cannot convert directly", "manager", workflow.LeadStatusNew, workflow.LeadStatusConverted, workflow.ErrInvalidTransition},
+		{"Same status is not a transition", "admin", workflow.LeadStatusContacted, workflow.LeadStatusContacted, workflow.ErrInvalidTransition},
+		{"Manager cannot reopen converted lead", "manager", workflow.LeadStatusConverted, workflow.LeadStatusQualified, workflow.ErrPermissionDenied},
+		{"Manager cannot reopen lost lead", "manager", workflow.LeadStatusLost, workflow.LeadStatusNurturing, workflow.ErrPermissionDenied},
+		{"Admin reopens lost lead", "admin", workflow.LeadStatusLost, workflow.LeadStatusContacted, nil},
+		{"Converted cannot become lost", "admin", workflow.LeadStatusConverted, workflow.LeadStatusLost, workflow.ErrInvalidTransition},
+		{"Editor cannot update leads", "editor", workflow.LeadStatusNew, workflow.LeadStatusContacted, workflow.ErrPermissionDenied},
+		{"Unknown target status", "admin", workflow.LeadStatusNew, "archived", workflow.ErrInvalidStatus},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := workflow.AuthorizeLead(tc.role, tc.from, tc.to)
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tc.wantErr), "got %v", err)
+			}
+		})
+	}
+}
+
+// TestAvailableLeadTransitions tests that terminal statuses are only reopened by admins
+func TestAvailableLeadTransitions(t *testing.T) {
+	assert.Empty(t, workflow.AvailableLeadTransitions("manager", workflow.LeadStatusConverted))
+
+	reopen := workflow.AvailableLeadTransitions("admin", workflow.LeadStatusConverted)
+	assert.NotEmpty(t, reopen)
+	for _, transition := range reopen {
+		assert.Equal(t, "lead:reopen", transition.Permission)
+		assert.False(t, workflow.IsTerminalLeadStatus(transition.To))
+	}
+}